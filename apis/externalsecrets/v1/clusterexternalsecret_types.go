@@ -59,6 +59,34 @@ type ClusterExternalSecretSpec struct {
 
 	// The time in which the controller should reconcile its objects and recheck namespaces for labels.
 	RefreshInterval *metav1.Duration `json:"refreshTime,omitempty"`
+
+	// NamespaceTemplating renders the string values of externalSecretSpec and externalSecretMetadata
+	// as templates for each namespace, with the namespace available as ".namespace".
+	// If not set, the spec is copied into every namespace as is.
+	// +optional
+	NamespaceTemplating *ClusterExternalSecretNamespaceTemplating `json:"namespaceTemplating,omitempty"`
+}
+
+// ClusterExternalSecretNamespaceTemplating configures the rendering of the ExternalSecrets for each namespace.
+// The delimiters should differ from the ones used in the template of the ExternalSecret target,
+// otherwise the template of the target is rendered as well.
+type ClusterExternalSecretNamespaceTemplating struct {
+	// LeftDelimiter is the left delimiter of the templates.
+	// +kubebuilder:default="[["
+	// +kubebuilder:validation:MinLength:=1
+	// +optional
+	LeftDelimiter string `json:"leftDelimiter,omitempty"`
+
+	// RightDelimiter is the right delimiter of the templates.
+	// +kubebuilder:default="]]"
+	// +kubebuilder:validation:MinLength:=1
+	// +optional
+	RightDelimiter string `json:"rightDelimiter,omitempty"`
+
+	// TargetName is a template of the name of the target Secret, it overrides externalSecretSpec.target.name.
+	// The target name in externalSecretSpec must be a valid name before it is rendered, so it can not be templated.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
 }
 
 // ExternalSecretMetadata defines metadata fields for the ExternalSecret generated by the ClusterExternalSecret.
@@ -96,6 +124,37 @@ type ClusterExternalSecretNamespaceFailure struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// ClusterExternalSecretChildrenStatus aggregates the Ready conditions of the ExternalSecrets
+// created by the ClusterExternalSecret.
+type ClusterExternalSecretChildrenStatus struct {
+	// Ready is the number of ExternalSecrets which are Ready.
+	Ready int32 `json:"ready"`
+
+	// NotReady is the number of ExternalSecrets which are not synced yet or failed to sync.
+	NotReady int32 `json:"notReady"`
+
+	// OldestFailure is the ExternalSecret which has been failing to sync the longest.
+	// +optional
+	OldestFailure *ClusterExternalSecretChildFailure `json:"oldestFailure,omitempty"`
+}
+
+// ClusterExternalSecretChildFailure is an ExternalSecret created by the ClusterExternalSecret which failed to sync.
+type ClusterExternalSecretChildFailure struct {
+	// Namespace is the namespace of the ExternalSecret.
+	Namespace string `json:"namespace"`
+
+	// Reason is the reason of the Ready condition of the ExternalSecret.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the message of the Ready condition of the ExternalSecret.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Since is the time the ExternalSecret started failing.
+	Since metav1.Time `json:"since"`
+}
+
 // ClusterExternalSecretStatus defines the observed state of ClusterExternalSecret.
 type ClusterExternalSecretStatus struct {
 	// ExternalSecretName is the name of the ExternalSecrets created by the ClusterExternalSecret
@@ -109,6 +168,10 @@ type ClusterExternalSecretStatus struct {
 	// +optional
 	ProvisionedNamespaces []string `json:"provisionedNamespaces,omitempty"`
 
+	// Children aggregates the Ready conditions of the ExternalSecrets in the provisioned namespaces.
+	// +optional
+	Children *ClusterExternalSecretChildrenStatus `json:"children,omitempty"`
+
 	// +optional
 	Conditions []ClusterExternalSecretStatusCondition `json:"conditions,omitempty"`
 }
@@ -122,6 +185,8 @@ type ClusterExternalSecretStatus struct {
 // +kubebuilder:printcolumn:name="Store",type=string,JSONPath=`.spec.externalSecretSpec.secretStoreRef.name`
 // +kubebuilder:printcolumn:name="Refresh Interval",type=string,JSONPath=`.spec.refreshTime`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Synced",type=integer,JSONPath=`.status.children.ready`
+// +kubebuilder:printcolumn:name="Not Synced",type=integer,JSONPath=`.status.children.notReady`
 type ClusterExternalSecret struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -34,6 +34,29 @@ type SecretStoreRef struct {
 	// +optional
 	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
 	Kind string `json:"kind,omitempty"`
+
+	// FallbackStoreRefs is an ordered list of stores that are tried
+	// when this store can not serve a request, e.g. because it is not ready
+	// or the provider returned an error.
+	// A secret that does not exist in the provider does not trigger a fallback.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	FallbackStoreRefs []NamedStoreRef `json:"fallbackStoreRefs,omitempty"`
+}
+
+// NamedStoreRef references a SecretStore or ClusterSecretStore by name.
+type NamedStoreRef struct {
+	// Name of the SecretStore resource
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Kind of the SecretStore resource (SecretStore or ClusterSecretStore)
+	// Defaults to `SecretStore`
+	// +optional
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	Kind string `json:"kind,omitempty"`
 }
 
 // ExternalSecretCreationPolicy defines rules on how to create the resulting Secret.
@@ -78,6 +101,19 @@ const (
 	DeletionPolicyRetain ExternalSecretDeletionPolicy = "Retain"
 )
 
+// ExternalSecretErrorPolicy defines how errors fetching individual keys of spec.data are handled.
+// +kubebuilder:validation:Enum=Fail;Partial
+type ExternalSecretErrorPolicy string
+
+const (
+	// ErrorPolicyFail fails the whole sync if any key can not be fetched.
+	ErrorPolicyFail ExternalSecretErrorPolicy = "Fail"
+
+	// ErrorPolicyPartial writes the keys that could be fetched
+	// and keeps the last-known values of the keys that failed.
+	ErrorPolicyPartial ExternalSecretErrorPolicy = "Partial"
+)
+
 // ExternalSecretNullBytePolicy defines how fetched secret data containing NUL bytes should be handled.
 // +kubebuilder:validation:Enum=Ignore;Fail
 type ExternalSecretNullBytePolicy string
@@ -258,6 +294,32 @@ type ExternalSecretTarget struct {
 	// Immutable defines if the final secret will be immutable
 	// +optional
 	Immutable bool `json:"immutable,omitempty"`
+
+	// History keeps snapshots of the last rendered versions of the target Secret,
+	// so it can be rolled back to a previous revision.
+	// It is not supported for generic targets.
+	// +optional
+	History *ExternalSecretHistory `json:"history,omitempty"`
+}
+
+// ExternalSecretHistory defines how many rendered versions of the target Secret are kept.
+// Every revision is stored in a companion Secret owned by the ExternalSecret. The revisions are
+// encrypted with the key of the controller flag --history-encryption-key-file.
+type ExternalSecretHistory struct {
+	// Limit is the number of revisions to keep.
+	// Defaults to 5
+	// +optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	Limit int32 `json:"limit,omitempty"`
+
+	// AllowPlaintext stores the revisions as plaintext copies of the rendered data
+	// if the controller has no history encryption key configured.
+	// Without an encryption key and without this opt-in, no revisions are recorded and the sync fails.
+	// Warning: every revision is another Secret holding the full secret data.
+	// +optional
+	AllowPlaintext bool `json:"allowPlaintext,omitempty"`
 }
 
 // ExternalSecretData defines the connection between the Kubernetes Secret key (spec.data.<key>) and the Provider data.
@@ -521,6 +583,32 @@ const (
 	RefreshPolicyOnChange ExternalSecretRefreshPolicy = "OnChange"
 )
 
+// RefreshJitterMode defines how the delay of a refresh is chosen.
+// +kubebuilder:validation:Enum=Random;Hash
+type RefreshJitterMode string
+
+const (
+	// RefreshJitterModeRandom delays every refresh by a random duration.
+	RefreshJitterModeRandom RefreshJitterMode = "Random"
+	// RefreshJitterModeHash delays every refresh by a fixed duration derived from the UID of the ExternalSecret,
+	// which spreads the refreshes deterministically.
+	RefreshJitterModeHash RefreshJitterMode = "Hash"
+)
+
+// RefreshJitter spreads the periodic refreshes of ExternalSecrets across the refresh interval.
+type RefreshJitter struct {
+	// Percent is the maximum delay of a refresh, as percentage of the refresh interval.
+	// Set to 0 to disable the jitter.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// Mode defines how the delay of a refresh is chosen.
+	// +optional
+	// +kubebuilder:default="Random"
+	Mode RefreshJitterMode `json:"mode,omitempty"`
+}
+
 // ExternalSecretSpec defines the desired state of ExternalSecret.
 type ExternalSecretSpec struct {
 	// +optional
@@ -530,6 +618,13 @@ type ExternalSecretSpec struct {
 	// +optional
 	Target ExternalSecretTarget `json:"target,omitempty"`
 
+	// Targets are additional Secrets or resources rendered from the same provider data as the target,
+	// each with its own name, template and creation/deletion policy.
+	// The name of every additional target is required and must be unique, history is not supported.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	Targets []ExternalSecretTarget `json:"targets,omitempty"`
+
 	// RefreshPolicy determines how the ExternalSecret should be refreshed:
 	// - CreatedOnce: Creates the Secret only if it does not exist and does not update it thereafter
 	// - Periodic: Synchronizes the Secret from the external source at regular intervals specified by refreshInterval.
@@ -546,6 +641,12 @@ type ExternalSecretSpec struct {
 	// +kubebuilder:default="1h0m0s"
 	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
 
+	// RefreshJitter delays the periodic refreshes by up to a percentage of the refresh interval,
+	// so that the refreshes of many ExternalSecrets are spread across the interval.
+	// Overrides the jitter configured for the controller.
+	// +optional
+	RefreshJitter *RefreshJitter `json:"refreshJitter,omitempty"`
+
 	// Data defines the connection between the Kubernetes Secret keys and the Provider data
 	// +optional
 	Data []ExternalSecretData `json:"data,omitempty"`
@@ -554,6 +655,21 @@ type ExternalSecretSpec struct {
 	// If multiple entries are specified, the Secret keys are merged in the specified order
 	// +optional
 	DataFrom []ExternalSecretDataFromRemoteRef `json:"dataFrom,omitempty"`
+
+	// ErrorPolicy defines how errors fetching individual keys of spec.data are handled:
+	// - Fail: the whole sync fails if any key can not be fetched.
+	// - Partial: the keys that could be fetched are written,
+	//   while the keys that failed keep their last-known value.
+	//   It must not be used with target.template, as the last-known values are read from the target Secret.
+	// +optional
+	// +kubebuilder:default="Fail"
+	ErrorPolicy ExternalSecretErrorPolicy `json:"errorPolicy,omitempty"`
+
+	// DryRun fetches and renders the target without writing it.
+	// The difference between the rendered and the current target is recorded in status.preview.
+	// Setting the annotation `external-secrets.io/dry-run: "true"` has the same effect.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // StoreSourceRef allows you to override the SecretStore source
@@ -593,7 +709,7 @@ type GeneratorRef struct {
 	APIVersion string `json:"apiVersion,omitempty"`
 
 	// Specify the Kind of the generator resource
-	// +kubebuilder:validation:Enum=ACRAccessToken;ClusterGenerator;CloudsmithAccessToken;ECRAuthorizationToken;Fake;GCRAccessToken;GithubAccessToken;QuayAccessToken;Password;SSHKey;STSSessionToken;UUID;VaultDynamicSecret;Webhook;Grafana;MFA
+	// +kubebuilder:validation:Enum=ACRAccessToken;ClusterGenerator;CloudsmithAccessToken;ECRAuthorizationToken;Fake;GCRAccessToken;GithubAccessToken;QuayAccessToken;Password;SSHKey;STSSessionToken;UUID;VaultDynamicSecret;Webhook;Grafana;MFA;Certificate;GeneratorChain;DatabaseUser
 	Kind string `json:"kind"`
 
 	// Specify the name of the generator resource
@@ -634,10 +750,14 @@ const (
 	ConditionReasonSecretSynced = "SecretSynced"
 	// ConditionReasonSecretSyncedError indicates that there was an error syncing the secret.
 	ConditionReasonSecretSyncedError = "SecretSyncedError"
+	// ConditionReasonSecretPartiallySynced indicates that the secret was synced, but some keys could not be fetched.
+	ConditionReasonSecretPartiallySynced = "SecretPartiallySynced"
 	// ConditionReasonSecretDeleted indicates that the secret has been deleted.
 	ConditionReasonSecretDeleted = "SecretDeleted"
 	// ConditionReasonSecretMissing indicates that the secret is missing.
 	ConditionReasonSecretMissing = "SecretMissing"
+	// ConditionReasonSecretPreviewed indicates that the secret was rendered in dry-run mode without being written.
+	ConditionReasonSecretPreviewed = "SecretPreviewed"
 
 	// ReasonUpdateFailed indicates that the update operation failed.
 	ReasonUpdateFailed = "UpdateFailed"
@@ -651,6 +771,12 @@ const (
 	ReasonDeleted = "Deleted"
 	// ReasonMissingProviderSecret indicates that the provider secret is missing.
 	ReasonMissingProviderSecret = "MissingProviderSecret"
+	// ReasonRolledBack indicates that the target was pinned to a previous revision.
+	ReasonRolledBack = "RolledBack"
+	// ReasonStoreFallback indicates that a fallback store served the data.
+	ReasonStoreFallback = "StoreFallback"
+	// ReasonPartiallySynced indicates that some keys could not be fetched and kept their last-known value.
+	ReasonPartiallySynced = "PartiallySynced"
 
 	// ConditionReasonResourceSynced indicates that the secrets was synced.
 	ConditionReasonResourceSynced = "ResourceSynced"
@@ -660,6 +786,8 @@ const (
 	ConditionReasonResourceDeleted = "ResourceDeleted"
 	// ConditionReasonResourceMissing indicates that the secret is missing.
 	ConditionReasonResourceMissing = "ResourceMissing"
+	// ConditionReasonResourcePreviewed indicates that the resource was rendered in dry-run mode without being written.
+	ConditionReasonResourcePreviewed = "ResourcePreviewed"
 )
 
 // ExternalSecretStatus defines the observed state of ExternalSecret.
@@ -672,11 +800,188 @@ type ExternalSecretStatus struct {
 	// SyncedResourceVersion keeps track of the last synced version
 	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
 
+	// RefreshJitter is the random delay of the next periodic refresh after refreshTime.
+	// It is chosen once per refresh, so that the schedule does not change between reconciles.
+	// +optional
+	RefreshJitter *metav1.Duration `json:"refreshJitter,omitempty"`
+
+	// GeneratorRenewalTime is the time at which the values of generators with an expiry are regenerated,
+	// after a fraction of their remaining lifetime has passed.
+	// The ExternalSecret is refreshed at this time, even if the refresh interval has not elapsed yet.
+	// +optional
+	GeneratorRenewalTime *metav1.Time `json:"generatorRenewalTime,omitempty"`
+
 	// +optional
 	Conditions []ExternalSecretStatusCondition `json:"conditions,omitempty"`
 
 	// Binding represents a servicebinding.io Provisioned Service reference to the secret
 	Binding corev1.LocalObjectReference `json:"binding,omitempty"`
+
+	// Stores records, for every store referenced by the ExternalSecret,
+	// which store served the data during the last sync.
+	// +optional
+	Stores []ExternalSecretStoreStatus `json:"stores,omitempty"`
+
+	// Keys records the sync state of every key of spec.data.
+	// +optional
+	Keys []ExternalSecretKeyStatus `json:"keys,omitempty"`
+
+	// Preview holds a redacted diff of the rendered target against the current target.
+	// It is only set while the ExternalSecret runs in dry-run mode.
+	// +optional
+	Preview *ExternalSecretPreview `json:"preview,omitempty"`
+
+	// History lists the revisions of the target Secret that are kept for rollbacks.
+	// +optional
+	History *ExternalSecretHistoryStatus `json:"history,omitempty"`
+
+	// Targets lists the resources provisioned for the entries of spec.targets.
+	// Resources of entries that were removed from spec.targets are cleaned up.
+	// +optional
+	Targets []ExternalSecretTargetStatus `json:"targets,omitempty"`
+}
+
+// ExternalSecretTargetStatus identifies a resource provisioned for an entry of spec.targets.
+type ExternalSecretTargetStatus struct {
+	// APIVersion of the resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Name of the resource.
+	Name string `json:"name"`
+
+	// CreationPolicy the resource was provisioned with.
+	// Only resources with creationPolicy=Owner are deleted once the entry is removed.
+	// +optional
+	CreationPolicy ExternalSecretCreationPolicy `json:"creationPolicy,omitempty"`
+}
+
+// ExternalSecretHistoryStatus lists the revisions of the target Secret that are kept.
+type ExternalSecretHistoryStatus struct {
+	// CurrentRevision is the revision the target Secret currently holds.
+	// +optional
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+
+	// PinnedRevision is set while the target Secret is pinned to a previous revision.
+	// +optional
+	PinnedRevision int64 `json:"pinnedRevision,omitempty"`
+
+	// Revisions lists the kept revisions, oldest first.
+	// +optional
+	Revisions []ExternalSecretRevision `json:"revisions,omitempty"`
+}
+
+// ExternalSecretRevision is a rendered version of the target Secret.
+type ExternalSecretRevision struct {
+	// Revision is the sequence number of the revision.
+	Revision int64 `json:"revision"`
+
+	// SecretName is the name of the companion Secret holding the snapshot.
+	SecretName string `json:"secretName"`
+
+	// DataHash is the hash of the rendered data.
+	DataHash string `json:"dataHash"`
+
+	// CreationTimestamp is the time the revision was recorded.
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
+// ExternalSecretPreview is a redacted diff of the rendered target against the current target.
+// It never contains any secret values.
+type ExternalSecretPreview struct {
+	// RenderTime is the time the target was rendered.
+	RenderTime metav1.Time `json:"renderTime,omitempty"`
+
+	// Added lists the keys that are rendered but do not exist in the current target.
+	// +optional
+	Added []string `json:"added,omitempty"`
+
+	// Removed lists the keys that exist in the current target but are not rendered.
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+
+	// Changed lists the keys whose rendered value differs from the current target.
+	// +optional
+	Changed []string `json:"changed,omitempty"`
+
+	// RenderedHash is the hash of the rendered data.
+	// It is empty if the target would be deleted.
+	// +optional
+	RenderedHash string `json:"renderedHash,omitempty"`
+
+	// TargetHash is the hash of the data of the current target.
+	// It is empty if the target does not exist.
+	// +optional
+	TargetHash string `json:"targetHash,omitempty"`
+
+	// Targets holds the redacted diffs of the entries of spec.targets.
+	// +optional
+	Targets []ExternalSecretTargetPreview `json:"targets,omitempty"`
+}
+
+// ExternalSecretTargetPreview is a redacted diff of an entry of spec.targets.
+type ExternalSecretTargetPreview struct {
+	// Kind of the target.
+	Kind string `json:"kind"`
+
+	// Name of the target.
+	Name string `json:"name"`
+
+	// Added lists the keys that are rendered but do not exist in the current target.
+	// +optional
+	Added []string `json:"added,omitempty"`
+
+	// Removed lists the keys that exist in the current target but are not rendered.
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+
+	// Changed lists the keys whose rendered value differs from the current target.
+	// +optional
+	Changed []string `json:"changed,omitempty"`
+
+	// RenderedHash is the hash of the rendered data.
+	// It is empty if the target would be deleted.
+	// +optional
+	RenderedHash string `json:"renderedHash,omitempty"`
+
+	// TargetHash is the hash of the data of the current target.
+	// It is empty if the target does not exist.
+	// +optional
+	TargetHash string `json:"targetHash,omitempty"`
+}
+
+// ExternalSecretKeyStatus records the sync state of a key of spec.data.
+type ExternalSecretKeyStatus struct {
+	// SecretKey is the key in the target Secret.
+	SecretKey string `json:"secretKey"`
+
+	// RemoteKey is the key of the remote secret.
+	RemoteKey string `json:"remoteKey"`
+
+	// Store is the store the key was fetched from.
+	// +optional
+	Store *NamedStoreRef `json:"store,omitempty"`
+
+	// LastSyncTime is the time the key was last fetched successfully.
+	// +optional
+	// +nullable
+	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Error is the error of the last attempt to fetch the key.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ExternalSecretStoreStatus records which store served the data of a referenced store.
+type ExternalSecretStoreStatus struct {
+	// StoreRef is the store referenced by the ExternalSecret.
+	StoreRef NamedStoreRef `json:"storeRef"`
+
+	// ServedBy is the store that served the data.
+	// It differs from StoreRef if one of its fallback stores was used.
+	ServedBy NamedStoreRef `json:"servedBy"`
 }
 
 // ExternalSecret is the Schema for the external-secrets API.
@@ -709,6 +1014,14 @@ const (
 	AnnotationDataHash = "reconcile.external-secrets.io/data-hash"
 	// AnnotationForceSync all ExternalSecrets managed by a ClusterExternalSecret mirror the state and value of this annotation.
 	AnnotationForceSync = "external-secrets.io/force-sync"
+	// AnnotationDryRun when set to "true", the ExternalSecret renders its target without writing it.
+	AnnotationDryRun = "external-secrets.io/dry-run"
+	// AnnotationRollbackRevision pins the target Secret to a revision of its history until the annotation is removed.
+	AnnotationRollbackRevision = "external-secrets.io/rollback-revision"
+	// AnnotationHistoryEncryptionKey is set on encrypted history Secrets, its value identifies the encryption key.
+	AnnotationHistoryEncryptionKey = "reconcile.external-secrets.io/history-encryption-key"
+	// AnnotationRefreshRequested is set by the refresh receiver when a referenced remote key changed, to trigger a refresh.
+	AnnotationRefreshRequested = "external-secrets.io/refresh-requested"
 
 	// LabelManaged all secrets managed by an ExternalSecret will have this label equal to "true".
 	LabelManaged = "reconcile.external-secrets.io/managed"
@@ -718,6 +1031,12 @@ const (
 
 	// LabelOwner points to the owning ExternalSecret resource when CreationPolicy=Owner.
 	LabelOwner = "reconcile.external-secrets.io/created-by"
+
+	// LabelHistoryOf points to the ExternalSecret resource a history Secret belongs to.
+	LabelHistoryOf = "reconcile.external-secrets.io/history-of"
+
+	// LabelHistoryRevision is the revision of the target Secret a history Secret holds.
+	LabelHistoryRevision = "reconcile.external-secrets.io/history-revision"
 )
 
 // +kubebuilder:object:root=true
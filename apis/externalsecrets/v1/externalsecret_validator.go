@@ -55,6 +55,14 @@ func validateExternalSecret(es *ExternalSecret) (admission.Warnings, error) {
 		errs = errors.Join(errs, err)
 	}
 
+	if err := validateTargets(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateErrorPolicy(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	if len(es.Spec.Data) == 0 && len(es.Spec.DataFrom) == 0 {
 		errs = errors.Join(errs, errors.New("either data or dataFrom should be specified"))
 	}
@@ -103,19 +111,70 @@ func validateExtractFindGenerator(ref ExternalSecretDataFromRemoteRef) error {
 }
 
 func validatePolicies(es *ExternalSecret) error {
+	errs := validateTargetPolicies(es.Spec.Target)
+	for _, target := range es.Spec.Targets {
+		if err := validateTargetPolicies(target); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("targets[%s]: %w", target.Name, err))
+		}
+	}
+	return errs
+}
+
+func validateTargetPolicies(target ExternalSecretTarget) error {
 	var errs error
-	if (es.Spec.Target.DeletionPolicy == DeletionPolicyDelete && es.Spec.Target.CreationPolicy == CreatePolicyMerge) ||
-		(es.Spec.Target.DeletionPolicy == DeletionPolicyDelete && es.Spec.Target.CreationPolicy == CreatePolicyNone) {
+	if (target.DeletionPolicy == DeletionPolicyDelete && target.CreationPolicy == CreatePolicyMerge) ||
+		(target.DeletionPolicy == DeletionPolicyDelete && target.CreationPolicy == CreatePolicyNone) {
 		errs = errors.Join(errs, errors.New("deletionPolicy=Delete must not be used when the controller doesn't own the secret. Please set creationPolicy=Owner"))
 	}
 
-	if es.Spec.Target.DeletionPolicy == DeletionPolicyMerge && es.Spec.Target.CreationPolicy == CreatePolicyNone {
+	if target.DeletionPolicy == DeletionPolicyMerge && target.CreationPolicy == CreatePolicyNone {
 		errs = errors.Join(errs, errors.New("deletionPolicy=Merge must not be used with creationPolicy=None. There is no Secret to merge with"))
 	}
 
 	return errs
 }
 
+func validateTargets(es *ExternalSecret) error {
+	var errs error
+	targetName := es.Spec.Target.Name
+	if targetName == "" {
+		targetName = es.Name
+	}
+	seen := map[string]struct{}{targetKey(es.Spec.Target, targetName): {}}
+	for i, target := range es.Spec.Targets {
+		if target.Name == "" {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: name is required", i))
+			continue
+		}
+		key := targetKey(target, target.Name)
+		if _, exists := seen[key]; exists {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: %s is already targeted", i, key))
+		}
+		seen[key] = struct{}{}
+		if target.History != nil {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: history is not supported", i))
+		}
+	}
+	return errs
+}
+
+// validateErrorPolicy ensures that ErrorPolicy=Partial is only used when the last-known values
+// can be read back from the target, which is not the case once they were rendered by a template.
+func validateErrorPolicy(es *ExternalSecret) error {
+	if es.Spec.ErrorPolicy == ErrorPolicyPartial && es.Spec.Target.Template != nil {
+		return errors.New("errorPolicy=Partial must not be used with target.template. The last-known values can not be recovered from a templated Secret")
+	}
+	return nil
+}
+
+// targetKey identifies the resource of a target, as a Secret and a generic target of another kind may share a name.
+func targetKey(target ExternalSecretTarget, name string) string {
+	if target.Manifest != nil {
+		return fmt.Sprintf("%s/%s/%s", target.Manifest.APIVersion, target.Manifest.Kind, name)
+	}
+	return "v1/Secret/" + name
+}
+
 func validateDuplicateKeys(es *ExternalSecret, errs error) error {
 	if es.Spec.Target.DeletionPolicy == DeletionPolicyRetain {
 		seenKeys := make(map[string]struct{})
@@ -18,6 +18,8 @@ package v1
 
 import (
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -202,6 +204,58 @@ either data or dataFrom should be specified`,
 			},
 			expectedErr: "duplicate secretKey found: SERVICE_NAME",
 		},
+		{
+			name: "invalid targets",
+			obj: &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es"},
+				Spec: ExternalSecretSpec{
+					Targets: []ExternalSecretTarget{
+						{},
+						{Name: "es"},
+						{Name: "tls", History: &ExternalSecretHistory{Limit: 1}},
+						{Name: "tls", DeletionPolicy: DeletionPolicyDelete, CreationPolicy: CreatePolicyNone},
+					},
+					Data: []ExternalSecretData{
+						{SecretKey: "SERVICE_NAME"},
+					},
+				},
+			},
+			expectedErr: `targets[tls]: deletionPolicy=Delete must not be used when the controller doesn't own the secret. Please set creationPolicy=Owner
+targets[0]: name is required
+targets[1]: v1/Secret/es is already targeted
+targets[2]: history is not supported
+targets[3]: v1/Secret/tls is already targeted`,
+		},
+		{
+			name: "partial error policy with template",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					ErrorPolicy: ErrorPolicyPartial,
+					Target: ExternalSecretTarget{
+						Template: &ExternalSecretTemplate{Data: map[string]string{"dsn": "{{ .password }}"}},
+					},
+					Data: []ExternalSecretData{
+						{SecretKey: "password"},
+					},
+				},
+			},
+			expectedErr: "errorPolicy=Partial must not be used with target.template. The last-known values can not be recovered from a templated Secret",
+		},
+		{
+			name: "valid targets",
+			obj: &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es"},
+				Spec: ExternalSecretSpec{
+					Targets: []ExternalSecretTarget{
+						{Name: "tls"},
+						{Name: "tls", Manifest: &ManifestReference{APIVersion: "v1", Kind: "ConfigMap"}},
+					},
+					Data: []ExternalSecretData{
+						{SecretKey: "SERVICE_NAME"},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -99,6 +99,38 @@ type SecretsClient interface {
 	Close(ctx context.Context) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretsSoftDeleter is implemented by SecretsClients which can delete a secret
+// so that it can still be recovered in the provider.
+type SecretsSoftDeleter interface {
+	// SoftDeleteSecret will delete the secret from a provider, keeping it recoverable
+	SoftDeleteSecret(ctx context.Context, remoteRef PushSecretRemoteRef) error
+}
+
+// SoftDeleteNotSupportedErr is a sentinel error for when a SecretsClient can not soft delete secrets.
+var SoftDeleteNotSupportedErr = SoftDeleteNotSupportedError{}
+
+// SoftDeleteNotSupportedError shall be returned when a secret should be soft deleted
+// from a provider which does not support it.
+type SoftDeleteNotSupportedError struct{}
+
+func (SoftDeleteNotSupportedError) Error() string {
+	return "provider does not support soft delete"
+}
+
+// SoftDeleteSecret soft deletes the secret with the client, if the client supports it.
+func SoftDeleteSecret(ctx context.Context, secretsClient SecretsClient, remoteRef PushSecretRemoteRef) error {
+	softDeleter, ok := secretsClient.(SecretsSoftDeleter)
+	if !ok {
+		return SoftDeleteNotSupportedErr
+	}
+	return softDeleter.SoftDeleteSecret(ctx, remoteRef)
+}
+
 // NoSecretErr is a sentinel error for when a secret is not found.
 var NoSecretErr = NoSecretError{}
 
@@ -35,6 +35,16 @@ type SecretStoreSpec struct {
 	// +optional
 	RetrySettings *SecretStoreRetrySettings `json:"retrySettings,omitempty"`
 
+	// Used to limit the rate and concurrency of requests to the provider.
+	// The limits apply to the store as a whole, they are shared by all ExternalSecrets and PushSecrets using it.
+	// +optional
+	RateLimit *SecretStoreRateLimit `json:"rateLimit,omitempty"`
+
+	// Used to coalesce and cache the secrets read from the provider.
+	// The cache is shared by all ExternalSecrets using the store, and invalidated when the store changes.
+	// +optional
+	Cache *SecretStoreCache `json:"cache,omitempty"`
+
 	// Used to configure store refresh interval in seconds. Empty or 0 will default to the controller config.
 	// +optional
 	RefreshInterval int `json:"refreshInterval,omitempty"`
@@ -275,6 +285,33 @@ type SecretStoreRetrySettings struct {
 	RetryInterval *string `json:"retryInterval,omitempty"`
 }
 
+// SecretStoreRateLimit defines the client-side rate limit for requests to the provider of a store.
+type SecretStoreRateLimit struct {
+	// QPS is the maximum sustained number of requests per second. 0 means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+
+	// Burst is the maximum number of requests sent at once, before QPS applies. Defaults to QPS.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// MaxInFlight is the maximum number of concurrent requests. 0 means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxInFlight int32 `json:"maxInFlight,omitempty"`
+}
+
+// SecretStoreCache defines the read cache of a store.
+// Concurrent identical reads of the store are coalesced into a single request to the provider.
+type SecretStoreCache struct {
+	// TTL is the duration a secret read from the provider is served from the cache.
+	// If not set, reads are coalesced but not cached.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
 // SecretStoreConditionType represents the condition of the SecretStore.
 type SecretStoreConditionType string
 
@@ -326,6 +363,18 @@ type SecretStoreStatus struct {
 	Conditions []SecretStoreStatusCondition `json:"conditions,omitempty"`
 	// +optional
 	Capabilities SecretStoreCapabilities `json:"capabilities,omitempty"`
+	// +optional
+	RateLimit *SecretStoreRateLimitStatus `json:"rateLimit,omitempty"`
+}
+
+// SecretStoreRateLimitStatus reports the requests throttled by the rate limit of a store.
+type SecretStoreRateLimitStatus struct {
+	// ThrottledRequests is the number of requests delayed by the rate limit since the controller started.
+	ThrottledRequests int64 `json:"throttledRequests"`
+
+	// LastThrottledTime is the time a request was last delayed by the rate limit.
+	// +optional
+	LastThrottledTime *metav1.Time `json:"lastThrottledTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
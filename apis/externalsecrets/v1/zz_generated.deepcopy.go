@@ -868,6 +868,42 @@ func (in *ClusterExternalSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExternalSecretChildFailure) DeepCopyInto(out *ClusterExternalSecretChildFailure) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExternalSecretChildFailure.
+func (in *ClusterExternalSecretChildFailure) DeepCopy() *ClusterExternalSecretChildFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExternalSecretChildFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExternalSecretChildrenStatus) DeepCopyInto(out *ClusterExternalSecretChildrenStatus) {
+	*out = *in
+	if in.OldestFailure != nil {
+		in, out := &in.OldestFailure, &out.OldestFailure
+		*out = new(ClusterExternalSecretChildFailure)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExternalSecretChildrenStatus.
+func (in *ClusterExternalSecretChildrenStatus) DeepCopy() *ClusterExternalSecretChildrenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExternalSecretChildrenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExternalSecretList) DeepCopyInto(out *ClusterExternalSecretList) {
 	*out = *in
@@ -915,6 +951,21 @@ func (in *ClusterExternalSecretNamespaceFailure) DeepCopy() *ClusterExternalSecr
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExternalSecretNamespaceTemplating) DeepCopyInto(out *ClusterExternalSecretNamespaceTemplating) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExternalSecretNamespaceTemplating.
+func (in *ClusterExternalSecretNamespaceTemplating) DeepCopy() *ClusterExternalSecretNamespaceTemplating {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExternalSecretNamespaceTemplating)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExternalSecretSpec) DeepCopyInto(out *ClusterExternalSecretSpec) {
 	*out = *in
@@ -946,6 +997,11 @@ func (in *ClusterExternalSecretSpec) DeepCopyInto(out *ClusterExternalSecretSpec
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.NamespaceTemplating != nil {
+		in, out := &in.NamespaceTemplating, &out.NamespaceTemplating
+		*out = new(ClusterExternalSecretNamespaceTemplating)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExternalSecretSpec.
@@ -971,6 +1027,11 @@ func (in *ClusterExternalSecretStatus) DeepCopyInto(out *ClusterExternalSecretSt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = new(ClusterExternalSecretChildrenStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ClusterExternalSecretStatusCondition, len(*in))
@@ -1511,6 +1572,64 @@ func (in *ExternalSecretFind) DeepCopy() *ExternalSecretFind {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretHistory) DeepCopyInto(out *ExternalSecretHistory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretHistory.
+func (in *ExternalSecretHistory) DeepCopy() *ExternalSecretHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretHistoryStatus) DeepCopyInto(out *ExternalSecretHistoryStatus) {
+	*out = *in
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]ExternalSecretRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretHistoryStatus.
+func (in *ExternalSecretHistoryStatus) DeepCopy() *ExternalSecretHistoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretHistoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretKeyStatus) DeepCopyInto(out *ExternalSecretKeyStatus) {
+	*out = *in
+	if in.Store != nil {
+		in, out := &in.Store, &out.Store
+		*out = new(NamedStoreRef)
+		**out = **in
+	}
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretKeyStatus.
+func (in *ExternalSecretKeyStatus) DeepCopy() *ExternalSecretKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretList) DeepCopyInto(out *ExternalSecretList) {
 	*out = *in
@@ -1572,6 +1691,60 @@ func (in *ExternalSecretMetadata) DeepCopy() *ExternalSecretMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretPreview) DeepCopyInto(out *ExternalSecretPreview) {
+	*out = *in
+	in.RenderTime.DeepCopyInto(&out.RenderTime)
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ExternalSecretTargetPreview, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretPreview.
+func (in *ExternalSecretPreview) DeepCopy() *ExternalSecretPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRevision) DeepCopyInto(out *ExternalSecretRevision) {
+	*out = *in
+	in.CreationTimestamp.DeepCopyInto(&out.CreationTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRevision.
+func (in *ExternalSecretRevision) DeepCopy() *ExternalSecretRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretRewrite) DeepCopyInto(out *ExternalSecretRewrite) {
 	*out = *in
@@ -1655,13 +1828,25 @@ func (in *ExternalSecretRewriteTransform) DeepCopy() *ExternalSecretRewriteTrans
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretSpec) DeepCopyInto(out *ExternalSecretSpec) {
 	*out = *in
-	out.SecretStoreRef = in.SecretStoreRef
+	in.SecretStoreRef.DeepCopyInto(&out.SecretStoreRef)
 	in.Target.DeepCopyInto(&out.Target)
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ExternalSecretTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.RefreshInterval != nil {
 		in, out := &in.RefreshInterval, &out.RefreshInterval
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.RefreshJitter != nil {
+		in, out := &in.RefreshJitter, &out.RefreshJitter
+		*out = new(RefreshJitter)
+		**out = **in
+	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
 		*out = make([]ExternalSecretData, len(*in))
@@ -1692,6 +1877,15 @@ func (in *ExternalSecretSpec) DeepCopy() *ExternalSecretSpec {
 func (in *ExternalSecretStatus) DeepCopyInto(out *ExternalSecretStatus) {
 	*out = *in
 	in.RefreshTime.DeepCopyInto(&out.RefreshTime)
+	if in.RefreshJitter != nil {
+		in, out := &in.RefreshJitter, &out.RefreshJitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GeneratorRenewalTime != nil {
+		in, out := &in.GeneratorRenewalTime, &out.GeneratorRenewalTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ExternalSecretStatusCondition, len(*in))
@@ -1700,6 +1894,33 @@ func (in *ExternalSecretStatus) DeepCopyInto(out *ExternalSecretStatus) {
 		}
 	}
 	out.Binding = in.Binding
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make([]ExternalSecretStoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]ExternalSecretKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(ExternalSecretPreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(ExternalSecretHistoryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ExternalSecretTargetStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStatus.
@@ -1728,6 +1949,23 @@ func (in *ExternalSecretStatusCondition) DeepCopy() *ExternalSecretStatusConditi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretStoreStatus) DeepCopyInto(out *ExternalSecretStoreStatus) {
+	*out = *in
+	out.StoreRef = in.StoreRef
+	out.ServedBy = in.ServedBy
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStoreStatus.
+func (in *ExternalSecretStoreStatus) DeepCopy() *ExternalSecretStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretTarget) DeepCopyInto(out *ExternalSecretTarget) {
 	*out = *in
@@ -1741,6 +1979,11 @@ func (in *ExternalSecretTarget) DeepCopyInto(out *ExternalSecretTarget) {
 		*out = new(ManifestReference)
 		**out = **in
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(ExternalSecretHistory)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTarget.
@@ -1753,6 +1996,51 @@ func (in *ExternalSecretTarget) DeepCopy() *ExternalSecretTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretTargetPreview) DeepCopyInto(out *ExternalSecretTargetPreview) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTargetPreview.
+func (in *ExternalSecretTargetPreview) DeepCopy() *ExternalSecretTargetPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretTargetPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretTargetStatus) DeepCopyInto(out *ExternalSecretTargetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTargetStatus.
+func (in *ExternalSecretTargetStatus) DeepCopy() *ExternalSecretTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretTemplate) DeepCopyInto(out *ExternalSecretTemplate) {
 	*out = *in
@@ -2613,6 +2901,21 @@ func (in *NTLMProtocol) DeepCopy() *NTLMProtocol {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedStoreRef) DeepCopyInto(out *NamedStoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedStoreRef.
+func (in *NamedStoreRef) DeepCopy() *NamedStoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NebiusAuth) DeepCopyInto(out *NebiusAuth) {
 	*out = *in
@@ -3284,6 +3587,21 @@ func (in *PulumiProviderSecretRef) DeepCopy() *PulumiProviderSecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefreshJitter) DeepCopyInto(out *RefreshJitter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshJitter.
+func (in *RefreshJitter) DeepCopy() *RefreshJitter {
+	if in == nil {
+		return nil
+	}
+	out := new(RefreshJitter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScalewayProvider) DeepCopyInto(out *ScalewayProvider) {
 	*out = *in
@@ -3426,6 +3744,26 @@ func (in *SecretStore) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreCache) DeepCopyInto(out *SecretStoreCache) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreCache.
+func (in *SecretStoreCache) DeepCopy() *SecretStoreCache {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreList) DeepCopyInto(out *SecretStoreList) {
 	*out = *in
@@ -3673,9 +4011,48 @@ func (in *SecretStoreProvider) DeepCopy() *SecretStoreProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRateLimit) DeepCopyInto(out *SecretStoreRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRateLimit.
+func (in *SecretStoreRateLimit) DeepCopy() *SecretStoreRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRateLimitStatus) DeepCopyInto(out *SecretStoreRateLimitStatus) {
+	*out = *in
+	if in.LastThrottledTime != nil {
+		in, out := &in.LastThrottledTime, &out.LastThrottledTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRateLimitStatus.
+func (in *SecretStoreRateLimitStatus) DeepCopy() *SecretStoreRateLimitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRateLimitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreRef) DeepCopyInto(out *SecretStoreRef) {
 	*out = *in
+	if in.FallbackStoreRefs != nil {
+		in, out := &in.FallbackStoreRefs, &out.FallbackStoreRefs
+		*out = make([]NamedStoreRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRef.
@@ -3726,6 +4103,16 @@ func (in *SecretStoreSpec) DeepCopyInto(out *SecretStoreSpec) {
 		*out = new(SecretStoreRetrySettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(SecretStoreRateLimit)
+		**out = **in
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(SecretStoreCache)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ClusterSecretStoreCondition, len(*in))
@@ -3755,6 +4142,11 @@ func (in *SecretStoreStatus) DeepCopyInto(out *SecretStoreStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(SecretStoreRateLimitStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreStatus.
@@ -3830,13 +4222,28 @@ func (in *SenhaseguraProvider) DeepCopy() *SenhaseguraProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SoftDeleteNotSupportedError) DeepCopyInto(out *SoftDeleteNotSupportedError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SoftDeleteNotSupportedError.
+func (in *SoftDeleteNotSupportedError) DeepCopy() *SoftDeleteNotSupportedError {
+	if in == nil {
+		return nil
+	}
+	out := new(SoftDeleteNotSupportedError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreGeneratorSourceRef) DeepCopyInto(out *StoreGeneratorSourceRef) {
 	*out = *in
 	if in.SecretStoreRef != nil {
 		in, out := &in.SecretStoreRef, &out.SecretStoreRef
 		*out = new(SecretStoreRef)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.GeneratorRef != nil {
 		in, out := &in.GeneratorRef, &out.GeneratorRef
@@ -3858,7 +4265,7 @@ func (in *StoreGeneratorSourceRef) DeepCopy() *StoreGeneratorSourceRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreSourceRef) DeepCopyInto(out *StoreSourceRef) {
 	*out = *in
-	out.SecretStoreRef = in.SecretStoreRef
+	in.SecretStoreRef.DeepCopyInto(&out.SecretStoreRef)
 	if in.GeneratorRef != nil {
 		in, out := &in.GeneratorRef, &out.GeneratorRef
 		*out = new(GeneratorRef)
@@ -0,0 +1,172 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterSecretSync condition reasons.
+const (
+	// ReasonSourceNotFound indicates that the source Secret of a ClusterSecretSync does not exist.
+	ReasonSourceNotFound = "SourceNotFound"
+)
+
+// ClusterSecretSyncSource defines the Secret that is replicated.
+type ClusterSecretSyncSource struct {
+	// Name of the Secret.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Namespace of the Secret.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=63
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Namespace string `json:"namespace"`
+}
+
+// ClusterSecretSyncTarget defines the Secrets that are created in the target namespaces.
+type ClusterSecretSyncTarget struct {
+	// Name of the Secrets.
+	// Defaults to the name of the source Secret.
+	// +optional
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name,omitempty"`
+
+	// Labels of the Secrets.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations of the Secrets.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ClusterSecretSyncSpec defines the configuration for a ClusterSecretSync resource.
+type ClusterSecretSyncSpec struct {
+	// Source is the Secret that is replicated to the target namespaces.
+	// Changes of the Secret are replicated right away.
+	Source ClusterSecretSyncSource `json:"source"`
+
+	// Target defines the Secrets that are created in the target namespaces.
+	// +optional
+	Target ClusterSecretSyncTarget `json:"target,omitempty"`
+
+	// A list of labels to select by to find the Namespaces to replicate the Secret to. The selectors are ORed.
+	// +optional
+	NamespaceSelectors []*metav1.LabelSelector `json:"namespaceSelectors,omitempty"`
+
+	// Choose namespaces by name. This field is ORed with anything that NamespaceSelectors ends up choosing.
+	// +optional
+	// +kubebuilder:validation:items:MinLength:=1
+	// +kubebuilder:validation:items:MaxLength:=63
+	// +kubebuilder:validation:items:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// The time in which the controller should recheck namespaces for labels.
+	// +kubebuilder:default="1h0m0s"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// ClusterSecretSyncConditionType indicates the condition of the ClusterSecretSync.
+type ClusterSecretSyncConditionType string
+
+const (
+	// ClusterSecretSyncReady indicates the ClusterSecretSync resource is ready.
+	ClusterSecretSyncReady ClusterSecretSyncConditionType = "Ready"
+)
+
+// ClusterSecretSyncStatusCondition indicates the status of the ClusterSecretSync.
+type ClusterSecretSyncStatusCondition struct {
+	Type   ClusterSecretSyncConditionType `json:"type"`
+	Status corev1.ConditionStatus         `json:"status"`
+
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ClusterSecretSyncNamespaceFailure represents a failed namespace deployment and it's reason.
+type ClusterSecretSyncNamespaceFailure struct {
+	// Namespace is the namespace that failed when trying to replicate the Secret
+	Namespace string `json:"namespace"`
+
+	// Reason is why the Secret failed to be replicated to the namespace
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ClusterSecretSyncStatus contains the status information for the ClusterSecretSync resource.
+type ClusterSecretSyncStatus struct {
+	// Failed namespaces are the namespaces that failed to replicate the Secret
+	// +optional
+	FailedNamespaces []ClusterSecretSyncNamespaceFailure `json:"failedNamespaces,omitempty"`
+
+	// ProvisionedNamespaces are the namespaces where the ClusterSecretSync has replicated the Secret
+	// +optional
+	ProvisionedNamespaces []string `json:"provisionedNamespaces,omitempty"`
+
+	// TargetName is the name of the replicated Secrets.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+
+	// SyncedResourceVersion is the resource version of the source Secret that was last replicated.
+	// +optional
+	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
+
+	// +optional
+	Conditions []ClusterSecretSyncStatusCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels="external-secrets.io/component=controller"
+// +kubebuilder:resource:scope=Cluster,categories={external-secrets},shortName=cssync
+
+// ClusterSecretSync is the Schema for the ClusterSecretSyncs API that replicates a Kubernetes Secret
+// to other namespaces, without a round-trip through a provider.
+// Only the namespaces of the cluster the controller runs in are targeted; replicating to remote clusters
+// is not supported, use a PushSecret with the kubernetes provider for that.
+type ClusterSecretSync struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSecretSyncSpec   `json:"spec,omitempty"`
+	Status ClusterSecretSyncStatus `json:"status,omitempty"`
+}
+
+// ClusterSecretSyncList contains a list of ClusterSecretSync resources.
+// +kubebuilder:object:root=true
+type ClusterSecretSyncList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSecretSync `json:"items"`
+}
@@ -32,6 +32,16 @@ const (
 	ReasonErrored = "Errored"
 	// ReasonSourceDeleted indicates that the source Secret was deleted and provider secrets were cleaned up.
 	ReasonSourceDeleted = "SourceDeleted"
+	// ReasonDrifted indicates that secrets were changed in the provider since they were last pushed.
+	ReasonDrifted = "Drifted"
+	// ReasonNotDrifted indicates that no secret was changed in the provider since it was last pushed.
+	ReasonNotDrifted = "NotDrifted"
+	// ReasonRolledBack indicates that the secrets pushed in a reconcile were rolled back after a failure.
+	ReasonRolledBack = "RolledBack"
+	// ReasonRolloutHalted indicates that the rollout of a ClusterPushSecret was halted after a failure.
+	ReasonRolloutHalted = "RolloutHalted"
+	// ReasonRolloutResumed indicates that a halted rollout of a ClusterPushSecret was resumed.
+	ReasonRolloutResumed = "RolloutResumed"
 )
 
 // PushSecretStoreRef contains a reference on how to sync to a SecretStore.
@@ -65,6 +75,21 @@ const (
 	PushSecretUpdatePolicyIfNotExists PushSecretUpdatePolicy = "IfNotExists"
 )
 
+// PushSecretDriftMode defines how secrets changed in the provider since the last push are handled.
+// +kubebuilder:validation:Enum=None;Detect;Fail
+type PushSecretDriftMode string
+
+const (
+	// PushSecretDriftModeNone overwrites secrets that were changed in the provider.
+	PushSecretDriftModeNone PushSecretDriftMode = "None"
+	// PushSecretDriftModeDetect keeps secrets that were changed in the provider and reports the drift,
+	// all other secrets are pushed.
+	PushSecretDriftModeDetect PushSecretDriftMode = "Detect"
+	// PushSecretDriftModeFail fails the PushSecret if a secret was changed in the provider,
+	// nothing is pushed to the store of the drifted secret.
+	PushSecretDriftModeFail PushSecretDriftMode = "Fail"
+)
+
 // PushSecretDeletionPolicy defines how push secrets are deleted in the provider.
 // +kubebuilder:validation:Enum=Delete;None
 type PushSecretDeletionPolicy string
@@ -76,6 +101,18 @@ const (
 	PushSecretDeletionPolicyNone PushSecretDeletionPolicy = "None"
 )
 
+// PushSecretDeletionMode defines how secrets are deleted from the provider.
+// +kubebuilder:validation:Enum=Provider;Soft
+type PushSecretDeletionMode string
+
+const (
+	// PushSecretDeletionModeProvider deletes secrets as configured in the store of the provider.
+	PushSecretDeletionModeProvider PushSecretDeletionMode = "Provider"
+	// PushSecretDeletionModeSoft deletes secrets so they can still be recovered in the provider.
+	// The deletion fails for providers which do not support soft deletes.
+	PushSecretDeletionModeSoft PushSecretDeletionMode = "Soft"
+)
+
 // PushSecretConversionStrategy defines how secret values are converted when pushed to providers.
 // +kubebuilder:validation:Enum=None;ReverseUnicode
 type PushSecretConversionStrategy string
@@ -100,11 +137,36 @@ type PushSecretSpec struct {
 	// +optional
 	UpdatePolicy PushSecretUpdatePolicy `json:"updatePolicy,omitempty"`
 
+	// DriftMode defines how secrets changed in the provider since the last push are handled.
+	// Drift is detected by comparing the remote value with its hash after the last push,
+	// it only applies with UpdatePolicy=Replace.
+	// +kubebuilder:default="None"
+	// +optional
+	DriftMode PushSecretDriftMode `json:"driftMode,omitempty"`
+
+	// Atomic rolls back the secrets pushed in a reconcile if pushing any secret fails,
+	// so every store either holds the new values or its previous values.
+	// Previous values are read before pushing, secrets whose previous value can not be read are not rolled back.
+	// +optional
+	Atomic bool `json:"atomic,omitempty"`
+
 	// Deletion Policy to handle Secrets in the provider.
 	// +kubebuilder:default="None"
 	// +optional
 	DeletionPolicy PushSecretDeletionPolicy `json:"deletionPolicy,omitempty"`
 
+	// DeletionGracePeriod delays the deletion of secrets with DeletionPolicy=Delete.
+	// Secrets are recorded as pending deletions in the status and only deleted once the period expired,
+	// a pending deletion is cancelled if the secret is pushed again before.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+
+	// DeletionMode defines how secrets are deleted with DeletionPolicy=Delete.
+	// Soft keeps deleted secrets recoverable in the provider, e.g. within the recovery window
+	// of AWS Secrets Manager or the retention period of Azure Key Vault.
+	// +optional
+	DeletionMode PushSecretDeletionMode `json:"deletionMode,omitempty"`
+
 	// The Secret Selector (k8s source) for the Push Secret
 	Selector PushSecretSelector `json:"selector"`
 
@@ -147,6 +209,68 @@ type PushSecretSelector struct {
 	// Point to a generator to create a Secret.
 	// +optional
 	GeneratorRef *esv1.GeneratorRef `json:"generatorRef,omitempty"`
+
+	// Select a ConfigMap to Push. All keys of data and binaryData are pushed.
+	// +optional
+	ConfigMap *PushSecretConfigMap `json:"configMap,omitempty"`
+
+	// Select an arbitrary resource to Push, values are extracted with JSONPath expressions or templates.
+	// Warning: Using Generic source. Requires the --unsafe-allow-generic-sources flag.
+	// +optional
+	Resource *PushSecretResource `json:"resource,omitempty"`
+}
+
+// PushSecretConfigMap defines a ConfigMap that will be used as a source for pushing to providers.
+type PushSecretConfigMap struct {
+	// Name of the ConfigMap.
+	// The ConfigMap must exist in the same namespace as the PushSecret manifest.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+}
+
+// PushSecretResource defines an arbitrary namespaced resource that will be used as a source for pushing to providers.
+type PushSecretResource struct {
+	// APIVersion of the resource (e.g., "v1" for ConfigMap, "cert-manager.io/v1" for Certificate)
+	// +kubebuilder:validation:MinLength:=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource (e.g., "ConfigMap", "Certificate")
+	// +kubebuilder:validation:MinLength:=1
+	Kind string `json:"kind"`
+
+	// Name of the resource.
+	// The resource must exist in the same namespace as the PushSecret manifest.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Values defines the keys of the source and how their values are extracted from the resource.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=key
+	Values []PushSecretResourceValue `json:"values"`
+}
+
+// PushSecretResourceValue extracts a value from a resource.
+// +kubebuilder:validation:XValidation:rule="(has(self.jsonPath) && !has(self.template)) || (!has(self.jsonPath) && has(self.template))",message="exactly one of jsonPath or template must be set"
+type PushSecretResourceValue struct {
+	// Key is the key the value is available as, it can be matched with data and dataTo.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[-._a-zA-Z0-9]+$
+	Key string `json:"key"`
+
+	// JSONPath is a kubectl style JSONPath expression selecting the value, e.g. "{.spec.host}".
+	// Values which are not strings are encoded as JSON.
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Template is a Go template rendering the value, the resource is available as ".object".
+	// +optional
+	Template string `json:"template,omitempty"`
 }
 
 // PushSecretRemoteRef defines the location of the secret in the provider.
@@ -257,7 +381,7 @@ type PushSecretDataToMatch struct {
 }
 
 // PushSecretRewrite defines how to transform secret keys before pushing.
-// +kubebuilder:validation:XValidation:rule="(has(self.regexp) && !has(self.transform)) || (!has(self.regexp) && has(self.transform))",message="exactly one of regexp or transform must be set"
+// +kubebuilder:validation:XValidation:rule="[has(self.regexp), has(self.transform), has(self.merge), has(self.split)].filter(x, x).size() == 1",message="exactly one of regexp, transform, merge or split must be set"
 type PushSecretRewrite struct {
 	// Used to rewrite with regular expressions.
 	// +optional
@@ -266,6 +390,41 @@ type PushSecretRewrite struct {
 	// Used to apply string transformation on the secrets.
 	// +optional
 	Transform *esv1.ExternalSecretRewriteTransform `json:"transform,omitempty"`
+
+	// Used to merge keys into a single remote key, which holds a JSON object of the keys and their values.
+	// +optional
+	Merge *PushSecretRewriteMerge `json:"merge,omitempty"`
+
+	// Used to split keys holding a JSON object into a remote key for every property of the object.
+	// +optional
+	Split *PushSecretRewriteSplit `json:"split,omitempty"`
+}
+
+// PushSecretRewriteMerge defines how keys are merged into a single remote key.
+type PushSecretRewriteMerge struct {
+	// Into is the remote key the keys are merged into.
+	// +kubebuilder:validation:MinLength:=1
+	Into string `json:"into"`
+
+	// Match selects the keys which are merged.
+	// If not specified, all keys are merged.
+	// +optional
+	Match *PushSecretDataToMatch `json:"match,omitempty"`
+}
+
+// PushSecretRewriteSplit defines how keys holding a JSON object are split into several remote keys.
+// The remote keys are the key and the property of the object, joined by the separator.
+// String values are pushed as is, other values are pushed as JSON.
+type PushSecretRewriteSplit struct {
+	// Separator joins the key and the property of the object.
+	// +kubebuilder:default="/"
+	// +optional
+	Separator string `json:"separator,omitempty"`
+
+	// Match selects the keys which are split.
+	// If not specified, all keys are split.
+	// +optional
+	Match *PushSecretDataToMatch `json:"match,omitempty"`
 }
 
 // PushSecretConditionType indicates the condition of the PushSecret.
@@ -274,6 +433,8 @@ type PushSecretConditionType string
 const (
 	// PushSecretReady indicates the PushSecret resource is ready.
 	PushSecretReady PushSecretConditionType = "Ready"
+	// PushSecretDrifted indicates secrets were changed in the provider since they were last pushed.
+	PushSecretDrifted PushSecretConditionType = "Drifted"
 )
 
 // PushSecretStatusCondition indicates the status of the PushSecret.
@@ -295,6 +456,25 @@ type PushSecretStatusCondition struct {
 // The outer map's key is the secret store name, and the inner map's key is the remote key name.
 type SyncedPushSecretsMap map[string]map[string]PushSecretData
 
+// SyncedPushSecretHashesMap tracks the hash of the remote value of every synced secret after it was pushed.
+// It is keyed like SyncedPushSecretsMap.
+type SyncedPushSecretHashesMap map[string]map[string]string
+
+// PushSecretPendingDeletion is a secret that is deleted from the provider once the deletion grace period expired.
+type PushSecretPendingDeletion struct {
+	// StoreRef is the store the secret is deleted from, in the form <kind>/<name>.
+	StoreRef string `json:"storeRef"`
+
+	// Key is the key of the secret in the synced push secrets of the store.
+	Key string `json:"key"`
+
+	// Data is the PushSecretData the secret was pushed with.
+	Data PushSecretData `json:"data"`
+
+	// DeleteAfter is the time after which the secret is deleted.
+	DeleteAfter metav1.Time `json:"deleteAfter"`
+}
+
 // PushSecretStatus indicates the history of the status of PushSecret.
 type PushSecretStatus struct {
 	// +nullable
@@ -304,10 +484,25 @@ type PushSecretStatus struct {
 
 	// SyncedResourceVersion keeps track of the last synced version.
 	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
+	// SyncedSourceResourceVersion keeps track of the version of the source ConfigMap
+	// or resource that was last pushed, a change of the source triggers a push.
+	// +optional
+	SyncedSourceResourceVersion string `json:"syncedSourceResourceVersion,omitempty"`
 	// Synced PushSecrets, including secrets that already exist in provider.
 	// Matches secret stores to PushSecretData that was stored to that secret store.
 	// +optional
 	SyncedPushSecrets SyncedPushSecretsMap `json:"syncedPushSecrets,omitempty"`
+	// SyncedPushSecretHashes are the hashes of the remote values after they were last pushed,
+	// they are only recorded if a drift mode is set.
+	// +optional
+	SyncedPushSecretHashes SyncedPushSecretHashesMap `json:"syncedPushSecretHashes,omitempty"`
+	// DriftedSecrets are the secrets which were changed in the provider since they were last pushed,
+	// formatted as "<store>/<remote ref>". A drift is reported once, until it is resolved.
+	// +optional
+	DriftedSecrets []string `json:"driftedSecrets,omitempty"`
+	// PendingDeletions are the secrets which are deleted from the provider once the deletion grace period expired.
+	// +optional
+	PendingDeletions []PushSecretPendingDeletion `json:"pendingDeletions,omitempty"`
 	// +optional
 	Conditions []PushSecretStatusCondition `json:"conditions,omitempty"`
 }
@@ -384,6 +579,97 @@ type ClusterPushSecretSpec struct {
 	// A list of labels to select by to find the Namespaces to create the ExternalSecrets in. The selectors are ORed.
 	// +optional
 	NamespaceSelectors []*metav1.LabelSelector `json:"namespaceSelectors,omitempty"`
+
+	// RolloutStrategy rolls out changes of the PushSecret spec and metadata to the namespaces in batches.
+	// If not set, all PushSecrets are updated at once.
+	// +optional
+	RolloutStrategy *ClusterPushSecretRolloutStrategy `json:"rolloutStrategy,omitempty"`
+}
+
+// ClusterPushSecretRolloutStrategy defines how changes are rolled out to the PushSecrets of a ClusterPushSecret.
+type ClusterPushSecretRolloutStrategy struct {
+	// BatchSize is the number of namespaces whose PushSecret is created or updated at once.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// Pause is the time to wait after a batch was rolled out before the next batch is started.
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+
+	// HealthGate waits for the PushSecrets of a batch to become Ready before the next batch is started.
+	// The rollout is halted if a PushSecret of the batch fails.
+	// +optional
+	HealthGate *ClusterPushSecretHealthGate `json:"healthGate,omitempty"`
+}
+
+// ClusterPushSecretHealthGate defines the health checks of a batch.
+type ClusterPushSecretHealthGate struct {
+	// Timeout is the time the PushSecrets of a batch have to become Ready,
+	// the rollout is halted if they are not Ready in time.
+	// +kubebuilder:default="10m"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// AnnotationRolloutRevision is set on the PushSecrets of a ClusterPushSecret with a rollout strategy,
+// it is the revision of the ClusterPushSecret the PushSecret was last updated to.
+const AnnotationRolloutRevision = "reconcile.external-secrets.io/rollout-revision"
+
+// AnnotationRolloutResume resumes a halted rollout of a ClusterPushSecret whenever its value changes,
+// e.g. after a transient failure of a provider. The current batch gets a new health gate timeout.
+const AnnotationRolloutResume = "reconcile.external-secrets.io/rollout-resume"
+
+// ClusterPushSecretRolloutPhase is the phase of a rollout.
+type ClusterPushSecretRolloutPhase string
+
+const (
+	// ClusterPushSecretRolloutProgressing indicates that PushSecrets are being updated to the revision.
+	ClusterPushSecretRolloutProgressing ClusterPushSecretRolloutPhase = "Progressing"
+	// ClusterPushSecretRolloutComplete indicates that all PushSecrets were updated to the revision.
+	ClusterPushSecretRolloutComplete ClusterPushSecretRolloutPhase = "Complete"
+	// ClusterPushSecretRolloutHalted indicates that the rollout was stopped after a failure.
+	// It is restarted when the PushSecret spec or metadata change,
+	// and resumed when the value of the rollout-resume annotation changes.
+	ClusterPushSecretRolloutHalted ClusterPushSecretRolloutPhase = "Halted"
+)
+
+// ClusterPushSecretRolloutStatus records the progress of a rollout.
+type ClusterPushSecretRolloutStatus struct {
+	// Revision is the hash of the PushSecret spec and metadata that is rolled out.
+	Revision string `json:"revision"`
+
+	// Phase is the phase of the rollout.
+	Phase ClusterPushSecretRolloutPhase `json:"phase"`
+
+	// UpdatedNamespaces is the number of namespaces whose PushSecret was updated to the revision.
+	// +optional
+	UpdatedNamespaces int32 `json:"updatedNamespaces,omitempty"`
+
+	// PendingNamespaces is the number of namespaces whose PushSecret was not yet updated to the revision.
+	// +optional
+	PendingNamespaces int32 `json:"pendingNamespaces,omitempty"`
+
+	// CurrentBatch are the namespaces of the batch that is being rolled out.
+	// +optional
+	CurrentBatch []string `json:"currentBatch,omitempty"`
+
+	// BatchStartTime is the time the current batch was started.
+	// +optional
+	BatchStartTime *metav1.Time `json:"batchStartTime,omitempty"`
+
+	// LastBatchCompletionTime is the time the last batch was completed.
+	// +optional
+	LastBatchCompletionTime *metav1.Time `json:"lastBatchCompletionTime,omitempty"`
+
+	// Message describes why the rollout was halted.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastResume is the value of the rollout-resume annotation the rollout was last resumed with.
+	// +optional
+	LastResume string `json:"lastResume,omitempty"`
 }
 
 // ClusterPushSecretNamespaceFailure represents a failed namespace deployment and it's reason.
@@ -397,6 +683,37 @@ type ClusterPushSecretNamespaceFailure struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// ClusterPushSecretChildrenStatus aggregates the Ready conditions of the PushSecrets
+// created by the ClusterPushSecret.
+type ClusterPushSecretChildrenStatus struct {
+	// Ready is the number of PushSecrets which are Ready.
+	Ready int32 `json:"ready"`
+
+	// NotReady is the number of PushSecrets which are not synced yet or failed to sync.
+	NotReady int32 `json:"notReady"`
+
+	// OldestFailure is the PushSecret which has been failing to sync the longest.
+	// +optional
+	OldestFailure *ClusterPushSecretChildFailure `json:"oldestFailure,omitempty"`
+}
+
+// ClusterPushSecretChildFailure is a PushSecret created by the ClusterPushSecret which failed to sync.
+type ClusterPushSecretChildFailure struct {
+	// Namespace is the namespace of the PushSecret.
+	Namespace string `json:"namespace"`
+
+	// Reason is the reason of the Ready condition of the PushSecret.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the message of the Ready condition of the PushSecret.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Since is the time the PushSecret started failing.
+	Since metav1.Time `json:"since"`
+}
+
 // ClusterPushSecretStatus contains the status information for the ClusterPushSecret resource.
 type ClusterPushSecretStatus struct {
 	// Failed namespaces are the namespaces that failed to apply an PushSecret
@@ -408,6 +725,14 @@ type ClusterPushSecretStatus struct {
 	ProvisionedNamespaces []string `json:"provisionedNamespaces,omitempty"`
 	PushSecretName        string   `json:"pushSecretName,omitempty"`
 
+	// Children aggregates the Ready conditions of the PushSecrets in the provisioned namespaces.
+	// +optional
+	Children *ClusterPushSecretChildrenStatus `json:"children,omitempty"`
+
+	// Rollout is the progress of the rollout, it is only set if a rollout strategy is configured.
+	// +optional
+	Rollout *ClusterPushSecretRolloutStatus `json:"rollout,omitempty"`
+
 	// +optional
 	Conditions []PushSecretStatusCondition `json:"conditions,omitempty"`
 }
@@ -416,6 +741,8 @@ type ClusterPushSecretStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Synced",type=integer,JSONPath=`.status.children.ready`
+// +kubebuilder:printcolumn:name="Not Synced",type=integer,JSONPath=`.status.children.notReady`
 // +kubebuilder:subresource:status
 // +kubebuilder:metadata:labels="external-secrets.io/component=controller"
 // +kubebuilder:resource:scope=Cluster,categories={external-secrets}
@@ -61,7 +61,31 @@ var (
 	ClusterPushSecretGroupVersionKind = SchemeGroupVersion.WithKind(ClusterPushSecretKind)
 )
 
+var (
+	// SecretSyncKind is the kind name used for SecretSync resources.
+	SecretSyncKind = reflect.TypeFor[SecretSync]().Name()
+	// SecretSyncGroupKind is the group/kind used for SecretSync resources.
+	SecretSyncGroupKind = schema.GroupKind{Group: Group, Kind: SecretSyncKind}.String()
+	// SecretSyncKindAPIVersion is the kind/apiVersion used for SecretSync resources.
+	SecretSyncKindAPIVersion = SecretSyncKind + "." + SchemeGroupVersion.String()
+	// SecretSyncGroupVersionKind is the GroupVersionKind for SecretSync resources.
+	SecretSyncGroupVersionKind = SchemeGroupVersion.WithKind(SecretSyncKind)
+)
+
+var (
+	// ClusterSecretSyncKind is the kind name used for ClusterSecretSync resources.
+	ClusterSecretSyncKind = reflect.TypeFor[ClusterSecretSync]().Name()
+	// ClusterSecretSyncGroupKind is the group/kind used for ClusterSecretSync resources.
+	ClusterSecretSyncGroupKind = schema.GroupKind{Group: Group, Kind: ClusterSecretSyncKind}.String()
+	// ClusterSecretSyncKindAPIVersion is the kind/apiVersion used for ClusterSecretSync resources.
+	ClusterSecretSyncKindAPIVersion = ClusterSecretSyncKind + "." + SchemeGroupVersion.String()
+	// ClusterSecretSyncGroupVersionKind is the GroupVersionKind for ClusterSecretSync resources.
+	ClusterSecretSyncGroupVersionKind = SchemeGroupVersion.WithKind(ClusterSecretSyncKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&PushSecret{}, &PushSecretList{})
 	SchemeBuilder.Register(&ClusterPushSecret{}, &ClusterPushSecretList{})
+	SchemeBuilder.Register(&SecretSync{}, &SecretSyncList{})
+	SchemeBuilder.Register(&ClusterSecretSync{}, &ClusterSecretSyncList{})
 }
@@ -0,0 +1,213 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretSync condition reasons.
+const (
+	// ReasonConflict indicates that a value was changed both in the Secret and in the provider since the last sync.
+	ReasonConflict = "Conflict"
+)
+
+// SecretSyncStoreRef defines the SecretStore the Secret is synced with.
+type SecretSyncStoreRef struct {
+	// Name of the SecretStore resource
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Kind of the SecretStore resource (SecretStore or ClusterSecretStore)
+	// +optional
+	// +kubebuilder:default="SecretStore"
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	Kind string `json:"kind,omitempty"`
+}
+
+// SecretSyncTarget defines the Kubernetes Secret that is synced with the provider.
+type SecretSyncTarget struct {
+	// Name of the Secret.
+	// The Secret must be in the same namespace as the SecretSync,
+	// it is created if it does not exist.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+}
+
+// SecretSyncData maps a key of the Secret to a secret in the provider.
+type SecretSyncData struct {
+	// SecretKey is the key of the Secret.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[-._a-zA-Z0-9]+$
+	SecretKey string `json:"secretKey"`
+
+	// RemoteRef is the location of the secret in the provider.
+	RemoteRef PushSecretRemoteRef `json:"remoteRef"`
+}
+
+// GetMetadata returns the metadata of the SecretSyncData, it has none.
+func (d SecretSyncData) GetMetadata() *apiextensionsv1.JSON {
+	return nil
+}
+
+// GetSecretKey returns the key of the Secret.
+func (d SecretSyncData) GetSecretKey() string {
+	return d.SecretKey
+}
+
+// GetRemoteKey returns the remote key of the secret in the provider.
+func (d SecretSyncData) GetRemoteKey() string {
+	return d.RemoteRef.RemoteKey
+}
+
+// GetProperty returns the property of the secret in the provider.
+func (d SecretSyncData) GetProperty() string {
+	return d.RemoteRef.Property
+}
+
+// SecretSyncConflictPolicy defines how values changed on both sides since the last sync are resolved.
+// +kubebuilder:validation:Enum=PreferRemote;PreferLocal;Halt
+type SecretSyncConflictPolicy string
+
+const (
+	// SecretSyncConflictPolicyPreferRemote writes the value of the provider to the Secret.
+	SecretSyncConflictPolicyPreferRemote SecretSyncConflictPolicy = "PreferRemote"
+	// SecretSyncConflictPolicyPreferLocal pushes the value of the Secret to the provider.
+	SecretSyncConflictPolicyPreferLocal SecretSyncConflictPolicy = "PreferLocal"
+	// SecretSyncConflictPolicyHalt keeps both values and stops syncing the key until they are equal again.
+	SecretSyncConflictPolicyHalt SecretSyncConflictPolicy = "Halt"
+)
+
+// SecretSyncSpec configures the behavior of the SecretSync.
+type SecretSyncSpec struct {
+	// The Interval to which External Secrets will try to sync the Secret and the provider.
+	// +kubebuilder:default="1h0m0s"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// SecretStoreRef is the SecretStore the Secret is synced with.
+	SecretStoreRef SecretSyncStoreRef `json:"secretStoreRef"`
+
+	// Target is the Secret that is synced with the provider.
+	Target SecretSyncTarget `json:"target"`
+
+	// Data maps the keys of the Secret to secrets in the provider.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=secretKey
+	Data []SecretSyncData `json:"data"`
+
+	// ConflictPolicy defines how values changed both in the Secret and in the provider
+	// since the last sync are resolved.
+	// +kubebuilder:default="Halt"
+	// +optional
+	ConflictPolicy SecretSyncConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// SecretSyncConditionType indicates the condition of the SecretSync.
+type SecretSyncConditionType string
+
+const (
+	// SecretSyncReady indicates the SecretSync resource is ready.
+	SecretSyncReady SecretSyncConditionType = "Ready"
+)
+
+// SecretSyncStatusCondition indicates the status of the SecretSync.
+type SecretSyncStatusCondition struct {
+	Type   SecretSyncConditionType `json:"type"`
+	Status corev1.ConditionStatus  `json:"status"`
+
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// SecretSyncHashes are the hashes of a value on both sides after it was last synced.
+type SecretSyncHashes struct {
+	// Local is the hash of the value in the Secret.
+	Local string `json:"local"`
+	// Remote is the hash of the value in the provider.
+	Remote string `json:"remote"`
+}
+
+// SecretSyncStatus indicates the history of the status of SecretSync.
+type SecretSyncStatus struct {
+	// +nullable
+	// refreshTime is the time and date the Secret and the provider were last synced.
+	RefreshTime metav1.Time `json:"refreshTime,omitempty"`
+
+	// SyncedResourceVersion keeps track of the last synced version.
+	// +optional
+	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
+
+	// SyncedSecretResourceVersion is the version of the Secret after the last sync,
+	// a change of the Secret triggers a sync.
+	// +optional
+	SyncedSecretResourceVersion string `json:"syncedSecretResourceVersion,omitempty"`
+
+	// SyncedHashes are the hashes of the values after they were last synced, keyed by the key of the Secret.
+	// They are used to find out which side changed since.
+	// +optional
+	SyncedHashes map[string]SecretSyncHashes `json:"syncedHashes,omitempty"`
+
+	// Conflicts are the keys of the Secret whose value was changed on both sides
+	// and which are not synced because of ConflictPolicy=Halt.
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
+
+	// +optional
+	Conditions []SecretSyncStatusCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.refreshTime`
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels="external-secrets.io/component=controller"
+// +kubebuilder:resource:scope=Namespaced,categories={external-secrets},shortName=ssync
+
+// SecretSync is the Schema for the SecretSyncs API that keeps a Kubernetes Secret and secrets
+// in an external provider in sync in both directions.
+type SecretSync struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretSyncSpec   `json:"spec,omitempty"`
+	Status SecretSyncStatus `json:"status,omitempty"`
+}
+
+// SecretSyncList contains a list of SecretSync resources.
+// +kubebuilder:object:root=true
+type SecretSyncList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretSync `json:"items"`
+}
@@ -54,6 +54,42 @@ func (in *ClusterPushSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPushSecretChildFailure) DeepCopyInto(out *ClusterPushSecretChildFailure) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPushSecretChildFailure.
+func (in *ClusterPushSecretChildFailure) DeepCopy() *ClusterPushSecretChildFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPushSecretChildFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPushSecretChildrenStatus) DeepCopyInto(out *ClusterPushSecretChildrenStatus) {
+	*out = *in
+	if in.OldestFailure != nil {
+		in, out := &in.OldestFailure, &out.OldestFailure
+		*out = new(ClusterPushSecretChildFailure)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPushSecretChildrenStatus.
+func (in *ClusterPushSecretChildrenStatus) DeepCopy() *ClusterPushSecretChildrenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPushSecretChildrenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterPushSecretCondition) DeepCopyInto(out *ClusterPushSecretCondition) {
 	*out = *in
@@ -79,6 +115,26 @@ func (in *ClusterPushSecretCondition) DeepCopy() *ClusterPushSecretCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPushSecretHealthGate) DeepCopyInto(out *ClusterPushSecretHealthGate) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPushSecretHealthGate.
+func (in *ClusterPushSecretHealthGate) DeepCopy() *ClusterPushSecretHealthGate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPushSecretHealthGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterPushSecretList) DeepCopyInto(out *ClusterPushSecretList) {
 	*out = *in
@@ -126,6 +182,59 @@ func (in *ClusterPushSecretNamespaceFailure) DeepCopy() *ClusterPushSecretNamesp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPushSecretRolloutStatus) DeepCopyInto(out *ClusterPushSecretRolloutStatus) {
+	*out = *in
+	if in.CurrentBatch != nil {
+		in, out := &in.CurrentBatch, &out.CurrentBatch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BatchStartTime != nil {
+		in, out := &in.BatchStartTime, &out.BatchStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBatchCompletionTime != nil {
+		in, out := &in.LastBatchCompletionTime, &out.LastBatchCompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPushSecretRolloutStatus.
+func (in *ClusterPushSecretRolloutStatus) DeepCopy() *ClusterPushSecretRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPushSecretRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPushSecretRolloutStrategy) DeepCopyInto(out *ClusterPushSecretRolloutStrategy) {
+	*out = *in
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HealthGate != nil {
+		in, out := &in.HealthGate, &out.HealthGate
+		*out = new(ClusterPushSecretHealthGate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPushSecretRolloutStrategy.
+func (in *ClusterPushSecretRolloutStrategy) DeepCopy() *ClusterPushSecretRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPushSecretRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterPushSecretSpec) DeepCopyInto(out *ClusterPushSecretSpec) {
 	*out = *in
@@ -147,6 +256,11 @@ func (in *ClusterPushSecretSpec) DeepCopyInto(out *ClusterPushSecretSpec) {
 			}
 		}
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(ClusterPushSecretRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPushSecretSpec.
@@ -172,6 +286,16 @@ func (in *ClusterPushSecretStatus) DeepCopyInto(out *ClusterPushSecretStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = new(ClusterPushSecretChildrenStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ClusterPushSecretRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]PushSecretStatusCondition, len(*in))
@@ -191,6 +315,210 @@ func (in *ClusterPushSecretStatus) DeepCopy() *ClusterPushSecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSync) DeepCopyInto(out *ClusterSecretSync) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSync.
+func (in *ClusterSecretSync) DeepCopy() *ClusterSecretSync {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSecretSync) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncList) DeepCopyInto(out *ClusterSecretSyncList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSecretSync, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncList.
+func (in *ClusterSecretSyncList) DeepCopy() *ClusterSecretSyncList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSecretSyncList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncNamespaceFailure) DeepCopyInto(out *ClusterSecretSyncNamespaceFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncNamespaceFailure.
+func (in *ClusterSecretSyncNamespaceFailure) DeepCopy() *ClusterSecretSyncNamespaceFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncNamespaceFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncSource) DeepCopyInto(out *ClusterSecretSyncSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncSource.
+func (in *ClusterSecretSyncSource) DeepCopy() *ClusterSecretSyncSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncSpec) DeepCopyInto(out *ClusterSecretSyncSpec) {
+	*out = *in
+	out.Source = in.Source
+	in.Target.DeepCopyInto(&out.Target)
+	if in.NamespaceSelectors != nil {
+		in, out := &in.NamespaceSelectors, &out.NamespaceSelectors
+		*out = make([]*v1.LabelSelector, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(v1.LabelSelector)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncSpec.
+func (in *ClusterSecretSyncSpec) DeepCopy() *ClusterSecretSyncSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncStatus) DeepCopyInto(out *ClusterSecretSyncStatus) {
+	*out = *in
+	if in.FailedNamespaces != nil {
+		in, out := &in.FailedNamespaces, &out.FailedNamespaces
+		*out = make([]ClusterSecretSyncNamespaceFailure, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProvisionedNamespaces != nil {
+		in, out := &in.ProvisionedNamespaces, &out.ProvisionedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterSecretSyncStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncStatus.
+func (in *ClusterSecretSyncStatus) DeepCopy() *ClusterSecretSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncStatusCondition) DeepCopyInto(out *ClusterSecretSyncStatusCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncStatusCondition.
+func (in *ClusterSecretSyncStatusCondition) DeepCopy() *ClusterSecretSyncStatusCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncStatusCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretSyncTarget) DeepCopyInto(out *ClusterSecretSyncTarget) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretSyncTarget.
+func (in *ClusterSecretSyncTarget) DeepCopy() *ClusterSecretSyncTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretSyncTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecret) DeepCopyInto(out *PushSecret) {
 	*out = *in
@@ -218,6 +546,21 @@ func (in *PushSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretConfigMap) DeepCopyInto(out *PushSecretConfigMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretConfigMap.
+func (in *PushSecretConfigMap) DeepCopy() *PushSecretConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretData) DeepCopyInto(out *PushSecretData) {
 	*out = *in
@@ -369,16 +712,68 @@ func (in *PushSecretMetadata) DeepCopy() *PushSecretMetadata {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PushSecretRemoteRef) DeepCopyInto(out *PushSecretRemoteRef) {
+func (in *PushSecretPendingDeletion) DeepCopyInto(out *PushSecretPendingDeletion) {
 	*out = *in
+	in.Data.DeepCopyInto(&out.Data)
+	in.DeleteAfter.DeepCopyInto(&out.DeleteAfter)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretRemoteRef.
-func (in *PushSecretRemoteRef) DeepCopy() *PushSecretRemoteRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretPendingDeletion.
+func (in *PushSecretPendingDeletion) DeepCopy() *PushSecretPendingDeletion {
 	if in == nil {
 		return nil
 	}
-	out := new(PushSecretRemoteRef)
+	out := new(PushSecretPendingDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretRemoteRef) DeepCopyInto(out *PushSecretRemoteRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretRemoteRef.
+func (in *PushSecretRemoteRef) DeepCopy() *PushSecretRemoteRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretRemoteRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretResource) DeepCopyInto(out *PushSecretResource) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]PushSecretResourceValue, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretResource.
+func (in *PushSecretResource) DeepCopy() *PushSecretResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretResourceValue) DeepCopyInto(out *PushSecretResourceValue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretResourceValue.
+func (in *PushSecretResourceValue) DeepCopy() *PushSecretResourceValue {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretResourceValue)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -396,6 +791,16 @@ func (in *PushSecretRewrite) DeepCopyInto(out *PushSecretRewrite) {
 		*out = new(externalsecretsv1.ExternalSecretRewriteTransform)
 		**out = **in
 	}
+	if in.Merge != nil {
+		in, out := &in.Merge, &out.Merge
+		*out = new(PushSecretRewriteMerge)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = new(PushSecretRewriteSplit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretRewrite.
@@ -408,6 +813,46 @@ func (in *PushSecretRewrite) DeepCopy() *PushSecretRewrite {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretRewriteMerge) DeepCopyInto(out *PushSecretRewriteMerge) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(PushSecretDataToMatch)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretRewriteMerge.
+func (in *PushSecretRewriteMerge) DeepCopy() *PushSecretRewriteMerge {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretRewriteMerge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretRewriteSplit) DeepCopyInto(out *PushSecretRewriteSplit) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(PushSecretDataToMatch)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretRewriteSplit.
+func (in *PushSecretRewriteSplit) DeepCopy() *PushSecretRewriteSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretRewriteSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretSecret) DeepCopyInto(out *PushSecretSecret) {
 	*out = *in
@@ -441,6 +886,16 @@ func (in *PushSecretSelector) DeepCopyInto(out *PushSecretSelector) {
 		*out = new(externalsecretsv1.GeneratorRef)
 		**out = **in
 	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(PushSecretConfigMap)
+		**out = **in
+	}
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(PushSecretResource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretSelector.
@@ -468,6 +923,11 @@ func (in *PushSecretSpec) DeepCopyInto(out *PushSecretSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
@@ -522,6 +982,36 @@ func (in *PushSecretStatus) DeepCopyInto(out *PushSecretStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.SyncedPushSecretHashes != nil {
+		in, out := &in.SyncedPushSecretHashes, &out.SyncedPushSecretHashes
+		*out = make(SyncedPushSecretHashesMap, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.DriftedSecrets != nil {
+		in, out := &in.DriftedSecrets, &out.DriftedSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingDeletions != nil {
+		in, out := &in.PendingDeletions, &out.PendingDeletions
+		*out = make([]PushSecretPendingDeletion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]PushSecretStatusCondition, len(*in))
@@ -577,6 +1067,236 @@ func (in *PushSecretStoreRef) DeepCopy() *PushSecretStoreRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSync) DeepCopyInto(out *SecretSync) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSync.
+func (in *SecretSync) DeepCopy() *SecretSync {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretSync) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncData) DeepCopyInto(out *SecretSyncData) {
+	*out = *in
+	out.RemoteRef = in.RemoteRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncData.
+func (in *SecretSyncData) DeepCopy() *SecretSyncData {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncHashes) DeepCopyInto(out *SecretSyncHashes) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncHashes.
+func (in *SecretSyncHashes) DeepCopy() *SecretSyncHashes {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncHashes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncList) DeepCopyInto(out *SecretSyncList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretSync, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncList.
+func (in *SecretSyncList) DeepCopy() *SecretSyncList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretSyncList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncSpec) DeepCopyInto(out *SecretSyncSpec) {
+	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	out.SecretStoreRef = in.SecretStoreRef
+	out.Target = in.Target
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]SecretSyncData, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncSpec.
+func (in *SecretSyncSpec) DeepCopy() *SecretSyncSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncStatus) DeepCopyInto(out *SecretSyncStatus) {
+	*out = *in
+	in.RefreshTime.DeepCopyInto(&out.RefreshTime)
+	if in.SyncedHashes != nil {
+		in, out := &in.SyncedHashes, &out.SyncedHashes
+		*out = make(map[string]SecretSyncHashes, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]SecretSyncStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncStatus.
+func (in *SecretSyncStatus) DeepCopy() *SecretSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncStatusCondition) DeepCopyInto(out *SecretSyncStatusCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncStatusCondition.
+func (in *SecretSyncStatusCondition) DeepCopy() *SecretSyncStatusCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncStatusCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncStoreRef) DeepCopyInto(out *SecretSyncStoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncStoreRef.
+func (in *SecretSyncStoreRef) DeepCopy() *SecretSyncStoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretSyncTarget) DeepCopyInto(out *SecretSyncTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSyncTarget.
+func (in *SecretSyncTarget) DeepCopy() *SecretSyncTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretSyncTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in SyncedPushSecretHashesMap) DeepCopyInto(out *SyncedPushSecretHashesMap) {
+	{
+		in := &in
+		*out = make(SyncedPushSecretHashesMap, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncedPushSecretHashesMap.
+func (in SyncedPushSecretHashesMap) DeepCopy() SyncedPushSecretHashesMap {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncedPushSecretHashesMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in SyncedPushSecretsMap) DeepCopyInto(out *SyncedPushSecretsMap) {
 	{
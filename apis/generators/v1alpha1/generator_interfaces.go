@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"time"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -53,5 +54,36 @@ type Generator interface {
 	) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// ExpiringGenerator is an optional interface for generators whose output expires, e.g. short-lived credentials.
+// The controllers use the expiry to regenerate the output before it expires.
+type ExpiringGenerator interface {
+	Generator
+
+	// GenerateWithExpiry behaves like Generate and additionally returns the time
+	// at which the generated output expires.
+	// A zero time means that the output does not expire.
+	GenerateWithExpiry(
+		ctx context.Context,
+		obj *apiextensions.JSON,
+		kube client.Client,
+		namespace string,
+	) (map[string][]byte, GeneratorProviderState, time.Time, error)
+}
+
+// GenerateWithExpiry generates the output of a generator along with its expiry.
+// The expiry is zero if the generator does not implement ExpiringGenerator.
+func GenerateWithExpiry(ctx context.Context, gen Generator, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, GeneratorProviderState, time.Time, error) {
+	if expiring, ok := gen.(ExpiringGenerator); ok {
+		return expiring.GenerateWithExpiry(ctx, obj, kube, namespace)
+	}
+	data, state, err := gen.Generate(ctx, obj, kube, namespace)
+	return data, state, time.Time{}, err
+}
+
 // GeneratorProviderState represents the state of a generator provider that can be stored and retrieved.
 type GeneratorProviderState *apiextensions.JSON
@@ -56,7 +56,14 @@ type GeneratorStateSpec struct {
 	// be blocked by a finalizer.
 	Resource *apiextensions.JSON `json:"resource"`
 	// State is the state that was produced by the generator implementation.
-	State *apiextensions.JSON `json:"state"`
+	// It is empty if the generator does not keep a state and the GeneratorState only records the expiry.
+	// +optional
+	State *apiextensions.JSON `json:"state,omitempty"`
+
+	// ExpiresAt is the time at which the generated output expires.
+	// It is only set for generators that report an expiry.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // GeneratorStateConditionType represents the type of condition for a generator state.
@@ -99,6 +106,7 @@ type GeneratorStateStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:metadata:labels="external-secrets.io/component=controller"
 // +kubebuilder:printcolumn:name="GC Deadline",type="string",JSONPath=".spec.garbageCollectionDeadline"
+// +kubebuilder:printcolumn:name="Expires At",type="string",JSONPath=".spec.expiresAt"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={external-secrets, external-secrets-generators},shortName=gs
 type GeneratorState struct {
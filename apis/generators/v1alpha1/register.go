@@ -73,6 +73,12 @@ var (
 	ClusterGeneratorKind = reflect.TypeFor[ClusterGenerator]().Name()
 	// CloudsmithAccessTokenKind is the kind name for CloudsmithAccessToken resource.
 	CloudsmithAccessTokenKind = reflect.TypeFor[CloudsmithAccessToken]().Name()
+	// CertificateKind is the kind name for Certificate resource.
+	CertificateKind = reflect.TypeFor[Certificate]().Name()
+	// GeneratorChainKind is the kind name for GeneratorChain resource.
+	GeneratorChainKind = reflect.TypeFor[GeneratorChain]().Name()
+	// DatabaseUserKind is the kind name for DatabaseUser resource.
+	DatabaseUserKind = reflect.TypeFor[DatabaseUser]().Name()
 )
 
 func init() {
@@ -109,4 +115,7 @@ func init() {
 	SchemeBuilder.Register(&Webhook{}, &WebhookList{})
 	SchemeBuilder.Register(&Grafana{}, &GrafanaList{})
 	SchemeBuilder.Register(&MFA{}, &MFAList{})
+	SchemeBuilder.Register(&Certificate{}, &CertificateList{})
+	SchemeBuilder.Register(&GeneratorChain{}, &GeneratorChainList{})
+	SchemeBuilder.Register(&DatabaseUser{}, &DatabaseUserList{})
 }
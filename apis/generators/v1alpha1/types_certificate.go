@@ -0,0 +1,228 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// CertificateSpec controls the behavior of the certificate generator.
+type CertificateSpec struct {
+	// CommonName of the certificate subject.
+	// +optional
+	CommonName string `json:"commonName,omitempty"`
+
+	// Subject configures the distinguished name of the certificate besides the common name.
+	// +optional
+	Subject *CertificateSubject `json:"subject,omitempty"`
+
+	// DNSNames is a list of DNS subject alternative names.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IPAddresses is a list of IP address subject alternative names.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// URIs is a list of URI subject alternative names.
+	// +optional
+	URIs []string `json:"uris,omitempty"`
+
+	// EmailAddresses is a list of email subject alternative names.
+	// +optional
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
+
+	// Duration is the validity period of the certificate.
+	// Defaults to 90 days.
+	// +kubebuilder:default="2160h"
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// IsCA marks the certificate as a certificate authority.
+	// CertSign and CRLSign are added to the key usages.
+	// +optional
+	IsCA bool `json:"isCA,omitempty"`
+
+	// PrivateKey configures the key pair of the certificate.
+	// +optional
+	PrivateKey CertificatePrivateKey `json:"privateKey,omitempty"`
+
+	// Usages is the list of key usages and extended key usages of the certificate.
+	// Defaults to DigitalSignature, KeyEncipherment, ServerAuth and ClientAuth.
+	// +optional
+	Usages []CertificateKeyUsage `json:"usages,omitempty"`
+
+	// CA references the certificate authority which signs the certificate.
+	// If omitted, the certificate is self-signed.
+	// +optional
+	CA *CertificateCA `json:"ca,omitempty"`
+
+	// Keystores configures additional PKCS#12 and JKS output.
+	// +optional
+	Keystores *CertificateKeystores `json:"keystores,omitempty"`
+}
+
+// CertificateSubject defines the distinguished name of a certificate.
+type CertificateSubject struct {
+	// +optional
+	Organizations []string `json:"organizations,omitempty"`
+	// +optional
+	OrganizationalUnits []string `json:"organizationalUnits,omitempty"`
+	// +optional
+	Countries []string `json:"countries,omitempty"`
+	// +optional
+	Localities []string `json:"localities,omitempty"`
+	// +optional
+	Provinces []string `json:"provinces,omitempty"`
+}
+
+// CertificateKeyAlgorithm is the algorithm of the private key.
+// +kubebuilder:validation:Enum=RSA;ECDSA;Ed25519
+type CertificateKeyAlgorithm string
+
+const (
+	// CertificateKeyAlgorithmRSA generates an RSA key.
+	CertificateKeyAlgorithmRSA CertificateKeyAlgorithm = "RSA"
+	// CertificateKeyAlgorithmECDSA generates an ECDSA key.
+	CertificateKeyAlgorithmECDSA CertificateKeyAlgorithm = "ECDSA"
+	// CertificateKeyAlgorithmEd25519 generates an Ed25519 key.
+	CertificateKeyAlgorithmEd25519 CertificateKeyAlgorithm = "Ed25519"
+)
+
+// CertificatePrivateKey configures the key pair of a certificate.
+type CertificatePrivateKey struct {
+	// Algorithm of the private key.
+	// +kubebuilder:default="RSA"
+	// +optional
+	Algorithm CertificateKeyAlgorithm `json:"algorithm,omitempty"`
+
+	// Size of the private key. For RSA the number of bits (2048, 3072 or 4096, defaults to 2048),
+	// for ECDSA the curve size (256, 384 or 521, defaults to 256). Ignored for Ed25519.
+	// +optional
+	Size int `json:"size,omitempty"`
+}
+
+// CertificateKeyUsage is a key usage or extended key usage of a certificate.
+// +kubebuilder:validation:Enum=DigitalSignature;ContentCommitment;KeyEncipherment;DataEncipherment;KeyAgreement;CertSign;CRLSign;ServerAuth;ClientAuth;CodeSigning;EmailProtection;TimeStamping;OCSPSigning
+type CertificateKeyUsage string
+
+// Key usages and extended key usages supported by the certificate generator.
+const (
+	CertificateKeyUsageDigitalSignature  CertificateKeyUsage = "DigitalSignature"
+	CertificateKeyUsageContentCommitment CertificateKeyUsage = "ContentCommitment"
+	CertificateKeyUsageKeyEncipherment   CertificateKeyUsage = "KeyEncipherment"
+	CertificateKeyUsageDataEncipherment  CertificateKeyUsage = "DataEncipherment"
+	CertificateKeyUsageKeyAgreement      CertificateKeyUsage = "KeyAgreement"
+	CertificateKeyUsageCertSign          CertificateKeyUsage = "CertSign"
+	CertificateKeyUsageCRLSign           CertificateKeyUsage = "CRLSign"
+	CertificateKeyUsageServerAuth        CertificateKeyUsage = "ServerAuth"
+	CertificateKeyUsageClientAuth        CertificateKeyUsage = "ClientAuth"
+	CertificateKeyUsageCodeSigning       CertificateKeyUsage = "CodeSigning"
+	CertificateKeyUsageEmailProtection   CertificateKeyUsage = "EmailProtection"
+	CertificateKeyUsageTimeStamping      CertificateKeyUsage = "TimeStamping"
+	CertificateKeyUsageOCSPSigning       CertificateKeyUsage = "OCSPSigning"
+)
+
+// CertificateCA references a Secret in the namespace of the generator which holds
+// the PEM encoded certificate and private key of a certificate authority.
+type CertificateCA struct {
+	// SecretName is the name of the Secret holding the CA key pair.
+	// +kubebuilder:validation:MinLength:=1
+	SecretName string `json:"secretName"`
+
+	// CertificateKey is the key of the Secret holding the CA certificate.
+	// +kubebuilder:default="tls.crt"
+	// +optional
+	CertificateKey string `json:"certificateKey,omitempty"`
+
+	// PrivateKeyKey is the key of the Secret holding the CA private key.
+	// +kubebuilder:default="tls.key"
+	// +optional
+	PrivateKeyKey string `json:"privateKeyKey,omitempty"`
+}
+
+// CertificateKeystores configures additional keystore output of the certificate generator.
+type CertificateKeystores struct {
+	// PKCS12 adds keystore.p12 and truststore.p12 to the output.
+	// +optional
+	PKCS12 *CertificatePKCS12Keystore `json:"pkcs12,omitempty"`
+
+	// JKS adds keystore.jks and truststore.jks to the output.
+	// +optional
+	JKS *CertificateJKSKeystore `json:"jks,omitempty"`
+}
+
+// CertificatePKCS12Profile is the encryption profile of a PKCS#12 keystore.
+// +kubebuilder:validation:Enum=LegacyRC2;LegacyDES;Modern2023
+type CertificatePKCS12Profile string
+
+const (
+	// CertificatePKCS12ProfileLegacyRC2 uses RC2 and 3DES, compatible with most legacy software.
+	CertificatePKCS12ProfileLegacyRC2 CertificatePKCS12Profile = "LegacyRC2"
+	// CertificatePKCS12ProfileLegacyDES uses 3DES.
+	CertificatePKCS12ProfileLegacyDES CertificatePKCS12Profile = "LegacyDES"
+	// CertificatePKCS12ProfileModern2023 uses PBES2 with AES-256-CBC and SHA-256.
+	CertificatePKCS12ProfileModern2023 CertificatePKCS12Profile = "Modern2023"
+)
+
+// CertificatePKCS12Keystore configures the PKCS#12 output.
+type CertificatePKCS12Keystore struct {
+	// PasswordSecretRef references the password which protects the keystores.
+	// The Secret must be in the namespace of the generator.
+	PasswordSecretRef esmeta.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Profile is the encryption profile of the keystores.
+	// +kubebuilder:default="Modern2023"
+	// +optional
+	Profile CertificatePKCS12Profile `json:"profile,omitempty"`
+}
+
+// CertificateJKSKeystore configures the JKS output.
+type CertificateJKSKeystore struct {
+	// PasswordSecretRef references the password which protects the keystores.
+	// The Secret must be in the namespace of the generator.
+	PasswordSecretRef esmeta.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Alias of the key entry in the keystore.
+	// +kubebuilder:default="certificate"
+	// +optional
+	Alias string `json:"alias,omitempty"`
+}
+
+// Certificate generates X.509 certificates, self-signed or signed by a CA from a Secret.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels="external-secrets.io/component=controller"
+// +kubebuilder:resource:scope=Namespaced,categories={external-secrets, external-secrets-generators}
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CertificateList contains a list of Certificate resources.
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Certificate `json:"items"`
+}
@@ -30,7 +30,7 @@ type ClusterGeneratorSpec struct {
 }
 
 // GeneratorKind represents a kind of generator.
-// +kubebuilder:validation:Enum=ACRAccessToken;CloudsmithAccessToken;ECRAuthorizationToken;Fake;GCRAccessToken;GithubAccessToken;QuayAccessToken;Password;SSHKey;STSSessionToken;UUID;VaultDynamicSecret;Webhook;Grafana
+// +kubebuilder:validation:Enum=ACRAccessToken;CloudsmithAccessToken;ECRAuthorizationToken;Fake;GCRAccessToken;GithubAccessToken;QuayAccessToken;Password;SSHKey;STSSessionToken;UUID;VaultDynamicSecret;Webhook;Grafana;Certificate;GeneratorChain;DatabaseUser
 type GeneratorKind string
 
 const (
@@ -64,6 +64,12 @@ const (
 	GeneratorKindMFA GeneratorKind = "MFA"
 	// GeneratorKindCloudsmithAccessToken represents a Cloudsmith access token generator.
 	GeneratorKindCloudsmithAccessToken GeneratorKind = "CloudsmithAccessToken"
+	// GeneratorKindCertificate represents a certificate generator.
+	GeneratorKindCertificate GeneratorKind = "Certificate"
+	// GeneratorKindGeneratorChain represents a generator which chains other generators.
+	GeneratorKindGeneratorChain GeneratorKind = "GeneratorChain"
+	// GeneratorKindDatabaseUser represents a PostgreSQL and MySQL user generator.
+	GeneratorKindDatabaseUser GeneratorKind = "DatabaseUser"
 )
 
 // GeneratorSpec defines the configuration for various supported generator types.
@@ -85,6 +91,9 @@ type GeneratorSpec struct {
 	WebhookSpec               *WebhookSpec               `json:"webhookSpec,omitempty"`
 	GrafanaSpec               *GrafanaSpec               `json:"grafanaSpec,omitempty"`
 	MFASpec                   *MFASpec                   `json:"mfaSpec,omitempty"`
+	CertificateSpec           *CertificateSpec           `json:"certificateSpec,omitempty"`
+	GeneratorChainSpec        *GeneratorChainSpec        `json:"generatorChainSpec,omitempty"`
+	DatabaseUserSpec          *DatabaseUserSpec          `json:"databaseUserSpec,omitempty"`
 }
 
 // ClusterGenerator represents a cluster-wide generator which can be referenced as part of `generatorRef` fields.
@@ -0,0 +1,147 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// DatabaseUserSpec controls the behavior of the database user generator.
+type DatabaseUserSpec struct {
+	// Driver is the type of the database server.
+	Driver DatabaseDriver `json:"driver"`
+
+	// Host of the database server.
+	// +kubebuilder:validation:MinLength:=1
+	Host string `json:"host"`
+
+	// Port of the database server.
+	// Defaults to 5432 for PostgreSQL and 3306 for MySQL.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Database the admin connects to.
+	// Defaults to `postgres` for PostgreSQL, MySQL does not select a database by default.
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// TLSMode configures the TLS connection to the database server.
+	// +kubebuilder:default="VerifyFull"
+	// +optional
+	TLSMode DatabaseTLSMode `json:"tlsMode,omitempty"`
+
+	// CABundle is a PEM encoded CA bundle which verifies the certificate of the database server.
+	// If omitted, the system certificate pool is used. Only used with TLSMode VerifyFull.
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+
+	// Auth references the admin credential which creates and drops the users.
+	Auth DatabaseUserAuth `json:"auth"`
+
+	// UsernamePrefix is prepended to the random part of the username.
+	// +kubebuilder:default="eso_"
+	// +kubebuilder:validation:MaxLength:=16
+	// +kubebuilder:validation:Pattern:=`^[a-z_][a-z0-9_]*$`
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// PasswordLength is the length of the generated password.
+	// +kubebuilder:default=32
+	// +kubebuilder:validation:Minimum=16
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	PasswordLength int `json:"passwordLength,omitempty"`
+
+	// CreationStatements create the user along with its roles and grants.
+	// Every statement is rendered with the v2 template engine, `.username`, `.password`
+	// and `.database` are available.
+	// Defaults to a statement which creates a user that can log in, without any grants.
+	// +optional
+	CreationStatements []string `json:"creationStatements,omitempty"`
+
+	// RevocationStatements drop the user once the generated credential is no longer in use.
+	// Every statement is rendered with the v2 template engine, `.username` and `.database` are available.
+	// Defaults to dropping the objects owned by the user (PostgreSQL) and the user itself.
+	// +optional
+	RevocationStatements []string `json:"revocationStatements,omitempty"`
+}
+
+// DatabaseDriver is the type of a database server.
+// +kubebuilder:validation:Enum=PostgreSQL;MySQL
+type DatabaseDriver string
+
+const (
+	// DatabaseDriverPostgreSQL connects to a PostgreSQL server.
+	DatabaseDriverPostgreSQL DatabaseDriver = "PostgreSQL"
+	// DatabaseDriverMySQL connects to a MySQL or MariaDB server.
+	DatabaseDriverMySQL DatabaseDriver = "MySQL"
+)
+
+// DatabaseTLSMode configures the TLS connection to a database server.
+// +kubebuilder:validation:Enum=Disable;Require;VerifyFull
+type DatabaseTLSMode string
+
+const (
+	// DatabaseTLSModeDisable connects without TLS.
+	DatabaseTLSModeDisable DatabaseTLSMode = "Disable"
+	// DatabaseTLSModeRequire connects with TLS, without verifying the certificate of the server.
+	DatabaseTLSModeRequire DatabaseTLSMode = "Require"
+	// DatabaseTLSModeVerifyFull connects with TLS and verifies the certificate and the host name of the server.
+	DatabaseTLSModeVerifyFull DatabaseTLSMode = "VerifyFull"
+)
+
+// DatabaseUserAuth references the admin credential of a database server.
+// The Secrets must be in the namespace of the generator.
+type DatabaseUserAuth struct {
+	// UsernameSecretRef references the username of the admin.
+	UsernameSecretRef esmeta.SecretKeySelector `json:"usernameSecretRef"`
+
+	// PasswordSecretRef references the password of the admin.
+	PasswordSecretRef esmeta.SecretKeySelector `json:"passwordSecretRef"`
+}
+
+// DatabaseUserState is the state type produced by the database user generator.
+// It contains the name of the generated user, which is dropped on cleanup.
+type DatabaseUserState struct {
+	Username string `json:"username"`
+}
+
+// DatabaseUser creates a user with a random password on a PostgreSQL or MySQL server.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels="external-secrets.io/component=controller"
+// +kubebuilder:resource:scope=Namespaced,categories={external-secrets, external-secrets-generators}
+type DatabaseUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DatabaseUserSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseUserList contains a list of DatabaseUser resources.
+type DatabaseUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseUser `json:"items"`
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GeneratorChainSpec controls the behavior of the generator chain.
+type GeneratorChainSpec struct {
+	// Steps are the generators which are invoked in order.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Steps []GeneratorChainStep `json:"steps"`
+
+	// Template renders the output of the chain with the v2 template engine.
+	// The output of a step is available as `.<step>.<key>`.
+	// If omitted, the outputs of all steps are merged, later steps overwrite the keys of earlier steps.
+	// +optional
+	Template map[string]string `json:"template,omitempty"`
+}
+
+// GeneratorChainStep invokes a generator as part of a chain.
+type GeneratorChainStep struct {
+	// Name of the step, later steps and the template reference its output as `.<name>.<key>`.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Kind of the generator.
+	Kind GeneratorKind `json:"kind"`
+
+	// Spec of the generator, it must match the kind.
+	// String values are rendered with the v2 template engine and may reference the outputs of earlier steps.
+	// Generators which keep a state, e.g. Grafana, must not reference earlier steps.
+	// +optional
+	Spec *apiextensions.JSON `json:"spec,omitempty"`
+}
+
+// GeneratorChainState is the state type produced by the generator chain.
+// It combines the states of the steps, along with the rendered resources
+// of their generators which are needed to clean them up.
+type GeneratorChainState struct {
+	Steps []GeneratorChainStepState `json:"steps"`
+}
+
+// GeneratorChainStepState is the state of a single step of a generator chain.
+type GeneratorChainStepState struct {
+	Name     string              `json:"name"`
+	Kind     GeneratorKind       `json:"kind"`
+	Resource *apiextensions.JSON `json:"resource"`
+	State    *apiextensions.JSON `json:"state"`
+}
+
+// GeneratorChain invokes several generators in order and combines their output.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels="external-secrets.io/component=controller"
+// +kubebuilder:resource:scope=Namespaced,categories={external-secrets, external-secrets-generators}
+type GeneratorChain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GeneratorChainSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GeneratorChainList contains a list of GeneratorChain resources.
+type GeneratorChainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GeneratorChain `json:"items"`
+}
@@ -21,6 +21,12 @@ import (
 )
 
 // PasswordSpec controls the behavior of the password generator.
+// +kubebuilder:validation:XValidation:rule="!has(self.minCharacters) || (!has(self.digits) && !has(self.symbols))",message="minCharacters is mutually exclusive with digits and symbols"
+// +kubebuilder:validation:XValidation:rule="!has(self.minCharacters) || self.minCharacters.lower + self.minCharacters.upper + self.minCharacters.digits + self.minCharacters.symbols <= self.length",message="the sum of minCharacters must not exceed length"
+// +kubebuilder:validation:XValidation:rule="!has(self.minCharacters) || !self.noUpper || self.minCharacters.upper == 0",message="minCharacters.upper requires noUpper to be false"
+// +kubebuilder:validation:XValidation:rule="!has(self.passphrase) || (!has(self.digits) && !has(self.symbols) && !has(self.symbolCharacters) && !has(self.minCharacters) && !has(self.excludeCharacters))",message="passphrase is mutually exclusive with digits, symbols, symbolCharacters, minCharacters and excludeCharacters"
+// +kubebuilder:validation:XValidation:rule="!has(self.hashes) || !self.hashes.exists(h, h.algorithm in ['Bcrypt', 'Htpasswd']) || has(self.passphrase) || self.length <= (!has(self.encoding) || self.encoding == 'raw' ? 72 : self.encoding == 'hex' ? 36 : self.encoding == 'base32' ? 45 : 54)",message="the Bcrypt and Htpasswd hashes support passwords of at most 72 bytes, length must not exceed 72 raw, 54 base64, 45 base32 or 36 hex encoded characters"
+// +kubebuilder:validation:XValidation:rule="!has(self.hashes) || !self.hashes.exists(h, h.algorithm in ['Bcrypt', 'Htpasswd']) || !has(self.passphrase) || (has(self.passphrase.words) ? self.passphrase.words : 6) * (9 + (has(self.passphrase.separator) ? size(self.passphrase.separator) : 1)) - (has(self.passphrase.separator) ? size(self.passphrase.separator) : 1) <= (!has(self.encoding) || self.encoding == 'raw' ? 72 : self.encoding == 'hex' ? 36 : self.encoding == 'base32' ? 45 : 54)",message="the Bcrypt and Htpasswd hashes support passwords of at most 72 bytes, the encoded passphrase can be longer, use fewer words, a shorter separator or the raw encoding"
 type PasswordSpec struct {
 	// Length of the password to be generated.
 	// Defaults to 24
@@ -64,8 +70,115 @@ type PasswordSpec struct {
 	// +kubebuilder:default="raw"
 	// +kubebuilder:validation:Enum=base64;base64url;base32;hex;raw
 	Encoding *string `json:"encoding,omitempty"`
+
+	// MinCharacters specifies the minimum number of characters of each class
+	// in the generated password. The remaining characters are drawn from all classes.
+	// Mutually exclusive with Digits and Symbols.
+	// +optional
+	MinCharacters *PasswordMinCharacters `json:"minCharacters,omitempty"`
+
+	// ExcludeCharacters specifies characters that are never used
+	// in the generated password, e.g. ambiguous characters like "0O1lI".
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	ExcludeCharacters *string `json:"excludeCharacters,omitempty"`
+
+	// Passphrase generates a diceware passphrase from the embedded EFF large wordlist
+	// instead of a password of random characters. Length is ignored.
+	// +optional
+	Passphrase *PasswordPassphrase `json:"passphrase,omitempty"`
+
+	// Hashes derives hashes of every generated password. The hash of a password
+	// is written to the key of the password with the suffix of the algorithm,
+	// e.g. "password_bcrypt", "password_argon2id", "password_sha512crypt" or "password_htpasswd".
+	// The hashes are computed after encoding the password.
+	// Bcrypt and Htpasswd only support passwords of at most 72 bytes, which limits
+	// the length of the password depending on the encoding.
+	// +optional
+	// +kubebuilder:validation:MaxItems=4
+	// +listType=map
+	// +listMapKey=algorithm
+	Hashes []PasswordHash `json:"hashes,omitempty"`
+}
+
+// PasswordMinCharacters specifies the minimum number of characters of each class.
+type PasswordMinCharacters struct {
+	// Lower is the minimum number of lowercase letters.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Lower int `json:"lower,omitempty"`
+
+	// Upper is the minimum number of uppercase letters.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Upper int `json:"upper,omitempty"`
+
+	// Digits is the minimum number of digits.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Digits int `json:"digits,omitempty"`
+
+	// Symbols is the minimum number of symbol characters.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Symbols int `json:"symbols,omitempty"`
 }
 
+// PasswordPassphrase controls the generation of a diceware passphrase.
+type PasswordPassphrase struct {
+	// Words is the number of words of the passphrase.
+	// +kubebuilder:default=6
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	Words int `json:"words,omitempty"`
+
+	// Separator is placed between the words.
+	// +kubebuilder:default="-"
+	// +kubebuilder:validation:MaxLength=8
+	// +optional
+	Separator *string `json:"separator,omitempty"`
+
+	// Set Capitalize to capitalize the first letter of every word.
+	// +kubebuilder:default=false
+	// +optional
+	Capitalize bool `json:"capitalize,omitempty"`
+}
+
+// PasswordHash derives a hash of the generated password.
+// +kubebuilder:validation:XValidation:rule="self.algorithm != 'Htpasswd' || has(self.username)",message="username is required for the Htpasswd algorithm"
+type PasswordHash struct {
+	// Algorithm of the hash.
+	Algorithm PasswordHashAlgorithm `json:"algorithm"`
+
+	// Username of the htpasswd entry. Only used with the Htpasswd algorithm.
+	// +kubebuilder:validation:Pattern=`^[^:\s]+$`
+	// +optional
+	Username string `json:"username,omitempty"`
+}
+
+// PasswordHashAlgorithm is the algorithm of a password hash.
+// +kubebuilder:validation:Enum=Bcrypt;Argon2id;SHA512Crypt;Htpasswd
+type PasswordHashAlgorithm string
+
+const (
+	// PasswordHashBcrypt derives a bcrypt hash with the default cost of 10.
+	// Bcrypt supports passwords of at most 72 bytes.
+	PasswordHashBcrypt PasswordHashAlgorithm = "Bcrypt"
+	// PasswordHashArgon2id derives an argon2id hash in the PHC string format,
+	// with 19 MiB of memory, 2 iterations and a parallelism of 1.
+	PasswordHashArgon2id PasswordHashAlgorithm = "Argon2id"
+	// PasswordHashSHA512Crypt derives a SHA-512 crypt hash with 5000 rounds, as used by /etc/shadow.
+	PasswordHashSHA512Crypt PasswordHashAlgorithm = "SHA512Crypt"
+	// PasswordHashHtpasswd derives an htpasswd entry of the form "username:bcrypt hash".
+	// Like Bcrypt, it supports passwords of at most 72 bytes.
+	PasswordHashHtpasswd PasswordHashAlgorithm = "Htpasswd"
+)
+
 // Password generates a random password based on the
 // configuration parameters in spec.
 // You can specify the length, characterset and other attributes.
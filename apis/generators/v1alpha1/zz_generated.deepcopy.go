@@ -287,6 +287,252 @@ func (in *AzureACRWorkloadIdentityAuth) DeepCopy() *AzureACRWorkloadIdentityAuth
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Certificate) DeepCopyInto(out *Certificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Certificate.
+func (in *Certificate) DeepCopy() *Certificate {
+	if in == nil {
+		return nil
+	}
+	out := new(Certificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Certificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateCA) DeepCopyInto(out *CertificateCA) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateCA.
+func (in *CertificateCA) DeepCopy() *CertificateCA {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateCA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateJKSKeystore) DeepCopyInto(out *CertificateJKSKeystore) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateJKSKeystore.
+func (in *CertificateJKSKeystore) DeepCopy() *CertificateJKSKeystore {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateJKSKeystore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateKeystores) DeepCopyInto(out *CertificateKeystores) {
+	*out = *in
+	if in.PKCS12 != nil {
+		in, out := &in.PKCS12, &out.PKCS12
+		*out = new(CertificatePKCS12Keystore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JKS != nil {
+		in, out := &in.JKS, &out.JKS
+		*out = new(CertificateJKSKeystore)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateKeystores.
+func (in *CertificateKeystores) DeepCopy() *CertificateKeystores {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateKeystores)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateList) DeepCopyInto(out *CertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Certificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateList.
+func (in *CertificateList) DeepCopy() *CertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatePKCS12Keystore) DeepCopyInto(out *CertificatePKCS12Keystore) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePKCS12Keystore.
+func (in *CertificatePKCS12Keystore) DeepCopy() *CertificatePKCS12Keystore {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatePKCS12Keystore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatePrivateKey) DeepCopyInto(out *CertificatePrivateKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePrivateKey.
+func (in *CertificatePrivateKey) DeepCopy() *CertificatePrivateKey {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatePrivateKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
+	*out = *in
+	if in.Subject != nil {
+		in, out := &in.Subject, &out.Subject
+		*out = new(CertificateSubject)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.URIs != nil {
+		in, out := &in.URIs, &out.URIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmailAddresses != nil {
+		in, out := &in.EmailAddresses, &out.EmailAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(apismetav1.Duration)
+		**out = **in
+	}
+	out.PrivateKey = in.PrivateKey
+	if in.Usages != nil {
+		in, out := &in.Usages, &out.Usages
+		*out = make([]CertificateKeyUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(CertificateCA)
+		**out = **in
+	}
+	if in.Keystores != nil {
+		in, out := &in.Keystores, &out.Keystores
+		*out = new(CertificateKeystores)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSpec.
+func (in *CertificateSpec) DeepCopy() *CertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSubject) DeepCopyInto(out *CertificateSubject) {
+	*out = *in
+	if in.Organizations != nil {
+		in, out := &in.Organizations, &out.Organizations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrganizationalUnits != nil {
+		in, out := &in.OrganizationalUnits, &out.OrganizationalUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Countries != nil {
+		in, out := &in.Countries, &out.Countries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Localities != nil {
+		in, out := &in.Localities, &out.Localities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provinces != nil {
+		in, out := &in.Provinces, &out.Provinces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSubject.
+func (in *CertificateSubject) DeepCopy() *CertificateSubject {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudsmithAccessToken) DeepCopyInto(out *CloudsmithAccessToken) {
 	*out = *in
@@ -451,6 +697,122 @@ func (in *ControllerClassResource) DeepCopy() *ControllerClassResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUser.
+func (in *DatabaseUser) DeepCopy() *DatabaseUser {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserAuth) DeepCopyInto(out *DatabaseUserAuth) {
+	*out = *in
+	in.UsernameSecretRef.DeepCopyInto(&out.UsernameSecretRef)
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserAuth.
+func (in *DatabaseUserAuth) DeepCopy() *DatabaseUserAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserList) DeepCopyInto(out *DatabaseUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserList.
+func (in *DatabaseUserList) DeepCopy() *DatabaseUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserSpec) DeepCopyInto(out *DatabaseUserSpec) {
+	*out = *in
+	in.Auth.DeepCopyInto(&out.Auth)
+	if in.CreationStatements != nil {
+		in, out := &in.CreationStatements, &out.CreationStatements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevocationStatements != nil {
+		in, out := &in.RevocationStatements, &out.RevocationStatements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserSpec.
+func (in *DatabaseUserSpec) DeepCopy() *DatabaseUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserState) DeepCopyInto(out *DatabaseUserState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserState.
+func (in *DatabaseUserState) DeepCopy() *DatabaseUserState {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ECRAuthorizationToken) DeepCopyInto(out *ECRAuthorizationToken) {
 	*out = *in
@@ -741,6 +1103,160 @@ func (in *GCRAccessTokenSpec) DeepCopy() *GCRAccessTokenSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorChain) DeepCopyInto(out *GeneratorChain) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorChain.
+func (in *GeneratorChain) DeepCopy() *GeneratorChain {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorChain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GeneratorChain) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorChainList) DeepCopyInto(out *GeneratorChainList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GeneratorChain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorChainList.
+func (in *GeneratorChainList) DeepCopy() *GeneratorChainList {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorChainList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GeneratorChainList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorChainSpec) DeepCopyInto(out *GeneratorChainSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]GeneratorChainStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorChainSpec.
+func (in *GeneratorChainSpec) DeepCopy() *GeneratorChainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorChainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorChainState) DeepCopyInto(out *GeneratorChainState) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]GeneratorChainStepState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorChainState.
+func (in *GeneratorChainState) DeepCopy() *GeneratorChainState {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorChainState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorChainStep) DeepCopyInto(out *GeneratorChainStep) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorChainStep.
+func (in *GeneratorChainStep) DeepCopy() *GeneratorChainStep {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorChainStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorChainStepState) DeepCopyInto(out *GeneratorChainStepState) {
+	*out = *in
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorChainStepState.
+func (in *GeneratorChainStepState) DeepCopy() *GeneratorChainStepState {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorChainStepState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GeneratorSpec) DeepCopyInto(out *GeneratorSpec) {
 	*out = *in
@@ -819,6 +1335,21 @@ func (in *GeneratorSpec) DeepCopyInto(out *GeneratorSpec) {
 		*out = new(MFASpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertificateSpec != nil {
+		in, out := &in.CertificateSpec, &out.CertificateSpec
+		*out = new(CertificateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GeneratorChainSpec != nil {
+		in, out := &in.GeneratorChainSpec, &out.GeneratorChainSpec
+		*out = new(GeneratorChainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseUserSpec != nil {
+		in, out := &in.DatabaseUserSpec, &out.DatabaseUserSpec
+		*out = new(DatabaseUserSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorSpec.
@@ -907,6 +1438,10 @@ func (in *GeneratorStateSpec) DeepCopyInto(out *GeneratorStateSpec) {
 		*out = new(v1.JSON)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorStateSpec.
@@ -1373,6 +1908,21 @@ func (in *Password) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordHash) DeepCopyInto(out *PasswordHash) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordHash.
+func (in *PasswordHash) DeepCopy() *PasswordHash {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordHash)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PasswordList) DeepCopyInto(out *PasswordList) {
 	*out = *in
@@ -1405,6 +1955,41 @@ func (in *PasswordList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordMinCharacters) DeepCopyInto(out *PasswordMinCharacters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordMinCharacters.
+func (in *PasswordMinCharacters) DeepCopy() *PasswordMinCharacters {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordMinCharacters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordPassphrase) DeepCopyInto(out *PasswordPassphrase) {
+	*out = *in
+	if in.Separator != nil {
+		in, out := &in.Separator, &out.Separator
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordPassphrase.
+func (in *PasswordPassphrase) DeepCopy() *PasswordPassphrase {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordPassphrase)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PasswordSpec) DeepCopyInto(out *PasswordSpec) {
 	*out = *in
@@ -1433,6 +2018,26 @@ func (in *PasswordSpec) DeepCopyInto(out *PasswordSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MinCharacters != nil {
+		in, out := &in.MinCharacters, &out.MinCharacters
+		*out = new(PasswordMinCharacters)
+		**out = **in
+	}
+	if in.ExcludeCharacters != nil {
+		in, out := &in.ExcludeCharacters, &out.ExcludeCharacters
+		*out = new(string)
+		**out = **in
+	}
+	if in.Passphrase != nil {
+		in, out := &in.Passphrase, &out.Passphrase
+		*out = new(PasswordPassphrase)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hashes != nil {
+		in, out := &in.Hashes, &out.Hashes
+		*out = make([]PasswordHash, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordSpec.
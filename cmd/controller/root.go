@@ -18,7 +18,9 @@ package controller
 
 import (
 	"crypto/tls"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -41,9 +43,12 @@ import (
 	"github.com/external-secrets/external-secrets/pkg/controllers/clusterexternalsecret/cesmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/clusterpushsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/clusterpushsecret/cpsmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/clustersecretsync"
 	ctrlcommon "github.com/external-secrets/external-secrets/pkg/controllers/common"
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esindex"
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/receiver"
 	"github.com/external-secrets/external-secrets/pkg/controllers/generatorstate"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret"
@@ -51,6 +56,7 @@ import (
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/cssmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/ssmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretsync"
 	"github.com/external-secrets/external-secrets/runtime/feature"
 
 	// To allow using gcp auth.
@@ -89,9 +95,12 @@ var (
 	enableClusterExternalSecretReconciler bool
 	enableClusterPushSecretReconciler     bool
 	enablePushSecretReconciler            bool
+	enableSecretSyncReconciler            bool
+	enableClusterSecretSyncReconciler     bool
 	enableFloodGate                       bool
 	enableGeneratorState                  bool
 	enableExtendedMetricLabels            bool
+	enableRemoteRefIndexEndpoint          bool
 	storeRequeueInterval                  time.Duration
 	serviceName, serviceNamespace         string
 	secretName, secretNamespace           string
@@ -103,6 +112,13 @@ var (
 	tlsMinVersion                         string
 	enableHTTP2                           bool
 	allowGenericTargets                   bool
+	allowGenericSources                   bool
+	refreshJitterPercent                  int32
+	refreshJitterMode                     string
+	generatorRenewalPercent               int32
+	refreshReceiverAddr                   string
+	refreshReceiverTokenFile              string
+	historyEncryptionKeyFile              string
 )
 
 const (
@@ -160,6 +176,14 @@ var rootCmd = &cobra.Command{
 		if !enableHTTP2 {
 			metricsOpts.TLSOpts = []func(*tls.Config){disableHTTP2}
 		}
+		// the remote ref index exposes which ExternalSecrets read which remote keys,
+		// so it is only served on the metrics endpoint when explicitly enabled.
+		remoteRefIndex := esindex.New()
+		if enableRemoteRefIndexEndpoint {
+			metricsOpts.ExtraHandlers = map[string]http.Handler{
+				esindex.DebugPath: remoteRefIndex,
+			}
+		}
 		mgrOpts := ctrl.Options{
 			Scheme:                 scheme,
 			Metrics:                metricsOpts,
@@ -237,6 +261,30 @@ var rootCmd = &cobra.Command{
 			setupLog.Error(err, errCreateController, "controller", "GeneratorState")
 			os.Exit(1)
 		}
+		if refreshJitterPercent < 0 || refreshJitterPercent > 100 {
+			setupLog.Error(nil, "invalid refresh jitter, must be between 0 and 100", "percent", refreshJitterPercent)
+			os.Exit(1)
+		}
+		if mode := esv1.RefreshJitterMode(refreshJitterMode); mode != esv1.RefreshJitterModeRandom && mode != esv1.RefreshJitterModeHash {
+			setupLog.Error(nil, "invalid refresh jitter mode, must be one of Random or Hash", "mode", refreshJitterMode)
+			os.Exit(1)
+		}
+		if generatorRenewalPercent < 0 || generatorRenewalPercent > 100 {
+			setupLog.Error(nil, "invalid generator renewal, must be between 0 and 100", "percent", generatorRenewalPercent)
+			os.Exit(1)
+		}
+		var historyEncryptionKey []byte
+		if historyEncryptionKeyFile != "" {
+			historyEncryptionKey, err = os.ReadFile(historyEncryptionKeyFile)
+			if err != nil {
+				setupLog.Error(err, "unable to read history encryption key")
+				os.Exit(1)
+			}
+			if len(historyEncryptionKey) != externalsecret.HistoryEncryptionKeySize {
+				setupLog.Error(nil, "invalid history encryption key, must be 32 bytes", "size", len(historyEncryptionKey))
+				os.Exit(1)
+			}
+		}
 		if err = (&externalsecret.Reconciler{
 			Client:                             mgr.GetClient(),
 			SecretClient:                       secretClient,
@@ -250,21 +298,68 @@ var rootCmd = &cobra.Command{
 			EnableFloodGate:                    enableFloodGate,
 			EnableGeneratorState:               enableGeneratorState,
 			AllowGenericTargets:                allowGenericTargets,
+			RemoteRefIndex:                     remoteRefIndex,
+			RefreshJitter: esv1.RefreshJitter{
+				Percent: refreshJitterPercent,
+				Mode:    esv1.RefreshJitterMode(refreshJitterMode),
+			},
+			GeneratorRenewalPercent: generatorRenewalPercent,
+			HistoryEncryptionKey:    historyEncryptionKey,
 		}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "ExternalSecret")
 			os.Exit(1)
 		}
+		if refreshReceiverAddr != "" {
+			token, err := os.ReadFile(refreshReceiverTokenFile)
+			if err != nil {
+				setupLog.Error(err, "unable to read refresh receiver token")
+				os.Exit(1)
+			}
+			if err = (&receiver.Receiver{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("refresh-receiver"),
+				Addr:   refreshReceiverAddr,
+				Token:  strings.TrimSpace(string(token)),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create refresh receiver")
+				os.Exit(1)
+			}
+		}
 		if enablePushSecretReconciler {
 			psmetrics.SetUpMetrics()
 			if err = (&pushsecret.Reconciler{
+				Client:              mgr.GetClient(),
+				Log:                 ctrl.Log.WithName("controllers").WithName("PushSecret"),
+				Scheme:              mgr.GetScheme(),
+				ControllerClass:     controllerClass,
+				RestConfig:          mgr.GetConfig(),
+				RequeueInterval:     time.Hour,
+				AllowGenericSources: allowGenericSources,
+			}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
+				setupLog.Error(err, errCreateController, "controller", "PushSecret")
+				os.Exit(1)
+			}
+		}
+		if enableSecretSyncReconciler {
+			if err = (&secretsync.Reconciler{
 				Client:          mgr.GetClient(),
-				Log:             ctrl.Log.WithName("controllers").WithName("PushSecret"),
+				Log:             ctrl.Log.WithName("controllers").WithName("SecretSync"),
 				Scheme:          mgr.GetScheme(),
 				ControllerClass: controllerClass,
-				RestConfig:      mgr.GetConfig(),
 				RequeueInterval: time.Hour,
 			}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
-				setupLog.Error(err, errCreateController, "controller", "PushSecret")
+				setupLog.Error(err, errCreateController, "controller", "SecretSync")
+				os.Exit(1)
+			}
+		}
+		if enableClusterSecretSyncReconciler {
+			if err = (&clustersecretsync.Reconciler{
+				Client:          mgr.GetClient(),
+				Log:             ctrl.Log.WithName("controllers").WithName("ClusterSecretSync"),
+				Scheme:          mgr.GetScheme(),
+				RequeueInterval: time.Hour,
+			}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
+				setupLog.Error(err, errCreateController, "controller", "ClusterSecretSync")
 				os.Exit(1)
 			}
 		}
@@ -351,6 +446,8 @@ func init() {
 	rootCmd.Flags().BoolVar(&enableClusterExternalSecretReconciler, "enable-cluster-external-secret-reconciler", true, "Enable cluster external secret reconciler.")
 	rootCmd.Flags().BoolVar(&enableClusterPushSecretReconciler, "enable-cluster-push-secret-reconciler", true, "Enable cluster push secret reconciler.")
 	rootCmd.Flags().BoolVar(&enablePushSecretReconciler, "enable-push-secret-reconciler", true, "Enable push secret reconciler.")
+	rootCmd.Flags().BoolVar(&enableSecretSyncReconciler, "enable-secret-sync-reconciler", false, "Enable secret sync reconciler, which syncs Secrets with providers in both directions.")
+	rootCmd.Flags().BoolVar(&enableClusterSecretSyncReconciler, "enable-cluster-secret-sync-reconciler", false, "Enable cluster secret sync reconciler, which replicates Secrets across namespaces.")
 	rootCmd.Flags().BoolVar(&enableSecretsCache, "enable-secrets-caching", false, "Enable secrets caching for ALL secrets in the cluster (WARNING: can increase memory usage).")
 	rootCmd.Flags().BoolVar(&enableConfigMapsCache, "enable-configmaps-caching", false, "Enable configmaps caching for ALL configmaps in the cluster (WARNING: can increase memory usage).")
 	rootCmd.Flags().BoolVar(&enableManagedSecretsCache, "enable-managed-secrets-caching", true, "Enable secrets caching for secrets managed by an ExternalSecret")
@@ -364,10 +461,20 @@ func init() {
 	rootCmd.Flags().BoolVar(&enableFloodGate, "enable-flood-gate", true, "Enable flood gate. External secret will be reconciled only if the ClusterStore or Store have an healthy or unknown state.")
 	rootCmd.Flags().BoolVar(&enableGeneratorState, "enable-generator-state", true, "Whether the Controller should manage GeneratorState")
 	rootCmd.Flags().BoolVar(&enableExtendedMetricLabels, "enable-extended-metric-labels", false, "Enable recommended kubernetes annotations as labels in metrics.")
+	rootCmd.Flags().BoolVar(&enableRemoteRefIndexEndpoint, "enable-remote-ref-index-endpoint", false,
+		"Serve the index of the remote keys read by ExternalSecrets on "+esindex.DebugPath+" of the metrics endpoint. WARNING: the index reveals the names of the remote keys.")
 	rootCmd.Flags().BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics server")
+	rootCmd.Flags().Int32Var(&refreshJitterPercent, "refresh-jitter-percent", 0, "Delay the periodic refreshes of ExternalSecrets by up to this percentage of their refresh interval, to spread them across the interval.")
+	rootCmd.Flags().StringVar(&refreshJitterMode, "refresh-jitter-mode", string(esv1.RefreshJitterModeRandom), "How the delay of a refresh is chosen, one of: Random, Hash. Hash derives a fixed delay from the UID of the ExternalSecret.")
+	rootCmd.Flags().Int32Var(&generatorRenewalPercent, "generator-renewal-percent", 75, "Regenerate the values of generators with an expiry, e.g. short-lived tokens, after this percentage of their remaining lifetime, even if the refresh interval of the ExternalSecret has not elapsed yet. Set to 0 to disable.")
+	rootCmd.Flags().StringVar(&historyEncryptionKeyFile, "history-encryption-key-file", "", "Path to a file containing a 32 byte AES-256 key which encrypts the revisions of ExternalSecrets with spec.target.history. Without a key, revisions are only recorded if history.allowPlaintext is set.")
+	rootCmd.Flags().StringVar(&refreshReceiverAddr, "refresh-receiver-addr", "", "The address the refresh receiver binds to. The receiver refreshes ExternalSecrets on change notifications of providers. Disabled if empty.")
+	rootCmd.Flags().StringVar(&refreshReceiverTokenFile, "refresh-receiver-token-file", "", "Path to a file containing the token that authenticates notifications sent to the refresh receiver.")
 	rootCmd.Flags().
 		BoolVar(&allowGenericTargets, "unsafe-allow-generic-targets", false, "Enable support for creating generic resources (ConfigMaps, Custom Resources). WARNING: Using generic resources, please sure all policies are correctly configured.")
+	rootCmd.Flags().
+		BoolVar(&allowGenericSources, "unsafe-allow-generic-sources", false, "Enable support for pushing values from generic resources (Custom Resources) with PushSecrets. WARNING: Values of these resources can be pushed to any provider the PushSecret can access.")
 	fs := feature.Features()
 	for _, f := range fs {
 		rootCmd.Flags().AddFlagSet(f.Flags)
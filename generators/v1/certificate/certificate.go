@@ -0,0 +1,505 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certificate provides functionality for generating X.509 certificates,
+// self-signed or signed by a CA key pair from a Secret.
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+	"software.sslmate.com/src/go-pkcs12"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/resolvers"
+)
+
+// Generator implements X.509 certificate generation functionality.
+type Generator struct{}
+
+const (
+	defaultDuration       = 90 * 24 * time.Hour
+	defaultRSAKeySize     = 2048
+	defaultECDSAKeySize   = 256
+	defaultCertificateKey = "tls.crt"
+	defaultPrivateKeyKey  = "tls.key"
+	defaultJKSAlias       = "certificate"
+
+	// Output keys of the generator.
+	keyPrivateKey     = "tls.key"
+	keyCertificate    = "tls.crt"
+	keyCA             = "ca.crt"
+	keyPKCS12Keystore = "keystore.p12"
+	keyPKCS12Trust    = "truststore.p12"
+	keyJKSKeystore    = "keystore.jks"
+	keyJKSTrust       = "truststore.jks"
+
+	errNoSpec          = "no config spec provided"
+	errParseSpec       = "unable to parse spec: %w"
+	errGenerateKey     = "unable to generate private key: %w"
+	errUnsupportedAlgo = "unsupported key algorithm: %s"
+	errUnsupportedSize = "unsupported key size %d for %s"
+	errInvalidIP       = "invalid IP address: %s"
+	errInvalidURI      = "invalid URI %s: %w"
+	errGetCA           = "unable to get CA secret %s: %w"
+	errCAMissingKey    = "CA secret %s has no key %s"
+	errParseCACert     = "unable to parse CA certificate: %w"
+	errParseCAKey      = "unable to parse CA private key: %w"
+	errCANotCA         = "CA certificate is not a certificate authority"
+	errCAKeyMismatch   = "CA private key does not match the CA certificate"
+	errCreateCert      = "unable to create certificate: %w"
+	errPassword        = "unable to get keystore password: %w"
+	errEncodePKCS12    = "unable to encode PKCS#12 keystore: %w"
+	errEncodeJKS       = "unable to encode JKS keystore: %w"
+)
+
+// Generate creates a new private key and a certificate for it.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry creates a new private key and a certificate for it and returns the end of its validity.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
+	if jsonSpec == nil {
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf(errParseSpec, err)
+	}
+	return generate(ctx, &res.Spec, kube, namespace, time.Now())
+}
+
+// Cleanup performs any necessary cleanup after certificate generation.
+func (g *Generator) Cleanup(_ context.Context, _ *apiextensions.JSON, _ genv1alpha1.GeneratorProviderState, _ client.Client, _ string) error {
+	return nil
+}
+
+// bundle is a generated key pair along with its certificate chain.
+type bundle struct {
+	key     crypto.Signer
+	keyDER  []byte
+	cert    *x509.Certificate
+	chain   []*x509.Certificate
+	root    *x509.Certificate
+	created time.Time
+}
+
+// issuer is the key pair which signs a certificate.
+type issuer struct {
+	cert  *x509.Certificate
+	key   crypto.Signer
+	chain []*x509.Certificate
+}
+
+func generate(ctx context.Context, spec *genv1alpha1.CertificateSpec, kube client.Client, namespace string, now time.Time) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
+	key, err := generateKey(spec.PrivateKey)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	template, err := certificateTemplate(spec, now)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	parent, signer := template, crypto.Signer(key)
+	var chain []*x509.Certificate
+	if spec.CA != nil {
+		ca, err := getIssuer(ctx, spec.CA, kube, namespace)
+		if err != nil {
+			return nil, nil, time.Time{}, err
+		}
+		parent, signer, chain = ca.cert, ca.key, ca.chain
+		// a certificate must not outlive its issuer.
+		if template.NotAfter.After(ca.cert.NotAfter) {
+			template.NotAfter = ca.cert.NotAfter
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf(errCreateCert, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf(errCreateCert, err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf(errGenerateKey, err)
+	}
+	b := &bundle{key: key, keyDER: keyDER, cert: cert, chain: chain, root: cert, created: now}
+	if len(chain) > 0 {
+		b.root = chain[len(chain)-1]
+	}
+	out := map[string][]byte{
+		keyPrivateKey:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		keyCertificate: encodeCertificates(append([]*x509.Certificate{cert}, chain...)),
+		keyCA:          encodeCertificates([]*x509.Certificate{b.root}),
+	}
+	if spec.Keystores != nil {
+		if err := encodeKeystores(ctx, spec.Keystores, kube, namespace, b, out); err != nil {
+			return nil, nil, time.Time{}, err
+		}
+	}
+	return out, nil, cert.NotAfter, nil
+}
+
+func generateKey(spec genv1alpha1.CertificatePrivateKey) (crypto.Signer, error) {
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch spec.Algorithm {
+	case genv1alpha1.CertificateKeyAlgorithmRSA, "":
+		size := spec.Size
+		if size == 0 {
+			size = defaultRSAKeySize
+		}
+		if size != 2048 && size != 3072 && size != 4096 {
+			return nil, fmt.Errorf(errUnsupportedSize, size, genv1alpha1.CertificateKeyAlgorithmRSA)
+		}
+		key, err = rsa.GenerateKey(rand.Reader, size)
+	case genv1alpha1.CertificateKeyAlgorithmECDSA:
+		var curve elliptic.Curve
+		switch spec.Size {
+		case 0, defaultECDSAKeySize:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf(errUnsupportedSize, spec.Size, spec.Algorithm)
+		}
+		key, err = ecdsa.GenerateKey(curve, rand.Reader)
+	case genv1alpha1.CertificateKeyAlgorithmEd25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf(errUnsupportedAlgo, spec.Algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errGenerateKey, err)
+	}
+	return key, nil
+}
+
+func certificateTemplate(spec *genv1alpha1.CertificateSpec, now time.Time) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateCert, err)
+	}
+	duration := defaultDuration
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: spec.CommonName},
+		DNSNames:              spec.DNSNames,
+		EmailAddresses:        spec.EmailAddresses,
+		NotBefore:             now,
+		NotAfter:              now.Add(duration),
+		IsCA:                  spec.IsCA,
+		BasicConstraintsValid: true,
+	}
+	if spec.Subject != nil {
+		template.Subject.Organization = spec.Subject.Organizations
+		template.Subject.OrganizationalUnit = spec.Subject.OrganizationalUnits
+		template.Subject.Country = spec.Subject.Countries
+		template.Subject.Locality = spec.Subject.Localities
+		template.Subject.Province = spec.Subject.Provinces
+	}
+	for _, raw := range spec.IPAddresses {
+		ip := net.ParseIP(raw)
+		if ip == nil {
+			return nil, fmt.Errorf(errInvalidIP, raw)
+		}
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+	for _, raw := range spec.URIs {
+		uri, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf(errInvalidURI, raw, err)
+		}
+		template.URIs = append(template.URIs, uri)
+	}
+	template.KeyUsage, template.ExtKeyUsage = keyUsages(spec.Usages, spec.IsCA)
+	return template, nil
+}
+
+var (
+	keyUsageMap = map[genv1alpha1.CertificateKeyUsage]x509.KeyUsage{
+		genv1alpha1.CertificateKeyUsageDigitalSignature:  x509.KeyUsageDigitalSignature,
+		genv1alpha1.CertificateKeyUsageContentCommitment: x509.KeyUsageContentCommitment,
+		genv1alpha1.CertificateKeyUsageKeyEncipherment:   x509.KeyUsageKeyEncipherment,
+		genv1alpha1.CertificateKeyUsageDataEncipherment:  x509.KeyUsageDataEncipherment,
+		genv1alpha1.CertificateKeyUsageKeyAgreement:      x509.KeyUsageKeyAgreement,
+		genv1alpha1.CertificateKeyUsageCertSign:          x509.KeyUsageCertSign,
+		genv1alpha1.CertificateKeyUsageCRLSign:           x509.KeyUsageCRLSign,
+	}
+	extKeyUsageMap = map[genv1alpha1.CertificateKeyUsage]x509.ExtKeyUsage{
+		genv1alpha1.CertificateKeyUsageServerAuth:      x509.ExtKeyUsageServerAuth,
+		genv1alpha1.CertificateKeyUsageClientAuth:      x509.ExtKeyUsageClientAuth,
+		genv1alpha1.CertificateKeyUsageCodeSigning:     x509.ExtKeyUsageCodeSigning,
+		genv1alpha1.CertificateKeyUsageEmailProtection: x509.ExtKeyUsageEmailProtection,
+		genv1alpha1.CertificateKeyUsageTimeStamping:    x509.ExtKeyUsageTimeStamping,
+		genv1alpha1.CertificateKeyUsageOCSPSigning:     x509.ExtKeyUsageOCSPSigning,
+	}
+	defaultUsages = []genv1alpha1.CertificateKeyUsage{
+		genv1alpha1.CertificateKeyUsageDigitalSignature,
+		genv1alpha1.CertificateKeyUsageKeyEncipherment,
+		genv1alpha1.CertificateKeyUsageServerAuth,
+		genv1alpha1.CertificateKeyUsageClientAuth,
+	}
+)
+
+func keyUsages(usages []genv1alpha1.CertificateKeyUsage, isCA bool) (x509.KeyUsage, []x509.ExtKeyUsage) {
+	if len(usages) == 0 {
+		usages = defaultUsages
+	}
+	var (
+		keyUsage    x509.KeyUsage
+		extKeyUsage []x509.ExtKeyUsage
+	)
+	for _, usage := range usages {
+		if ku, ok := keyUsageMap[usage]; ok {
+			keyUsage |= ku
+		}
+		if eku, ok := extKeyUsageMap[usage]; ok {
+			extKeyUsage = append(extKeyUsage, eku)
+		}
+	}
+	if isCA {
+		keyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+	return keyUsage, extKeyUsage
+}
+
+func getIssuer(ctx context.Context, ref *genv1alpha1.CertificateCA, kube client.Client, namespace string) (*issuer, error) {
+	var secret corev1.Secret
+	if err := kube.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.SecretName}, &secret); err != nil {
+		return nil, fmt.Errorf(errGetCA, ref.SecretName, err)
+	}
+	certKey, keyKey := ref.CertificateKey, ref.PrivateKeyKey
+	if certKey == "" {
+		certKey = defaultCertificateKey
+	}
+	if keyKey == "" {
+		keyKey = defaultPrivateKeyKey
+	}
+	certPEM, ok := secret.Data[certKey]
+	if !ok {
+		return nil, fmt.Errorf(errCAMissingKey, ref.SecretName, certKey)
+	}
+	keyPEM, ok := secret.Data[keyKey]
+	if !ok {
+		return nil, fmt.Errorf(errCAMissingKey, ref.SecretName, keyKey)
+	}
+
+	chain, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf(errParseCACert, err)
+	}
+	if !chain[0].IsCA {
+		return nil, errors.New(errCANotCA)
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf(errParseCAKey, err)
+	}
+	if !publicKeysEqual(chain[0].PublicKey, key.Public()) {
+		return nil, errors.New(errCAKeyMismatch)
+	}
+	return &issuer{cert: chain[0], key: key, chain: chain}, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	pub, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(b)
+}
+
+func encodeCertificates(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
+
+func encodeKeystores(ctx context.Context, spec *genv1alpha1.CertificateKeystores, kube client.Client, namespace string, b *bundle, out map[string][]byte) error {
+	if spec.PKCS12 != nil {
+		password, err := resolvers.SecretKeyRef(ctx, kube, resolvers.EmptyStoreKind, namespace, &spec.PKCS12.PasswordSecretRef)
+		if err != nil {
+			return fmt.Errorf(errPassword, err)
+		}
+		encoder := pkcs12Encoder(spec.PKCS12.Profile)
+		keystoreData, err := encoder.Encode(b.key, b.cert, b.chain, password)
+		if err != nil {
+			return fmt.Errorf(errEncodePKCS12, err)
+		}
+		truststoreData, err := encoder.EncodeTrustStore([]*x509.Certificate{b.root}, password)
+		if err != nil {
+			return fmt.Errorf(errEncodePKCS12, err)
+		}
+		out[keyPKCS12Keystore] = keystoreData
+		out[keyPKCS12Trust] = truststoreData
+	}
+	if spec.JKS != nil {
+		password, err := resolvers.SecretKeyRef(ctx, kube, resolvers.EmptyStoreKind, namespace, &spec.JKS.PasswordSecretRef)
+		if err != nil {
+			return fmt.Errorf(errPassword, err)
+		}
+		alias := spec.JKS.Alias
+		if alias == "" {
+			alias = defaultJKSAlias
+		}
+		keystoreData, truststoreData, err := encodeJKS(b, alias, []byte(password))
+		if err != nil {
+			return fmt.Errorf(errEncodeJKS, err)
+		}
+		out[keyJKSKeystore] = keystoreData
+		out[keyJKSTrust] = truststoreData
+	}
+	return nil
+}
+
+func pkcs12Encoder(profile genv1alpha1.CertificatePKCS12Profile) *pkcs12.Encoder {
+	switch profile {
+	case genv1alpha1.CertificatePKCS12ProfileLegacyRC2:
+		return pkcs12.LegacyRC2
+	case genv1alpha1.CertificatePKCS12ProfileLegacyDES:
+		return pkcs12.LegacyDES
+	default:
+		return pkcs12.Modern2023
+	}
+}
+
+func encodeJKS(b *bundle, alias string, password []byte) ([]byte, []byte, error) {
+	certs := []keystore.Certificate{{Type: "X509", Content: b.cert.Raw}}
+	for _, cert := range b.chain {
+		certs = append(certs, keystore.Certificate{Type: "X509", Content: cert.Raw})
+	}
+
+	ks := keystore.New()
+	if err := ks.SetPrivateKeyEntry(alias, keystore.PrivateKeyEntry{
+		CreationTime:     b.created,
+		PrivateKey:       b.keyDER,
+		CertificateChain: certs,
+	}, password); err != nil {
+		return nil, nil, err
+	}
+	var keystoreData bytes.Buffer
+	if err := ks.Store(&keystoreData, password); err != nil {
+		return nil, nil, err
+	}
+
+	ts := keystore.New()
+	if err := ts.SetTrustedCertificateEntry("ca", keystore.TrustedCertificateEntry{
+		CreationTime: b.created,
+		Certificate:  keystore.Certificate{Type: "X509", Content: b.root.Raw},
+	}); err != nil {
+		return nil, nil, err
+	}
+	var truststoreData bytes.Buffer
+	if err := ts.Store(&truststoreData, password); err != nil {
+		return nil, nil, err
+	}
+	return keystoreData.Bytes(), truststoreData.Bytes(), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.Certificate, error) {
+	var spec genv1alpha1.Certificate
+	err := yaml.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+// NewGenerator creates a new Generator instance.
+func NewGenerator() genv1alpha1.Generator {
+	return &Generator{}
+}
+
+// Kind returns the generator kind.
+func Kind() string {
+	return string(genv1alpha1.GeneratorKindCertificate)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"software.sslmate.com/src/go-pkcs12"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const namespace = "default"
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	// a CA to sign certificates with, generated by the generator itself.
+	caOut, _, _, err := generate(ctx, &genv1alpha1.CertificateSpec{
+		CommonName: "root",
+		IsCA:       true,
+		PrivateKey: genv1alpha1.CertificatePrivateKey{Algorithm: genv1alpha1.CertificateKeyAlgorithmECDSA},
+	}, nil, namespace, now)
+	require.NoError(t, err)
+	caCert := decodeCertificate(t, caOut[keyCertificate])
+	assert.True(t, caCert.IsCA)
+	assert.Equal(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign, caCert.KeyUsage&(x509.KeyUsageCertSign|x509.KeyUsageCRLSign))
+	assert.Equal(t, caOut[keyCertificate], caOut[keyCA])
+
+	kube := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: namespace},
+			Data:       map[string][]byte{"tls.crt": caOut[keyCertificate], "tls.key": caOut[keyPrivateKey]},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: namespace},
+			Data:       map[string][]byte{"tls.crt": []byte(""), "tls.key": caOut[keyPrivateKey]},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "keystore", Namespace: namespace},
+			Data:       map[string][]byte{"password": []byte("changeit")},
+		},
+	).Build()
+
+	tests := []struct {
+		name     string
+		spec     genv1alpha1.CertificateSpec
+		kube     client.Client
+		wantErr  string
+		validate func(t *testing.T, out map[string][]byte)
+	}{
+		{
+			name: "self-signed with defaults",
+			spec: genv1alpha1.CertificateSpec{
+				CommonName:  "example.com",
+				DNSNames:    []string{"example.com", "www.example.com"},
+				IPAddresses: []string{"10.0.0.1"},
+				URIs:        []string{"spiffe://cluster.local/ns/default/sa/app"},
+				Subject:     &genv1alpha1.CertificateSubject{Organizations: []string{"ESO"}},
+			},
+			validate: func(t *testing.T, out map[string][]byte) {
+				cert := decodeCertificate(t, out[keyCertificate])
+				assert.Equal(t, "example.com", cert.Subject.CommonName)
+				assert.Equal(t, []string{"ESO"}, cert.Subject.Organization)
+				assert.Equal(t, []string{"example.com", "www.example.com"}, cert.DNSNames)
+				assert.Equal(t, "10.0.0.1", cert.IPAddresses[0].String())
+				assert.Equal(t, "spiffe://cluster.local/ns/default/sa/app", cert.URIs[0].String())
+				assert.Equal(t, now.Add(defaultDuration).UTC(), cert.NotAfter)
+				assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment, cert.KeyUsage)
+				assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage)
+				assert.False(t, cert.IsCA)
+				assert.Equal(t, out[keyCertificate], out[keyCA])
+				assert.NoError(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+				assert.Contains(t, string(out[keyPrivateKey]), "BEGIN PRIVATE KEY")
+				assert.Len(t, out, 3)
+			},
+		},
+		{
+			name: "signed by CA from secret",
+			spec: genv1alpha1.CertificateSpec{
+				CommonName: "client",
+				Duration:   &metav1.Duration{Duration: 365 * 24 * time.Hour},
+				PrivateKey: genv1alpha1.CertificatePrivateKey{Algorithm: genv1alpha1.CertificateKeyAlgorithmEd25519},
+				Usages:     []genv1alpha1.CertificateKeyUsage{genv1alpha1.CertificateKeyUsageDigitalSignature, genv1alpha1.CertificateKeyUsageClientAuth},
+				CA:         &genv1alpha1.CertificateCA{SecretName: "ca"},
+			},
+			kube: kube,
+			validate: func(t *testing.T, out map[string][]byte) {
+				certs := decodeCertificates(t, out[keyCertificate])
+				require.Len(t, certs, 2)
+				assert.Equal(t, caCert.Raw, certs[1].Raw)
+				assert.NoError(t, certs[0].CheckSignatureFrom(caCert))
+				assert.Equal(t, "root", certs[0].Issuer.CommonName)
+				assert.IsType(t, ed25519.PublicKey{}, certs[0].PublicKey)
+				assert.Equal(t, x509.KeyUsageDigitalSignature, certs[0].KeyUsage)
+				assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, certs[0].ExtKeyUsage)
+				assert.Equal(t, caOut[keyCertificate], out[keyCA])
+			},
+		},
+		{
+			name: "pkcs12 and jks keystores",
+			spec: genv1alpha1.CertificateSpec{
+				CommonName: "java",
+				PrivateKey: genv1alpha1.CertificatePrivateKey{Algorithm: genv1alpha1.CertificateKeyAlgorithmECDSA, Size: 384},
+				CA:         &genv1alpha1.CertificateCA{SecretName: "ca"},
+				Keystores: &genv1alpha1.CertificateKeystores{
+					PKCS12: &genv1alpha1.CertificatePKCS12Keystore{
+						PasswordSecretRef: esmeta.SecretKeySelector{Name: "keystore", Key: "password"},
+					},
+					JKS: &genv1alpha1.CertificateJKSKeystore{
+						PasswordSecretRef: esmeta.SecretKeySelector{Name: "keystore", Key: "password"},
+						Alias:             "app",
+					},
+				},
+			},
+			kube: kube,
+			validate: func(t *testing.T, out map[string][]byte) {
+				key, cert, chain, err := pkcs12.DecodeChain(out[keyPKCS12Keystore], "changeit")
+				require.NoError(t, err)
+				assert.IsType(t, &ecdsa.PrivateKey{}, key)
+				assert.Equal(t, "java", cert.Subject.CommonName)
+				assert.Equal(t, caCert.Raw, chain[0].Raw)
+				trusted, err := pkcs12.DecodeTrustStore(out[keyPKCS12Trust], "changeit")
+				require.NoError(t, err)
+				assert.Equal(t, caCert.Raw, trusted[0].Raw)
+
+				ks := keystore.New()
+				require.NoError(t, ks.Load(bytes.NewReader(out[keyJKSKeystore]), []byte("changeit")))
+				entry, err := ks.GetPrivateKeyEntry("app", []byte("changeit"))
+				require.NoError(t, err)
+				assert.Len(t, entry.CertificateChain, 2)
+				ts := keystore.New()
+				require.NoError(t, ts.Load(bytes.NewReader(out[keyJKSTrust]), []byte("changeit")))
+				trustedEntry, err := ts.GetTrustedCertificateEntry("ca")
+				require.NoError(t, err)
+				assert.Equal(t, caCert.Raw, trustedEntry.Certificate.Content)
+			},
+		},
+		{
+			name:    "invalid IP address",
+			spec:    genv1alpha1.CertificateSpec{IPAddresses: []string{"not-an-ip"}},
+			wantErr: "invalid IP address: not-an-ip",
+		},
+		{
+			name:    "unsupported RSA key size",
+			spec:    genv1alpha1.CertificateSpec{PrivateKey: genv1alpha1.CertificatePrivateKey{Size: 1024}},
+			wantErr: "unsupported key size 1024 for RSA",
+		},
+		{
+			name:    "missing CA secret",
+			spec:    genv1alpha1.CertificateSpec{CA: &genv1alpha1.CertificateCA{SecretName: "missing"}},
+			kube:    kube,
+			wantErr: "unable to get CA secret missing",
+		},
+		{
+			name:    "CA secret without certificate",
+			spec:    genv1alpha1.CertificateSpec{CA: &genv1alpha1.CertificateCA{SecretName: "leaf"}},
+			kube:    kube,
+			wantErr: "unable to parse CA certificate: no PEM encoded certificate found",
+		},
+		{
+			name: "missing keystore password",
+			spec: genv1alpha1.CertificateSpec{Keystores: &genv1alpha1.CertificateKeystores{
+				JKS: &genv1alpha1.CertificateJKSKeystore{PasswordSecretRef: esmeta.SecretKeySelector{Name: "missing", Key: "password"}},
+			}},
+			kube:    kube,
+			wantErr: "unable to get keystore password",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, expiresAt, err := generate(ctx, &tt.spec, tt.kube, namespace, now)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, decodeCertificate(t, out[keyCertificate]).NotAfter, expiresAt)
+			tt.validate(t, out)
+		})
+	}
+}
+
+func TestGenerateSpec(t *testing.T) {
+	g := &Generator{}
+	_, _, err := g.Generate(context.Background(), nil, nil, namespace)
+	assert.EqualError(t, err, errNoSpec)
+
+	out, _, err := g.Generate(context.Background(), &apiextensions.JSON{Raw: []byte(`{"spec":{"commonName":"example.com","privateKey":{"algorithm":"ECDSA"}}}`)}, nil, namespace)
+	require.NoError(t, err)
+	cert := decodeCertificate(t, out[keyCertificate])
+	assert.Equal(t, "example.com", cert.Subject.CommonName)
+	assert.IsType(t, &ecdsa.PublicKey{}, cert.PublicKey)
+}
+
+func TestCAKeyMismatch(t *testing.T) {
+	ctx := context.Background()
+	ca, _, _, err := generate(ctx, &genv1alpha1.CertificateSpec{IsCA: true}, nil, namespace, time.Now())
+	require.NoError(t, err)
+	other, _, _, err := generate(ctx, &genv1alpha1.CertificateSpec{IsCA: true}, nil, namespace, time.Now())
+	require.NoError(t, err)
+	kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: namespace},
+		Data:       map[string][]byte{"ca.pem": ca[keyCertificate], "ca-key.pem": other[keyPrivateKey]},
+	}).Build()
+
+	_, _, _, err = generate(ctx, &genv1alpha1.CertificateSpec{CA: &genv1alpha1.CertificateCA{
+		SecretName:     "ca",
+		CertificateKey: "ca.pem",
+		PrivateKeyKey:  "ca-key.pem",
+	}}, kube, namespace, time.Now())
+	assert.EqualError(t, err, errCAKeyMismatch)
+}
+
+func decodeCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	return decodeCertificates(t, data)[0]
+}
+
+func decodeCertificates(t *testing.T, data []byte) []*x509.Certificate {
+	t.Helper()
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		certs = append(certs, cert)
+	}
+	require.NotEmpty(t, certs)
+	return certs
+}
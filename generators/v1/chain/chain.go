@@ -0,0 +1,280 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chain implements a generator which invokes several generators in order and combines their output.
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	estemplate "github.com/external-secrets/external-secrets/runtime/template/v2"
+)
+
+// Generator implements a generator which chains other generators.
+type Generator struct{}
+
+const (
+	errNoSpec       = "no config spec provided"
+	errParseSpec    = "unable to parse spec: %w"
+	errParseState   = "unable to parse state: %w"
+	errUnknownKind  = "unknown generator kind %s"
+	errStep         = "step %s: %w"
+	errRenderSpec   = "unable to render spec: %w"
+	errRenderOutput = "unable to render template at key %s: %w"
+	errStateSpec    = "generator %s keeps a state, its spec must not reference earlier steps"
+)
+
+// Generate invokes the steps of the chain in order.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry invokes the steps of the chain in order and returns the earliest expiry of their output.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
+	if jsonSpec == nil {
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf(errParseSpec, err)
+	}
+
+	var (
+		// outputs holds the output of every step by name, it is the data of the templates.
+		outputs   = make(map[string]map[string]string, len(res.Spec.Steps))
+		merged    = make(map[string][]byte)
+		states    []genv1alpha1.GeneratorChainStepState
+		expiresAt time.Time
+	)
+	// the GeneratorState is only written on success,
+	// so the steps which already ran are cleaned up if the chain fails.
+	fail := func(err error) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
+		return nil, nil, time.Time{}, errors.Join(err, cleanup(ctx, states, kube, namespace))
+	}
+	for _, step := range res.Spec.Steps {
+		data, state, stepExpiresAt, err := generateStep(ctx, step, outputs, kube, namespace)
+		if err != nil {
+			return fail(fmt.Errorf(errStep, step.Name, err))
+		}
+		if state != nil {
+			states = append(states, *state)
+		}
+		outputs[step.Name] = make(map[string]string, len(data))
+		for k, v := range data {
+			outputs[step.Name][k] = string(v)
+			merged[k] = v
+		}
+		if !stepExpiresAt.IsZero() && (expiresAt.IsZero() || stepExpiresAt.Before(expiresAt)) {
+			expiresAt = stepExpiresAt
+		}
+	}
+
+	if len(res.Spec.Template) > 0 {
+		merged = make(map[string][]byte, len(res.Spec.Template))
+		for k, tpl := range res.Spec.Template {
+			out, err := render(k, tpl, outputs)
+			if err != nil {
+				return fail(fmt.Errorf(errRenderOutput, k, err))
+			}
+			merged[k] = []byte(out)
+		}
+	}
+
+	if len(states) == 0 {
+		return merged, nil, expiresAt, nil
+	}
+	rawState, err := json.Marshal(genv1alpha1.GeneratorChainState{Steps: states})
+	if err != nil {
+		return fail(err)
+	}
+	return merged, &apiextensions.JSON{Raw: rawState}, expiresAt, nil
+}
+
+// generateStep renders the spec of a step and invokes its generator.
+// The returned state is nil if the generator did not produce a state.
+// A step which keeps a state must not have a templated spec: its spec is stored in the GeneratorState
+// to clean it up, and the rendered spec can contain the outputs of earlier steps, e.g. a password.
+func generateStep(ctx context.Context, step genv1alpha1.GeneratorChainStep, outputs map[string]map[string]string, kube client.Client, namespace string) (map[string][]byte, *genv1alpha1.GeneratorChainStepState, time.Time, error) {
+	gen, ok := genv1alpha1.GetGeneratorByName(string(step.Kind))
+	if !ok {
+		return nil, nil, time.Time{}, fmt.Errorf(errUnknownKind, step.Kind)
+	}
+	spec, templated, err := renderSpec(step.Spec, outputs)
+	if err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf(errRenderSpec, err)
+	}
+	resource, err := json.Marshal(map[string]any{
+		"apiVersion": genv1alpha1.SchemeGroupVersion.String(),
+		"kind":       string(step.Kind),
+		"spec":       spec,
+	})
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	obj := &apiextensions.JSON{Raw: resource}
+	data, state, expiresAt, err := genv1alpha1.GenerateWithExpiry(ctx, gen, obj, kube, namespace)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if state == nil {
+		return data, nil, expiresAt, nil
+	}
+	if templated {
+		return nil, nil, time.Time{}, errors.Join(fmt.Errorf(errStateSpec, step.Kind), gen.Cleanup(ctx, obj, state, kube, namespace))
+	}
+	return data, &genv1alpha1.GeneratorChainStepState{
+		Name:     step.Name,
+		Kind:     step.Kind,
+		Resource: obj,
+		State:    state,
+	}, expiresAt, nil
+}
+
+// Cleanup cleans up the state of every step of the chain, in reverse order.
+func (g *Generator) Cleanup(ctx context.Context, _ *apiextensions.JSON, state genv1alpha1.GeneratorProviderState, kube client.Client, namespace string) error {
+	if state == nil {
+		return nil
+	}
+	var chainState genv1alpha1.GeneratorChainState
+	if err := json.Unmarshal(state.Raw, &chainState); err != nil {
+		return fmt.Errorf(errParseState, err)
+	}
+	return cleanup(ctx, chainState.Steps, kube, namespace)
+}
+
+// cleanup cleans up the states of the given steps in reverse order.
+// It continues after a failure, so one failing step does not leak the others.
+func cleanup(ctx context.Context, states []genv1alpha1.GeneratorChainStepState, kube client.Client, namespace string) error {
+	var errs []error
+	for _, state := range slices.Backward(states) {
+		gen, ok := genv1alpha1.GetGeneratorByName(string(state.Kind))
+		if !ok {
+			errs = append(errs, fmt.Errorf(errStep, state.Name, fmt.Errorf(errUnknownKind, state.Kind)))
+			continue
+		}
+		if err := gen.Cleanup(ctx, state.Resource, state.State, kube, namespace); err != nil {
+			errs = append(errs, fmt.Errorf(errStep, state.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderSpec renders every string value of the spec as a template
+// and reports whether the spec contains a template.
+func renderSpec(spec *apiextensions.JSON, outputs map[string]map[string]string) (any, bool, error) {
+	if spec == nil || len(spec.Raw) == 0 {
+		return map[string]any{}, false, nil
+	}
+	// numbers are kept as they are, instead of round-tripping them through float64.
+	decoder := json.NewDecoder(bytes.NewReader(spec.Raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false, err
+	}
+	templated := hasTemplate(value)
+	rendered, err := renderValue(value, outputs)
+	return rendered, templated, err
+}
+
+func hasTemplate(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, "{{")
+	case map[string]any:
+		for _, item := range v {
+			if hasTemplate(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if hasTemplate(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func renderValue(value any, outputs map[string]map[string]string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return render("spec", v, outputs)
+	case map[string]any:
+		for k, item := range v {
+			rendered, err := renderValue(item, outputs)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			v[k] = rendered
+		}
+	case []any:
+		for i, item := range v {
+			rendered, err := renderValue(item, outputs)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = rendered
+		}
+	}
+	return value, nil
+}
+
+func render(name, text string, outputs map[string]map[string]string) (string, error) {
+	t, err := template.New(name).
+		Funcs(estemplate.FuncMap()).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, outputs); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.GeneratorChain, error) {
+	var spec genv1alpha1.GeneratorChain
+	err := yaml.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+// NewGenerator creates a new Generator instance.
+func NewGenerator() genv1alpha1.Generator {
+	return &Generator{}
+}
+
+// Kind returns the generator kind.
+func Kind() string {
+	return string(genv1alpha1.GeneratorKindGeneratorChain)
+}
@@ -0,0 +1,283 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+const (
+	kindEcho      = "ChainTestEcho"
+	kindStateful  = "ChainTestStateful"
+	kindFailing   = "ChainTestFailing"
+	testNamespace = "default"
+)
+
+var expiry = time.Date(2024, 10, 11, 12, 48, 44, 0, time.UTC)
+
+// echoGenerator outputs the string values of its spec.
+type echoGenerator struct{}
+
+func (g *echoGenerator) Generate(_ context.Context, obj *apiextensions.JSON, _ client.Client, _ string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	var res struct {
+		Spec map[string]string `json:"spec"`
+	}
+	if err := json.Unmarshal(obj.Raw, &res); err != nil {
+		return nil, nil, err
+	}
+	out := make(map[string][]byte, len(res.Spec))
+	for k, v := range res.Spec {
+		out[k] = []byte(v)
+	}
+	return out, nil, nil
+}
+
+func (g *echoGenerator) Cleanup(_ context.Context, _ *apiextensions.JSON, _ genv1alpha1.GeneratorProviderState, _ client.Client, _ string) error {
+	return errors.New("echo has no state to clean up")
+}
+
+// statefulGenerator outputs a password, keeps the id of its spec as its state and records its cleanups.
+type statefulGenerator struct {
+	cleanups []string
+}
+
+func (g *statefulGenerator) Generate(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, obj, kube, namespace)
+	return data, state, err
+}
+
+func (g *statefulGenerator) GenerateWithExpiry(_ context.Context, obj *apiextensions.JSON, _ client.Client, _ string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
+	var res struct {
+		Spec struct {
+			ID string `json:"id"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(obj.Raw, &res); err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return map[string][]byte{"password": []byte("s3cr3t")}, &apiextensions.JSON{Raw: []byte(res.Spec.ID)}, expiry, nil
+}
+
+func (g *statefulGenerator) Cleanup(_ context.Context, _ *apiextensions.JSON, state genv1alpha1.GeneratorProviderState, _ client.Client, _ string) error {
+	g.cleanups = append(g.cleanups, string(state.Raw))
+	return nil
+}
+
+type failingGenerator struct{}
+
+func (g *failingGenerator) Generate(_ context.Context, _ *apiextensions.JSON, _ client.Client, _ string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	return nil, nil, errors.New("boom")
+}
+
+func (g *failingGenerator) Cleanup(_ context.Context, _ *apiextensions.JSON, _ genv1alpha1.GeneratorProviderState, _ client.Client, _ string) error {
+	return nil
+}
+
+func setup(t *testing.T) *statefulGenerator {
+	t.Helper()
+	stateful := &statefulGenerator{}
+	genv1alpha1.ForceRegister(kindEcho, &echoGenerator{})
+	genv1alpha1.ForceRegister(kindStateful, stateful)
+	genv1alpha1.ForceRegister(kindFailing, &failingGenerator{})
+	return stateful
+}
+
+func chainSpec(spec string) *apiextensions.JSON {
+	return &apiextensions.JSON{Raw: []byte(`apiVersion: generators.external-secrets.io/v1alpha1
+kind: GeneratorChain
+spec:
+` + spec)}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          string
+		want          map[string][]byte
+		wantState     bool
+		wantExpiresAt time.Time
+		wantErr       string
+		wantCleanups  []string
+	}{
+		{
+			name: "later steps reference earlier outputs",
+			spec: `  steps:
+  - name: secret
+    kind: ChainTestStateful
+  - name: derived
+    kind: ChainTestEcho
+    spec:
+      upper: "{{ .secret.password | upper }}"
+      plain: "unchanged"
+`,
+			want: map[string][]byte{
+				"password": []byte("s3cr3t"),
+				"upper":    []byte("S3CR3T"),
+				"plain":    []byte("unchanged"),
+			},
+			wantState:     true,
+			wantExpiresAt: expiry,
+		},
+		{
+			name: "template renders the output",
+			spec: `  steps:
+  - name: user
+    kind: ChainTestEcho
+    spec:
+      name: "app"
+  - name: secret
+    kind: ChainTestStateful
+  template:
+    credentials: "{{ .user.name }}:{{ .secret.password }}"
+`,
+			want: map[string][]byte{
+				"credentials": []byte("app:s3cr3t"),
+			},
+			wantState:     true,
+			wantExpiresAt: expiry,
+		},
+		{
+			name: "steps without state",
+			spec: `  steps:
+  - name: first
+    kind: ChainTestEcho
+    spec:
+      key: "a"
+  - name: second
+    kind: ChainTestEcho
+    spec:
+      key: "{{ .first.key }}b"
+`,
+			want: map[string][]byte{
+				"key": []byte("ab"),
+			},
+		},
+		{
+			name: "failing step cleans up earlier steps",
+			spec: `  steps:
+  - name: secret
+    kind: ChainTestStateful
+    spec:
+      id: "1"
+  - name: fail
+    kind: ChainTestFailing
+`,
+			wantErr:      "step fail: boom",
+			wantCleanups: []string{"1"},
+		},
+		{
+			name: "missing output fails the template",
+			spec: `  steps:
+  - name: secret
+    kind: ChainTestStateful
+    spec:
+      id: "1"
+  template:
+    value: "{{ .secret.missing }}"
+`,
+			wantErr:      "unable to render template at key value",
+			wantCleanups: []string{"1"},
+		},
+		{
+			name: "stateful step with a templated spec is cleaned up and fails",
+			spec: `  steps:
+  - name: first
+    kind: ChainTestStateful
+    spec:
+      id: "1"
+  - name: second
+    kind: ChainTestStateful
+    spec:
+      id: "{{ .first.password | len }}"
+`,
+			wantErr:      "step second: generator ChainTestStateful keeps a state, its spec must not reference earlier steps",
+			wantCleanups: []string{"6", "1"},
+		},
+		{
+			name: "unknown kind",
+			spec: `  steps:
+  - name: unknown
+    kind: DoesNotExist
+`,
+			wantErr: "step unknown: unknown generator kind DoesNotExist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateful := setup(t)
+			got, state, expiresAt, err := (&Generator{}).GenerateWithExpiry(context.Background(), chainSpec(tt.spec), nil, testNamespace)
+			assert.Equal(t, tt.wantCleanups, stateful.cleanups)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantState, state != nil)
+			assert.True(t, expiresAt.Equal(tt.wantExpiresAt))
+		})
+	}
+}
+
+func TestGenerateNoSpec(t *testing.T) {
+	_, _, err := (&Generator{}).Generate(context.Background(), nil, nil, testNamespace)
+	assert.EqualError(t, err, errNoSpec)
+}
+
+func TestCleanup(t *testing.T) {
+	stateful := setup(t)
+	g := &Generator{}
+	_, state, err := g.Generate(context.Background(), chainSpec(`  steps:
+  - name: first
+    kind: ChainTestStateful
+    spec:
+      id: "1"
+  - name: echo
+    kind: ChainTestEcho
+  - name: second
+    kind: ChainTestStateful
+    spec:
+      id: "2"
+`), nil, testNamespace)
+	require.NoError(t, err)
+
+	var chainState genv1alpha1.GeneratorChainState
+	require.NoError(t, json.Unmarshal(state.Raw, &chainState))
+	require.Len(t, chainState.Steps, 2)
+	assert.Equal(t, "first", chainState.Steps[0].Name)
+	assert.Equal(t, "second", chainState.Steps[1].Name)
+	assert.JSONEq(t, `{"apiVersion":"generators.external-secrets.io/v1alpha1","kind":"ChainTestStateful","spec":{"id":"2"}}`, string(chainState.Steps[1].Resource.Raw))
+
+	// the steps without a state are skipped, the others are cleaned up in reverse order.
+	require.NoError(t, g.Cleanup(context.Background(), nil, state, nil, testNamespace))
+	assert.Equal(t, []string{"2", "1"}, stateful.cleanups)
+
+	assert.NoError(t, g.Cleanup(context.Background(), nil, nil, nil, testNamespace))
+	assert.ErrorContains(t, g.Cleanup(context.Background(), nil, &apiextensions.JSON{Raw: []byte(`{"steps":[{"name":"gone","kind":"DoesNotExist"}]}`)}, nil, testNamespace),
+		"step gone: unknown generator kind DoesNotExist")
+}
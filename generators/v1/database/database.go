@@ -0,0 +1,369 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package database implements a generator which creates users on PostgreSQL and MySQL servers.
+package database
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/sethvargo/go-password/password"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/resolvers"
+	estemplate "github.com/external-secrets/external-secrets/runtime/template/v2"
+)
+
+// Generator implements the creation of database users.
+type Generator struct{}
+
+const (
+	errNoSpec          = "no config spec provided"
+	errParseSpec       = "unable to parse spec: %w"
+	errParseState      = "unable to parse state: %w"
+	errUnknownDriver   = "unknown driver %q"
+	errGetCredentials  = "unable to get admin credentials: %w"
+	errConnect         = "unable to connect to database: %w"
+	errGenerate        = "unable to generate credentials: %w"
+	errRender          = "unable to render statement %d: %w"
+	errExecute         = "unable to execute statement %d: %w"
+	errCheckUser       = "unable to check if user %s exists: %w"
+	errInvalidCABundle = "no certificate found in caBundle"
+
+	keyUsername = "username"
+	keyPassword = "password"
+
+	defaultUsernamePrefix = "eso_"
+	defaultPasswordLength = 32
+	usernameSuffixLength  = 12
+)
+
+// dialect holds the defaults of a database driver.
+type dialect struct {
+	defaultPort          int32
+	creationStatements   []string
+	revocationStatements []string
+	// userExists is a query which returns whether the user given as its only argument exists.
+	userExists string
+	open       func(spec *genv1alpha1.DatabaseUserSpec, tlsConfig *tls.Config, username, password string) (*sql.DB, error)
+}
+
+var dialects = map[genv1alpha1.DatabaseDriver]dialect{
+	genv1alpha1.DatabaseDriverPostgreSQL: {
+		defaultPort: 5432,
+		creationStatements: []string{
+			`CREATE ROLE "{{ .username }}" WITH LOGIN PASSWORD '{{ .password }}'`,
+		},
+		revocationStatements: []string{
+			`DROP OWNED BY "{{ .username }}"`,
+			`DROP ROLE "{{ .username }}"`,
+		},
+		userExists: "SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = $1)",
+		open:       openPostgreSQL,
+	},
+	genv1alpha1.DatabaseDriverMySQL: {
+		defaultPort: 3306,
+		creationStatements: []string{
+			`CREATE USER '{{ .username }}'@'%' IDENTIFIED BY '{{ .password }}'`,
+		},
+		revocationStatements: []string{
+			`DROP USER '{{ .username }}'@'%'`,
+		},
+		userExists: "SELECT EXISTS (SELECT 1 FROM mysql.user WHERE user = ?)",
+		open:       openMySQL,
+	},
+}
+
+// openFunc connects to the database server with the admin credential.
+type openFunc func(ctx context.Context, spec *genv1alpha1.DatabaseUserSpec, kube client.Client, namespace string) (*sql.DB, dialect, error)
+
+// Generate creates a database user with a random password.
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	return g.generate(ctx, jsonSpec, kube, namespace, open)
+}
+
+func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string, openDB openFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	if jsonSpec == nil {
+		return nil, nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errParseSpec, err)
+	}
+	spec := &res.Spec
+
+	username, userPassword, err := generateCredentials(spec)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errGenerate, err)
+	}
+
+	db, d, err := openDB(ctx, spec, kube, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	statements := spec.CreationStatements
+	if len(statements) == 0 {
+		statements = d.creationStatements
+	}
+	data := map[string]string{
+		"username": username,
+		"password": userPassword,
+		"database": spec.Database,
+	}
+	if err := execute(ctx, db, statements, data); err != nil {
+		// a failing statement may leave a partially set up user behind.
+		return nil, nil, errors.Join(err, revoke(ctx, db, d, spec, username))
+	}
+
+	state, err := json.Marshal(genv1alpha1.DatabaseUserState{Username: username})
+	if err != nil {
+		return nil, nil, errors.Join(err, revoke(ctx, db, d, spec, username))
+	}
+	return map[string][]byte{
+		keyUsername: []byte(username),
+		keyPassword: []byte(userPassword),
+	}, &apiextensions.JSON{Raw: state}, nil
+}
+
+// Cleanup drops the user which was created by Generate.
+func (g *Generator) Cleanup(ctx context.Context, jsonSpec *apiextensions.JSON, state genv1alpha1.GeneratorProviderState, kube client.Client, namespace string) error {
+	return g.cleanup(ctx, jsonSpec, state, kube, namespace, open)
+}
+
+func (g *Generator) cleanup(ctx context.Context, jsonSpec *apiextensions.JSON, state genv1alpha1.GeneratorProviderState, kube client.Client, namespace string, openDB openFunc) error {
+	if state == nil {
+		return nil
+	}
+	var userState genv1alpha1.DatabaseUserState
+	if err := json.Unmarshal(state.Raw, &userState); err != nil {
+		return fmt.Errorf(errParseState, err)
+	}
+	if jsonSpec == nil {
+		return errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return fmt.Errorf(errParseSpec, err)
+	}
+
+	db, d, err := openDB(ctx, &res.Spec, kube, namespace)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	return revoke(ctx, db, d, &res.Spec, userState.Username)
+}
+
+// revoke runs the revocation statements, unless the user does not exist (anymore).
+func revoke(ctx context.Context, db *sql.DB, d dialect, spec *genv1alpha1.DatabaseUserSpec, username string) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, d.userExists, username).Scan(&exists); err != nil {
+		return fmt.Errorf(errCheckUser, username, err)
+	}
+	if !exists {
+		return nil
+	}
+	statements := spec.RevocationStatements
+	if len(statements) == 0 {
+		statements = d.revocationStatements
+	}
+	return execute(ctx, db, statements, map[string]string{
+		"username": username,
+		"database": spec.Database,
+	})
+}
+
+// execute renders and executes the statements in order, on a single connection.
+func execute(ctx context.Context, db *sql.DB, statements []string, data map[string]string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf(errConnect, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	for i, statement := range statements {
+		query, err := render(statement, data)
+		if err != nil {
+			return fmt.Errorf(errRender, i, err)
+		}
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf(errExecute, i, err)
+		}
+	}
+	return nil
+}
+
+func render(statement string, data map[string]string) (string, error) {
+	t, err := template.New("statement").
+		Funcs(estemplate.FuncMap()).
+		Option("missingkey=error").
+		Parse(statement)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// generateCredentials generates the username and the password of the user.
+// Neither contains characters which need quoting in SQL statements.
+func generateCredentials(spec *genv1alpha1.DatabaseUserSpec) (string, string, error) {
+	prefix := defaultUsernamePrefix
+	if spec.UsernamePrefix != "" {
+		prefix = spec.UsernamePrefix
+	}
+	suffix, err := password.Generate(usernameSuffixLength, usernameSuffixLength/3, 0, true, true)
+	if err != nil {
+		return "", "", err
+	}
+	length := defaultPasswordLength
+	if spec.PasswordLength > 0 {
+		length = spec.PasswordLength
+	}
+	userPassword, err := password.Generate(length, length/4, 0, false, true)
+	if err != nil {
+		return "", "", err
+	}
+	return prefix + suffix, userPassword, nil
+}
+
+// open connects to the database server with the admin credential from the Secrets.
+func open(ctx context.Context, spec *genv1alpha1.DatabaseUserSpec, kube client.Client, namespace string) (*sql.DB, dialect, error) {
+	d, ok := dialects[spec.Driver]
+	if !ok {
+		return nil, dialect{}, fmt.Errorf(errUnknownDriver, spec.Driver)
+	}
+	username, err := resolvers.SecretKeyRef(ctx, kube, resolvers.EmptyStoreKind, namespace, &spec.Auth.UsernameSecretRef)
+	if err != nil {
+		return nil, dialect{}, fmt.Errorf(errGetCredentials, err)
+	}
+	adminPassword, err := resolvers.SecretKeyRef(ctx, kube, resolvers.EmptyStoreKind, namespace, &spec.Auth.PasswordSecretRef)
+	if err != nil {
+		return nil, dialect{}, fmt.Errorf(errGetCredentials, err)
+	}
+	tlsConfig, err := newTLSConfig(spec)
+	if err != nil {
+		return nil, dialect{}, fmt.Errorf(errConnect, err)
+	}
+	if spec.Port == 0 {
+		spec.Port = d.defaultPort
+	}
+	db, err := d.open(spec, tlsConfig, username, adminPassword)
+	if err != nil {
+		return nil, dialect{}, fmt.Errorf(errConnect, err)
+	}
+	return db, d, nil
+}
+
+func openPostgreSQL(spec *genv1alpha1.DatabaseUserSpec, tlsConfig *tls.Config, username, adminPassword string) (*sql.DB, error) {
+	// the connection settings below take precedence over the PG* environment variables of the controller.
+	cfg, err := pgx.ParseConfig("sslmode=disable")
+	if err != nil {
+		return nil, err
+	}
+	cfg.Host = spec.Host
+	cfg.Port = uint16(spec.Port) //nolint:gosec // the port is validated by the CRD
+	cfg.Database = spec.Database
+	if cfg.Database == "" {
+		cfg.Database = "postgres"
+	}
+	cfg.User = username
+	cfg.Password = adminPassword
+	cfg.TLSConfig = tlsConfig
+	cfg.Fallbacks = nil
+	return stdlib.OpenDB(*cfg), nil
+}
+
+func openMySQL(spec *genv1alpha1.DatabaseUserSpec, tlsConfig *tls.Config, username, adminPassword string) (*sql.DB, error) {
+	cfg := mysql.NewConfig()
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(spec.Host, strconv.Itoa(int(spec.Port)))
+	cfg.DBName = spec.Database
+	cfg.User = username
+	cfg.Passwd = adminPassword
+	cfg.TLS = tlsConfig
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+func newTLSConfig(spec *genv1alpha1.DatabaseUserSpec) (*tls.Config, error) {
+	switch spec.TLSMode {
+	case genv1alpha1.DatabaseTLSModeDisable:
+		return nil, nil
+	case genv1alpha1.DatabaseTLSModeRequire:
+		return &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // Require only encrypts the connection, as documented
+			MinVersion:         tls.VersionTLS12,
+		}, nil
+	default:
+		cfg := &tls.Config{
+			ServerName: spec.Host,
+			MinVersion: tls.VersionTLS12,
+		}
+		if spec.CABundle != "" {
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM([]byte(spec.CABundle)) {
+				return nil, errors.New(errInvalidCABundle)
+			}
+		}
+		return cfg, nil
+	}
+}
+
+func parseSpec(data []byte) (*genv1alpha1.DatabaseUser, error) {
+	var spec genv1alpha1.DatabaseUser
+	err := yaml.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+// NewGenerator creates a new Generator instance.
+func NewGenerator() genv1alpha1.Generator {
+	return &Generator{}
+}
+
+// Kind returns the generator kind.
+func Kind() string {
+	return string(genv1alpha1.GeneratorKindDatabaseUser)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const namespace = "default"
+
+func databaseSpec(driver genv1alpha1.DatabaseDriver, extra string) *apiextensions.JSON {
+	return &apiextensions.JSON{Raw: []byte(`apiVersion: generators.external-secrets.io/v1alpha1
+kind: DatabaseUser
+spec:
+  driver: ` + string(driver) + `
+  host: db.example.com
+  auth:
+    usernameSecretRef:
+      name: admin
+      key: username
+    passwordSecretRef:
+      name: admin
+      key: password
+` + extra)}
+}
+
+// mockOpen returns an openFunc which hands out the mocked database.
+func mockOpen(t *testing.T) (sqlmock.Sqlmock, openFunc) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	return mock, func(_ context.Context, spec *genv1alpha1.DatabaseUserSpec, _ client.Client, _ string) (*sql.DB, dialect, error) {
+		return db, dialects[spec.Driver], nil
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   *apiextensions.JSON
+		expect func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "PostgreSQL default statements",
+			spec: databaseSpec(genv1alpha1.DatabaseDriverPostgreSQL, ""),
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`^CREATE ROLE "eso_[a-z0-9]{12}" WITH LOGIN PASSWORD '[a-zA-Z0-9]{32}'$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "MySQL default statements",
+			spec: databaseSpec(genv1alpha1.DatabaseDriverMySQL, ""),
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`^CREATE USER 'eso_[a-z0-9]{12}'@'%' IDENTIFIED BY '[a-zA-Z0-9]{32}'$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "custom statements",
+			spec: databaseSpec(genv1alpha1.DatabaseDriverPostgreSQL, `  database: app
+  usernamePrefix: app_
+  passwordLength: 20
+  creationStatements:
+  - CREATE ROLE "{{ .username }}" WITH LOGIN PASSWORD '{{ .password }}' VALID UNTIL 'infinity'
+  - GRANT CONNECT ON DATABASE "{{ .database }}" TO "{{ .username }}"
+`),
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`^CREATE ROLE "app_[a-z0-9]{12}" WITH LOGIN PASSWORD '[a-zA-Z0-9]{20}' VALID UNTIL 'infinity'$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`^GRANT CONNECT ON DATABASE "app" TO "app_[a-z0-9]{12}"$`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, openDB := mockOpen(t)
+			tt.expect(mock)
+			mock.ExpectClose()
+
+			out, state, err := (&Generator{}).generate(context.Background(), tt.spec, nil, namespace, openDB)
+			require.NoError(t, err)
+			require.NotNil(t, state)
+			var userState genv1alpha1.DatabaseUserState
+			require.NoError(t, json.Unmarshal(state.Raw, &userState))
+			assert.Equal(t, string(out[keyUsername]), userState.Username)
+			assert.NotEmpty(t, out[keyPassword])
+		})
+	}
+}
+
+func TestGenerateRevokesOnFailure(t *testing.T) {
+	mock, openDB := mockOpen(t)
+	mock.ExpectExec(`^CREATE ROLE`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`^GRANT`).WillReturnError(errors.New("permission denied"))
+	mock.ExpectQuery(regexp.QuoteMeta(dialects[genv1alpha1.DatabaseDriverPostgreSQL].userExists)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`^DROP OWNED BY "eso_[a-z0-9]{12}"$`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`^DROP ROLE "eso_[a-z0-9]{12}"$`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectClose()
+
+	_, state, err := (&Generator{}).generate(context.Background(), databaseSpec(genv1alpha1.DatabaseDriverPostgreSQL, `  creationStatements:
+  - CREATE ROLE "{{ .username }}" WITH LOGIN PASSWORD '{{ .password }}'
+  - GRANT SELECT ON ALL TABLES IN SCHEMA public TO "{{ .username }}"
+`), nil, namespace, openDB)
+	assert.ErrorContains(t, err, "unable to execute statement 1: permission denied")
+	assert.Nil(t, state)
+}
+
+func TestGenerateInvalidTemplate(t *testing.T) {
+	mock, openDB := mockOpen(t)
+	mock.ExpectQuery(regexp.QuoteMeta(dialects[genv1alpha1.DatabaseDriverMySQL].userExists)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectClose()
+
+	_, _, err := (&Generator{}).generate(context.Background(), databaseSpec(genv1alpha1.DatabaseDriverMySQL, `  creationStatements:
+  - CREATE USER '{{ .missing }}'
+`), nil, namespace, openDB)
+	assert.ErrorContains(t, err, "unable to render statement 0")
+}
+
+func TestCleanup(t *testing.T) {
+	state := &apiextensions.JSON{Raw: []byte(`{"username":"eso_abc"}`)}
+
+	t.Run("drops the user", func(t *testing.T) {
+		mock, openDB := mockOpen(t)
+		mock.ExpectQuery(regexp.QuoteMeta(dialects[genv1alpha1.DatabaseDriverMySQL].userExists)).
+			WithArgs("eso_abc").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(`DROP USER 'eso_abc'@'%'`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectClose()
+		require.NoError(t, (&Generator{}).cleanup(context.Background(), databaseSpec(genv1alpha1.DatabaseDriverMySQL, ""), state, nil, namespace, openDB))
+	})
+
+	t.Run("custom revocation statements", func(t *testing.T) {
+		mock, openDB := mockOpen(t)
+		mock.ExpectQuery(regexp.QuoteMeta(dialects[genv1alpha1.DatabaseDriverPostgreSQL].userExists)).
+			WithArgs("eso_abc").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(regexp.QuoteMeta(`REASSIGN OWNED BY "eso_abc" TO "app"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`DROP ROLE "eso_abc"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectClose()
+		require.NoError(t, (&Generator{}).cleanup(context.Background(), databaseSpec(genv1alpha1.DatabaseDriverPostgreSQL, `  revocationStatements:
+  - REASSIGN OWNED BY "{{ .username }}" TO "app"
+  - DROP ROLE "{{ .username }}"
+`), state, nil, namespace, openDB))
+	})
+
+	t.Run("user does not exist anymore", func(t *testing.T) {
+		mock, openDB := mockOpen(t)
+		mock.ExpectQuery(regexp.QuoteMeta(dialects[genv1alpha1.DatabaseDriverPostgreSQL].userExists)).
+			WithArgs("eso_abc").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectClose()
+		require.NoError(t, (&Generator{}).cleanup(context.Background(), databaseSpec(genv1alpha1.DatabaseDriverPostgreSQL, ""), state, nil, namespace, openDB))
+	})
+
+	t.Run("no state", func(t *testing.T) {
+		require.NoError(t, (&Generator{}).Cleanup(context.Background(), nil, nil, nil, namespace))
+	})
+}
+
+func TestOpen(t *testing.T) {
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: namespace},
+		Data: map[string][]byte{
+			"username": []byte("postgres"),
+			"password": []byte("secret"),
+		},
+	}).Build()
+	auth := genv1alpha1.DatabaseUserAuth{
+		UsernameSecretRef: esmeta.SecretKeySelector{Name: "admin", Key: "username"},
+		PasswordSecretRef: esmeta.SecretKeySelector{Name: "admin", Key: "password"},
+	}
+
+	for _, driver := range []genv1alpha1.DatabaseDriver{genv1alpha1.DatabaseDriverPostgreSQL, genv1alpha1.DatabaseDriverMySQL} {
+		spec := &genv1alpha1.DatabaseUserSpec{Driver: driver, Host: "db.example.com", Auth: auth}
+		db, d, err := open(context.Background(), spec, kube, namespace)
+		require.NoError(t, err)
+		assert.Equal(t, d.defaultPort, spec.Port)
+		assert.NoError(t, db.Close())
+	}
+
+	_, _, err := open(context.Background(), &genv1alpha1.DatabaseUserSpec{Driver: "Oracle"}, kube, namespace)
+	assert.EqualError(t, err, `unknown driver "Oracle"`)
+
+	_, _, err = open(context.Background(), &genv1alpha1.DatabaseUserSpec{
+		Driver: genv1alpha1.DatabaseDriverMySQL,
+		Auth: genv1alpha1.DatabaseUserAuth{
+			UsernameSecretRef: esmeta.SecretKeySelector{Name: "missing", Key: "username"},
+		},
+	}, kube, namespace)
+	assert.ErrorContains(t, err, "unable to get admin credentials")
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	cfg, err := newTLSConfig(&genv1alpha1.DatabaseUserSpec{TLSMode: genv1alpha1.DatabaseTLSModeDisable})
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	cfg, err = newTLSConfig(&genv1alpha1.DatabaseUserSpec{TLSMode: genv1alpha1.DatabaseTLSModeRequire})
+	require.NoError(t, err)
+	assert.True(t, cfg.InsecureSkipVerify)
+
+	cfg, err = newTLSConfig(&genv1alpha1.DatabaseUserSpec{Host: "db.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, &tls.Config{ServerName: "db.example.com", MinVersion: tls.VersionTLS12}, cfg)
+
+	_, err = newTLSConfig(&genv1alpha1.DatabaseUserSpec{TLSMode: genv1alpha1.DatabaseTLSModeVerifyFull, CABundle: "not a certificate"})
+	assert.EqualError(t, err, errInvalidCABundle)
+}
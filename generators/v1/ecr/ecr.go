@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
@@ -58,6 +59,12 @@ const (
 
 // Generate creates an authentication token for AWS ECR.
 func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry creates an authentication token for AWS ECR and returns its expiry.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	return g.generate(ctx, jsonSpec, kube, namespace, ecrPrivateFactory, ecrPublicFactory)
 }
 
@@ -73,13 +80,13 @@ func (g *Generator) generate(
 	namespace string,
 	ecrPrivateFunc ecrPrivateFactoryFunc,
 	ecrPublicFunc ecrPublicFactoryFunc,
-) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	if jsonSpec == nil {
-		return nil, nil, errors.New(errNoSpec)
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
 	}
 	res, err := parseSpec(jsonSpec.Raw)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errParseSpec, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errParseSpec, err)
 	}
 	cfg, err := awsauth.NewGeneratorSession(
 		ctx,
@@ -94,7 +101,7 @@ func (g *Generator) generate(
 		awsauth.DefaultSTSProvider,
 		awsauth.DefaultJWTProvider)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errCreateSess, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errCreateSess, err)
 	}
 
 	if res.Spec.Scope == "public" {
@@ -104,57 +111,57 @@ func (g *Generator) generate(
 	return fetchECRPrivateToken(ctx, cfg, ecrPrivateFunc)
 }
 
-func fetchECRPrivateToken(ctx context.Context, cfg *aws.Config, ecrPrivateFunc ecrPrivateFactoryFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+func fetchECRPrivateToken(ctx context.Context, cfg *aws.Config, ecrPrivateFunc ecrPrivateFactoryFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	client := ecrPrivateFunc(cfg)
 	out, err := client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
 	if err != nil {
-		return nil, nil, fmt.Errorf(errGetPrivateToken, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errGetPrivateToken, err)
 	}
 	if len(out.AuthorizationData) != 1 {
-		return nil, nil, fmt.Errorf("unexpected number of authorization tokens. expected 1, found %d", len(out.AuthorizationData))
+		return nil, nil, time.Time{}, fmt.Errorf("unexpected number of authorization tokens. expected 1, found %d", len(out.AuthorizationData))
 	}
 
 	// AuthorizationToken is base64 encoded {username}:{password} string
 	decodedToken, err := base64.StdEncoding.DecodeString(*out.AuthorizationData[0].AuthorizationToken)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 	parts := strings.Split(string(decodedToken), ":")
 	if len(parts) != 2 {
-		return nil, nil, errors.New("unexpected token format")
+		return nil, nil, time.Time{}, errors.New("unexpected token format")
 	}
 
-	exp := out.AuthorizationData[0].ExpiresAt.UTC().Unix()
+	expiresAt := out.AuthorizationData[0].ExpiresAt.UTC()
 	return map[string][]byte{
 		"username":       []byte(parts[0]),
 		"password":       []byte(parts[1]),
 		"proxy_endpoint": []byte(*out.AuthorizationData[0].ProxyEndpoint),
-		"expires_at":     []byte(strconv.FormatInt(exp, 10)),
-	}, nil, nil
+		"expires_at":     []byte(strconv.FormatInt(expiresAt.Unix(), 10)),
+	}, nil, expiresAt, nil
 }
 
-func fetchECRPublicToken(ctx context.Context, cfg *aws.Config, ecrPublicFunc ecrPublicFactoryFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+func fetchECRPublicToken(ctx context.Context, cfg *aws.Config, ecrPublicFunc ecrPublicFactoryFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	client := ecrPublicFunc(cfg)
 	out, err := client.GetAuthorizationToken(ctx, &ecrpublic.GetAuthorizationTokenInput{})
 	if err != nil {
-		return nil, nil, fmt.Errorf(errGetPublicToken, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errGetPublicToken, err)
 	}
 
 	decodedToken, err := base64.StdEncoding.DecodeString(*out.AuthorizationData.AuthorizationToken)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 	parts := strings.Split(string(decodedToken), ":")
 	if len(parts) != 2 {
-		return nil, nil, errors.New("unexpected token format")
+		return nil, nil, time.Time{}, errors.New("unexpected token format")
 	}
 
-	exp := out.AuthorizationData.ExpiresAt.UTC().Unix()
+	expiresAt := out.AuthorizationData.ExpiresAt.UTC()
 	return map[string][]byte{
 		"username":   []byte(parts[0]),
 		"password":   []byte(parts[1]),
-		"expires_at": []byte(strconv.FormatInt(exp, 10)),
-	}, nil, nil
+		"expires_at": []byte(strconv.FormatInt(expiresAt.Unix(), 10)),
+	}, nil, expiresAt, nil
 }
 
 type ecrPrivateFactoryFunc func(aws *aws.Config) ecrAPI
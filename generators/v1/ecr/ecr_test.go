@@ -46,11 +46,12 @@ func TestGenerate(t *testing.T) {
 		authTokenPublicFunc  func(*ecrpublic.GetAuthorizationTokenInput) (*ecrpublic.GetAuthorizationTokenOutput, error)
 	}
 	tests := []struct {
-		name    string
-		g       *Generator
-		args    args
-		want    map[string][]byte
-		wantErr bool
+		name          string
+		g             *Generator
+		args          args
+		want          map[string][]byte
+		wantExpiresAt time.Time
+		wantErr       bool
 	}{
 		{
 			name: "nil spec",
@@ -123,6 +124,7 @@ spec:
 				"proxy_endpoint": []byte("foo"),
 				"expires_at":     []byte("1234"),
 			},
+			wantExpiresAt: time.Unix(1234, 0),
 		},
 		{
 			name: "public ECR full spec",
@@ -152,12 +154,13 @@ spec:
 				"password":   []byte("pubpass"),
 				"expires_at": []byte("5678"),
 			},
+			wantExpiresAt: time.Unix(5678, 0),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := &Generator{}
-			got, _, err := g.generate(
+			got, _, expiresAt, err := g.generate(
 				tt.args.ctx,
 				tt.args.jsonSpec,
 				tt.args.kube,
@@ -180,6 +183,9 @@ spec:
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Generator.Generate() = %v, want %v", got, tt.want)
 			}
+			if !expiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("Generator.Generate() expiry = %v, want %v", expiresAt, tt.wantExpiresAt)
+			}
 		})
 	}
 }
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"golang.org/x/oauth2"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -48,6 +49,12 @@ const (
 // Generate creates an authentication token for Google Container Registry.
 // It retrieves the token using the GCP credentials and returns it in the expected format.
 func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry creates an authentication token for Google Container Registry and returns its expiry.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	return g.generate(
 		ctx,
 		jsonSpec,
@@ -67,13 +74,13 @@ func (g *Generator) generate(
 	jsonSpec *apiextensions.JSON,
 	kube client.Client,
 	namespace string,
-	tokenSource tokenSourceFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	tokenSource tokenSourceFunc) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	if jsonSpec == nil {
-		return nil, nil, errors.New(errNoSpec)
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
 	}
 	res, err := parseSpec(jsonSpec.Raw)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errParseSpec, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errParseSpec, err)
 	}
 	ts, err := tokenSource(ctx, esv1.GCPSMAuth{
 		SecretRef:                  (*esv1.GCPSMAuthSecretRef)(res.Spec.Auth.SecretRef),
@@ -81,18 +88,18 @@ func (g *Generator) generate(
 		WorkloadIdentityFederation: res.Spec.Auth.WorkloadIdentityFederation,
 	}, res.Spec.ProjectID, resolvers.EmptyStoreKind, kube, namespace)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 	token, err := ts.Token()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 	exp := strconv.FormatInt(token.Expiry.UTC().Unix(), 10)
 	return map[string][]byte{
 		"username": []byte(defaultLoginUsername),
 		"password": []byte(token.AccessToken),
 		"expiry":   []byte(exp),
-	}, nil, nil
+	}, nil, token.Expiry, nil
 }
 
 type tokenSourceFunc func(ctx context.Context, auth esv1.GCPSMAuth, projectID string, storeKind string, kube client.Client, namespace string) (oauth2.TokenSource, error)
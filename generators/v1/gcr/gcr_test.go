@@ -96,7 +96,7 @@ spec:
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := &Generator{}
-			got, _, err := g.generate(
+			got, _, expiresAt, err := g.generate(
 				tt.args.ctx,
 				tt.args.jsonSpec,
 				tt.args.kube,
@@ -109,6 +109,9 @@ spec:
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Generator.Generate() = %v, want %v", got, tt.want)
 			}
+			if tt.want != nil && expiresAt.Unix() != 5555 {
+				t.Errorf("Generator.Generate() expiry = %v, want %v", expiresAt, time.Unix(5555, 0))
+			}
 		})
 	}
 }
@@ -68,6 +68,12 @@ const (
 // Generate creates an authentication token for GitHub.
 // It uses a GitHub App installation token to authenticate with GitHub API.
 func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry creates an authentication token for GitHub and returns its expiry.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	return g.generate(
 		ctx,
 		jsonSpec,
@@ -85,16 +91,16 @@ func (g *Generator) generate(
 	ctx context.Context,
 	jsonSpec *apiextensions.JSON,
 	kube client.Client,
-	namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	if jsonSpec == nil {
-		return nil, nil, errors.New(errNoSpec)
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
 	}
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
 	gh, err := newGHClient(ctx, kube, namespace, g.httpClient, jsonSpec)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating request: %w", err)
+		return nil, nil, time.Time{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	payload := make(map[string]any)
@@ -109,7 +115,7 @@ func (g *Generator) generate(
 	if len(payload) > 0 {
 		bodyBytes, err := json.Marshal(payload)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error marshaling payload: %w", err)
+			return nil, nil, time.Time{}, fmt.Errorf("error marshaling payload: %w", err)
 		}
 
 		body = bytes.NewReader(bodyBytes)
@@ -118,14 +124,14 @@ func (g *Generator) generate(
 	// Github api expects POST request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gh.URL, body)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating request: %w", err)
+		return nil, nil, time.Time{}, fmt.Errorf("error creating request: %w", err)
 	}
 	req.Header.Add("Authorization", "Bearer "+gh.InstallTkn)
 	req.Header.Add("Accept", "application/vnd.github.v3+json")
 
 	resp, err := gh.HTTP.Do(req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error performing request: %w", err)
+		return nil, nil, time.Time{}, fmt.Errorf("error performing request: %w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -134,23 +140,29 @@ func (g *Generator) generate(
 	// git access token
 	var gat map[string]any
 	if err := json.NewDecoder(resp.Body).Decode(&gat); err != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return nil, nil, fmt.Errorf("error decoding response: %w", err)
+		return nil, nil, time.Time{}, fmt.Errorf("error decoding response: %w", err)
 	}
 
 	if resp.StatusCode >= 300 {
 		if message, ok := gat["message"]; ok {
-			return nil, nil, fmt.Errorf("error generating token: response code: %d, response: %v", resp.StatusCode, message)
+			return nil, nil, time.Time{}, fmt.Errorf("error generating token: response code: %d, response: %v", resp.StatusCode, message)
 		}
-		return nil, nil, fmt.Errorf("error generating token, failed to extract error message from github request: response code: %d", resp.StatusCode)
+		return nil, nil, time.Time{}, fmt.Errorf("error generating token, failed to extract error message from github request: response code: %d", resp.StatusCode)
 	}
 
 	accessToken, ok := gat["token"].(string)
 	if !ok {
-		return nil, nil, errors.New("token isn't a string or token key doesn't exist")
+		return nil, nil, time.Time{}, errors.New("token isn't a string or token key doesn't exist")
+	}
+	// installation tokens expire after one hour, a missing or malformed
+	// expires_at simply leaves renewal to the refresh interval.
+	var expiresAt time.Time
+	if raw, ok := gat["expires_at"].(string); ok {
+		expiresAt, _ = time.Parse(time.RFC3339, raw)
 	}
 	return map[string][]byte{
 		defaultLoginUsername: []byte(accessToken),
-	}, nil, nil
+	}, nil, expiresAt, nil
 }
 
 func newGHClient(ctx context.Context, k client.Client, n string, hc *http.Client,
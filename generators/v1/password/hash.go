@@ -0,0 +1,130 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/GehirnInc/crypt/sha512_crypt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+const (
+	// argon2id parameters as recommended by OWASP.
+	argon2Time    = 2
+	argon2Memory  = 19 * 1024
+	argon2Threads = 1
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+
+	bcryptPrefix         = "$2a$"
+	htpasswdBcryptPrefix = "$2y$"
+
+	errUnknownHash      = "unknown hash algorithm %q"
+	errDuplicateHash    = "hash algorithm %q is configured more than once"
+	errHashKey          = "the key %q of the %s hash collides with another key"
+	errHtpasswdUsername = "the Htpasswd hash requires a username without colons and whitespace"
+)
+
+// hashKeySuffixes are appended to the key of a password to form the key of its hash.
+var hashKeySuffixes = map[genv1alpha1.PasswordHashAlgorithm]string{
+	genv1alpha1.PasswordHashBcrypt:      "bcrypt",
+	genv1alpha1.PasswordHashArgon2id:    "argon2id",
+	genv1alpha1.PasswordHashSHA512Crypt: "sha512crypt",
+	genv1alpha1.PasswordHashHtpasswd:    "htpasswd",
+}
+
+func hashKey(key string, algorithm genv1alpha1.PasswordHashAlgorithm) string {
+	return key + "_" + hashKeySuffixes[algorithm]
+}
+
+// validateHashes ensures that every hash is valid and that its keys do not collide with the keys of the passwords.
+func validateHashes(keys []string, hashes []genv1alpha1.PasswordHash) error {
+	seen := make(map[string]struct{}, len(keys)*(1+len(hashes)))
+	for _, key := range keys {
+		seen[key] = struct{}{}
+	}
+	algorithms := make(map[genv1alpha1.PasswordHashAlgorithm]struct{}, len(hashes))
+	for _, hash := range hashes {
+		if _, ok := hashKeySuffixes[hash.Algorithm]; !ok {
+			return fmt.Errorf(errUnknownHash, hash.Algorithm)
+		}
+		if _, ok := algorithms[hash.Algorithm]; ok {
+			return fmt.Errorf(errDuplicateHash, hash.Algorithm)
+		}
+		algorithms[hash.Algorithm] = struct{}{}
+		if hash.Algorithm == genv1alpha1.PasswordHashHtpasswd && !validHtpasswdUsername(hash.Username) {
+			return errors.New(errHtpasswdUsername)
+		}
+		for _, key := range keys {
+			k := hashKey(key, hash.Algorithm)
+			if _, ok := seen[k]; ok {
+				return fmt.Errorf(errHashKey, k, hash.Algorithm)
+			}
+			seen[k] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func validHtpasswdUsername(username string) bool {
+	return username != "" && !strings.ContainsFunc(username, func(r rune) bool {
+		return r == ':' || unicode.IsSpace(r)
+	})
+}
+
+// hashPassword derives the hash of the password with the given algorithm.
+func hashPassword(pass []byte, hash genv1alpha1.PasswordHash) ([]byte, error) {
+	switch hash.Algorithm {
+	case genv1alpha1.PasswordHashBcrypt:
+		return bcrypt.GenerateFromPassword(pass, bcrypt.DefaultCost)
+	case genv1alpha1.PasswordHashArgon2id:
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		key := argon2.IDKey(pass, salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+		return fmt.Appendf(nil, "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, argon2Memory, argon2Time, argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key),
+		), nil
+	case genv1alpha1.PasswordHashSHA512Crypt:
+		hashed, err := sha512_crypt.New().Generate(pass, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(hashed), nil
+	case genv1alpha1.PasswordHashHtpasswd:
+		hashed, err := bcrypt.GenerateFromPassword(pass, bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		// htpasswd writes bcrypt hashes with the $2y$ prefix, which is the same algorithm.
+		return []byte(hash.Username + ":" + htpasswdBcryptPrefix + strings.TrimPrefix(string(hashed), bcryptPrefix)), nil
+	default:
+		return nil, fmt.Errorf(errUnknownHash, hash.Algorithm)
+	}
+}
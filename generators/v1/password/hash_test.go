@@ -0,0 +1,155 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/GehirnInc/crypt/sha512_crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+func TestHashPassword(t *testing.T) {
+	pass := []byte("correct-horse-battery-staple")
+
+	t.Run("bcrypt", func(t *testing.T) {
+		hashed, err := hashPassword(pass, genv1alpha1.PasswordHash{Algorithm: genv1alpha1.PasswordHashBcrypt})
+		require.NoError(t, err)
+		assert.NoError(t, bcrypt.CompareHashAndPassword(hashed, pass))
+	})
+
+	t.Run("argon2id", func(t *testing.T) {
+		hashed, err := hashPassword(pass, genv1alpha1.PasswordHash{Algorithm: genv1alpha1.PasswordHashArgon2id})
+		require.NoError(t, err)
+		parts := strings.Split(string(hashed), "$")
+		require.Len(t, parts, 6)
+		assert.Equal(t, []string{"", "argon2id", "v=19", "m=19456,t=2,p=1"}, parts[:4])
+		salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+		require.NoError(t, err)
+		assert.Equal(t, base64.RawStdEncoding.EncodeToString(argon2.IDKey(pass, salt, 2, 19456, 1, 32)), parts[5])
+	})
+
+	t.Run("sha512crypt", func(t *testing.T) {
+		hashed, err := hashPassword(pass, genv1alpha1.PasswordHash{Algorithm: genv1alpha1.PasswordHashSHA512Crypt})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(hashed), "$6$"))
+		assert.NoError(t, sha512_crypt.New().Verify(string(hashed), pass))
+	})
+
+	t.Run("htpasswd", func(t *testing.T) {
+		hashed, err := hashPassword(pass, genv1alpha1.PasswordHash{Algorithm: genv1alpha1.PasswordHashHtpasswd, Username: "admin"})
+		require.NoError(t, err)
+		username, hash, ok := strings.Cut(string(hashed), ":")
+		require.True(t, ok)
+		assert.Equal(t, "admin", username)
+		assert.True(t, strings.HasPrefix(hash, "$2y$10$"))
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), pass))
+	})
+
+	t.Run("bcrypt rejects long passwords", func(t *testing.T) {
+		_, err := hashPassword([]byte(strings.Repeat("a", 73)), genv1alpha1.PasswordHash{Algorithm: genv1alpha1.PasswordHashBcrypt})
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateHashes(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string
+		hashes  []genv1alpha1.PasswordHash
+		wantErr string
+	}{
+		{
+			name: "valid hashes",
+			keys: []string{"password", "other"},
+			hashes: []genv1alpha1.PasswordHash{
+				{Algorithm: genv1alpha1.PasswordHashBcrypt},
+				{Algorithm: genv1alpha1.PasswordHashHtpasswd, Username: "admin"},
+			},
+		},
+		{
+			name:    "unknown algorithm",
+			keys:    []string{"password"},
+			hashes:  []genv1alpha1.PasswordHash{{Algorithm: "MD5"}},
+			wantErr: `unknown hash algorithm "MD5"`,
+		},
+		{
+			name: "duplicate algorithm",
+			keys: []string{"password"},
+			hashes: []genv1alpha1.PasswordHash{
+				{Algorithm: genv1alpha1.PasswordHashBcrypt},
+				{Algorithm: genv1alpha1.PasswordHashBcrypt},
+			},
+			wantErr: `hash algorithm "Bcrypt" is configured more than once`,
+		},
+		{
+			name:    "hash key collides with a secret key",
+			keys:    []string{"password", "password_bcrypt"},
+			hashes:  []genv1alpha1.PasswordHash{{Algorithm: genv1alpha1.PasswordHashBcrypt}},
+			wantErr: `the key "password_bcrypt" of the Bcrypt hash collides with another key`,
+		},
+		{
+			name:    "htpasswd without username",
+			keys:    []string{"password"},
+			hashes:  []genv1alpha1.PasswordHash{{Algorithm: genv1alpha1.PasswordHashHtpasswd}},
+			wantErr: errHtpasswdUsername,
+		},
+		{
+			name:    "htpasswd username with a colon",
+			keys:    []string{"password"},
+			hashes:  []genv1alpha1.PasswordHash{{Algorithm: genv1alpha1.PasswordHashHtpasswd, Username: "a:b"}},
+			wantErr: errHtpasswdUsername,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHashes(tt.keys, tt.hashes)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGenerateWithHashes(t *testing.T) {
+	got, _, err := (&Generator{}).generate(&apiextensions.JSON{
+		Raw: []byte(`{"spec":{"encoding":"hex","hashes":[{"algorithm":"Bcrypt"},{"algorithm":"SHA512Crypt"}]}}`),
+	}, func(int, int, string, int, bool, bool) (string, error) {
+		return "foobar", nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	// the hashes are derived from the encoded password.
+	assert.Equal(t, "666f6f626172", string(got["password"]))
+	assert.NoError(t, bcrypt.CompareHashAndPassword(got["password_bcrypt"], got["password"]))
+	assert.NoError(t, sha512_crypt.New().Verify(string(got["password_sha512crypt"]), got["password"]))
+
+	_, _, err = (&Generator{}).generate(&apiextensions.JSON{
+		Raw: []byte(`{"spec":{"hashes":[{"algorithm":"Htpasswd"}]}}`),
+	}, nil)
+	assert.EqualError(t, err, errHtpasswdUsername)
+}
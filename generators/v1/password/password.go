@@ -46,6 +46,7 @@ const (
 	errParseSpec = "unable to parse spec: %w"
 	errGetToken  = "unable to get authorization token: %w"
 	errSecretKey = "secretKeys must be non-empty and unique"
+	errHash      = "unable to derive %s hash: %w"
 )
 
 type generateFunc func(
@@ -84,8 +85,11 @@ func (g *Generator) generate(jsonSpec *apiextensions.JSON, passGen generateFunc)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := validateHashes(keys, config.hashes); err != nil {
+		return nil, nil, err
+	}
 
-	passwords, err := generatePasswords(keys, config, passGen)
+	passwords, err := generatePasswords(keys, config, newPasswordFunc(config, passGen))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -101,6 +105,11 @@ type passwordConfig struct {
 	encoding         string
 	noUpper          bool
 	allowRepeat      bool
+
+	minCharacters     *genv1alpha1.PasswordMinCharacters
+	excludeCharacters string
+	passphrase        *genv1alpha1.PasswordPassphrase
+	hashes            []genv1alpha1.PasswordHash
 }
 
 func extractPasswordConfig(res *genv1alpha1.Password) passwordConfig {
@@ -129,6 +138,12 @@ func extractPasswordConfig(res *genv1alpha1.Password) passwordConfig {
 	}
 	config.noUpper = res.Spec.NoUpper
 	config.allowRepeat = res.Spec.AllowRepeat
+	config.minCharacters = res.Spec.MinCharacters
+	if res.Spec.ExcludeCharacters != nil {
+		config.excludeCharacters = *res.Spec.ExcludeCharacters
+	}
+	config.passphrase = res.Spec.Passphrase
+	config.hashes = res.Spec.Hashes
 
 	return config
 }
@@ -150,21 +165,49 @@ func validateSecretKeys(keys []string) ([]string, error) {
 	return keys, nil
 }
 
-func generatePasswords(keys []string, config passwordConfig, passGen generateFunc) (map[string][]byte, error) {
-	passwords := make(map[string][]byte, len(keys))
+// newPasswordFunc returns the function which generates a single password for the given config.
+// The policy generator is only used if the config requires it, so existing specs keep generating
+// passwords with the same characteristics.
+func newPasswordFunc(config passwordConfig, passGen generateFunc) func() (string, error) {
+	switch {
+	case config.passphrase != nil:
+		return func() (string, error) {
+			return generatePassphrase(config.passphrase)
+		}
+	case config.minCharacters != nil || config.excludeCharacters != "":
+		return func() (string, error) {
+			return generatePolicyPassword(config)
+		}
+	default:
+		return func() (string, error) {
+			return passGen(
+				config.length,
+				config.symbols,
+				config.symbolCharacters,
+				config.digits,
+				config.noUpper,
+				config.allowRepeat,
+			)
+		}
+	}
+}
+
+func generatePasswords(keys []string, config passwordConfig, next func() (string, error)) (map[string][]byte, error) {
+	passwords := make(map[string][]byte, len(keys)*(1+len(config.hashes)))
 	for _, key := range keys {
-		pass, err := passGen(
-			config.length,
-			config.symbols,
-			config.symbolCharacters,
-			config.digits,
-			config.noUpper,
-			config.allowRepeat,
-		)
+		pass, err := next()
 		if err != nil {
 			return nil, err
 		}
-		passwords[key] = encodePassword([]byte(pass), config.encoding)
+		encoded := encodePassword([]byte(pass), config.encoding)
+		passwords[key] = encoded
+		for _, hash := range config.hashes {
+			hashed, err := hashPassword(encoded, hash)
+			if err != nil {
+				return nil, fmt.Errorf(errHash, hash.Algorithm, err)
+			}
+			passwords[hashKey(key, hash.Algorithm)] = hashed
+		}
 	}
 	return passwords, nil
 }
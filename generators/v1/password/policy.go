@@ -0,0 +1,197 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/sethvargo/go-diceware/diceware"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+const (
+	lowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	upperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars   = "0123456789"
+
+	defaultPassphraseWords     = 6
+	defaultPassphraseSeparator = "-"
+
+	errExceedsLength   = "the number of required characters %d exceeds the length %d"
+	errNegativeCount   = "the number of %s must not be negative"
+	errEmptyClass      = "no %s left after excluding characters"
+	errNotEnoughUnique = "not enough unique %s, set allowRepeat to allow repeating characters"
+	errNoUpper         = "minCharacters.upper requires noUpper to be false"
+)
+
+// charClass is a set of characters of which a password contains exactly count characters.
+type charClass struct {
+	name  string
+	chars []rune
+	count int
+}
+
+// generatePolicyPassword generates a password of random characters which honours
+// the minimum number of characters of each class and the excluded characters.
+func generatePolicyPassword(config passwordConfig) (string, error) {
+	classes, err := policyClasses(config)
+	if err != nil {
+		return "", err
+	}
+
+	pass := make([]rune, 0, config.length)
+	used := make(map[rune]struct{}, config.length)
+	for _, class := range classes {
+		for range class.count {
+			c, err := randomChar(class, used, config.allowRepeat)
+			if err != nil {
+				return "", err
+			}
+			pass = append(pass, c)
+			used[c] = struct{}{}
+		}
+	}
+
+	// the classes are filled in order, so the characters are shuffled afterwards.
+	for i := len(pass) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		pass[i], pass[j] = pass[j], pass[i]
+	}
+	return string(pass), nil
+}
+
+// policyClasses returns the character classes of a password, the last class fills up the remaining length.
+// With MinCharacters every class contributes its minimum and the remainder is drawn from all classes.
+// Otherwise the password contains exactly the configured number of digits and symbols, like passwords without a policy.
+func policyClasses(config passwordConfig) ([]charClass, error) {
+	lower := excludeChars(lowerLetters, config.excludeCharacters)
+	upper := excludeChars(upperLetters, config.excludeCharacters)
+	if config.noUpper {
+		upper = nil
+	}
+	digits := excludeChars(digitChars, config.excludeCharacters)
+	symbols := excludeChars(config.symbolCharacters, config.excludeCharacters)
+
+	var classes []charClass
+	var rest charClass
+	if minimums := config.minCharacters; minimums != nil {
+		if config.noUpper && minimums.Upper > 0 {
+			return nil, errors.New(errNoUpper)
+		}
+		classes = []charClass{
+			{name: "lowercase letters", chars: lower, count: minimums.Lower},
+			{name: "uppercase letters", chars: upper, count: minimums.Upper},
+			{name: "digits", chars: digits, count: minimums.Digits},
+			{name: "symbols", chars: symbols, count: minimums.Symbols},
+		}
+		rest = charClass{name: "characters", chars: slices.Concat(lower, upper, digits, symbols)}
+	} else {
+		classes = []charClass{
+			{name: "digits", chars: digits, count: config.digits},
+			{name: "symbols", chars: symbols, count: config.symbols},
+		}
+		rest = charClass{name: "letters", chars: slices.Concat(lower, upper)}
+	}
+
+	required := 0
+	for _, class := range classes {
+		if class.count < 0 {
+			return nil, fmt.Errorf(errNegativeCount, class.name)
+		}
+		if class.count > 0 && len(class.chars) == 0 {
+			return nil, fmt.Errorf(errEmptyClass, class.name)
+		}
+		required += class.count
+	}
+	if required > config.length {
+		return nil, fmt.Errorf(errExceedsLength, required, config.length)
+	}
+	rest.count = config.length - required
+	if rest.count > 0 && len(rest.chars) == 0 {
+		return nil, fmt.Errorf(errEmptyClass, rest.name)
+	}
+	return append(classes, rest), nil
+}
+
+// randomChar picks a random character of the class, which is not used yet unless allowRepeat is set.
+func randomChar(class charClass, used map[rune]struct{}, allowRepeat bool) (rune, error) {
+	chars := class.chars
+	if !allowRepeat {
+		chars = slices.DeleteFunc(slices.Clone(chars), func(c rune) bool {
+			_, ok := used[c]
+			return ok
+		})
+		if len(chars) == 0 {
+			return 0, fmt.Errorf(errNotEnoughUnique, class.name)
+		}
+	}
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// excludeChars returns the unique characters of chars which are not part of exclude.
+func excludeChars(chars, exclude string) []rune {
+	var out []rune
+	for _, c := range chars {
+		if !strings.ContainsRune(exclude, c) && !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// generatePassphrase generates a diceware passphrase from the EFF large wordlist.
+func generatePassphrase(passphrase *genv1alpha1.PasswordPassphrase) (string, error) {
+	numWords := defaultPassphraseWords
+	if passphrase.Words > 0 {
+		numWords = passphrase.Words
+	}
+	separator := defaultPassphraseSeparator
+	if passphrase.Separator != nil {
+		separator = *passphrase.Separator
+	}
+	words, err := diceware.Generate(numWords)
+	if err != nil {
+		return "", err
+	}
+	if passphrase.Capitalize {
+		for i, word := range words {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, separator), nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+func countChars(s, chars string) int {
+	n := 0
+	for _, c := range s {
+		if strings.ContainsRune(chars, c) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGeneratePolicyPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  passwordConfig
+		check   func(t *testing.T, pass string)
+		wantErr string
+	}{
+		{
+			name: "minimum characters of each class",
+			config: passwordConfig{
+				length:           16,
+				symbolCharacters: "-_",
+				allowRepeat:      true,
+				minCharacters:    &genv1alpha1.PasswordMinCharacters{Lower: 2, Upper: 3, Digits: 4, Symbols: 5},
+			},
+			check: func(t *testing.T, pass string) {
+				assert.GreaterOrEqual(t, countChars(pass, lowerLetters), 2)
+				assert.GreaterOrEqual(t, countChars(pass, upperLetters), 3)
+				assert.GreaterOrEqual(t, countChars(pass, digitChars), 4)
+				assert.GreaterOrEqual(t, countChars(pass, "-_"), 5)
+			},
+		},
+		{
+			name: "excluded characters are never used",
+			config: passwordConfig{
+				length:            62,
+				symbolCharacters:  "-_",
+				digits:            10,
+				symbols:           2,
+				excludeCharacters: "0O1lI_",
+				allowRepeat:       true,
+			},
+			check: func(t *testing.T, pass string) {
+				assert.Zero(t, countChars(pass, "0O1lI_"))
+				// without minimums, the number of digits and symbols is exact.
+				assert.Equal(t, 10, countChars(pass, digitChars))
+				assert.Equal(t, 2, countChars(pass, "-"))
+			},
+		},
+		{
+			name: "noUpper removes uppercase letters from the remainder",
+			config: passwordConfig{
+				length:           40,
+				symbolCharacters: "-",
+				noUpper:          true,
+				allowRepeat:      true,
+				minCharacters:    &genv1alpha1.PasswordMinCharacters{Digits: 1},
+			},
+			check: func(t *testing.T, pass string) {
+				assert.Zero(t, countChars(pass, upperLetters))
+			},
+		},
+		{
+			name: "characters are unique without allowRepeat",
+			config: passwordConfig{
+				length:            36,
+				symbolCharacters:  "-",
+				excludeCharacters: "ABCDEFGHIJKLMNOPQRSTUVWXYZ-",
+				minCharacters:     &genv1alpha1.PasswordMinCharacters{Digits: 10},
+			},
+			check: func(t *testing.T, pass string) {
+				seen := map[rune]bool{}
+				for _, c := range pass {
+					assert.False(t, seen[c], "repeated character %q", c)
+					seen[c] = true
+				}
+			},
+		},
+		{
+			name: "not enough unique characters",
+			config: passwordConfig{
+				length:            24,
+				symbolCharacters:  "-",
+				digits:            11,
+				excludeCharacters: "x",
+			},
+			wantErr: "not enough unique digits, set allowRepeat to allow repeating characters",
+		},
+		{
+			name: "minimums exceed the length",
+			config: passwordConfig{
+				length:           8,
+				symbolCharacters: "-",
+				minCharacters:    &genv1alpha1.PasswordMinCharacters{Lower: 4, Upper: 4, Digits: 1},
+			},
+			wantErr: "the number of required characters 9 exceeds the length 8",
+		},
+		{
+			name: "minimum of an excluded class",
+			config: passwordConfig{
+				length:            8,
+				symbolCharacters:  "-",
+				excludeCharacters: digitChars,
+				minCharacters:     &genv1alpha1.PasswordMinCharacters{Digits: 1},
+			},
+			wantErr: "no digits left after excluding characters",
+		},
+		{
+			name: "uppercase minimum with noUpper",
+			config: passwordConfig{
+				length:           8,
+				symbolCharacters: "-",
+				noUpper:          true,
+				minCharacters:    &genv1alpha1.PasswordMinCharacters{Upper: 1},
+			},
+			wantErr: errNoUpper,
+		},
+		{
+			name: "negative minimum",
+			config: passwordConfig{
+				length:           8,
+				symbolCharacters: "-",
+				minCharacters:    &genv1alpha1.PasswordMinCharacters{Symbols: -1},
+			},
+			wantErr: "the number of symbols must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				pass, err := generatePolicyPassword(tt.config)
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Len(t, []rune(pass), tt.config.length)
+				tt.check(t, pass)
+			}
+		})
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	pass, err := generatePassphrase(&genv1alpha1.PasswordPassphrase{})
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(pass, defaultPassphraseSeparator), defaultPassphraseWords)
+
+	separator := " "
+	pass, err = generatePassphrase(&genv1alpha1.PasswordPassphrase{Words: 8, Separator: &separator, Capitalize: true})
+	require.NoError(t, err)
+	words := strings.Split(pass, separator)
+	assert.Len(t, words, 8)
+	for _, word := range words {
+		assert.True(t, unicode.IsUpper([]rune(word)[0]), "word %q is not capitalized", word)
+	}
+}
+
+func TestGenerateWithPolicy(t *testing.T) {
+	got, _, err := (&Generator{}).generate(&apiextensions.JSON{
+		Raw: []byte(`{"spec":{"length":20,"excludeCharacters":"0O1lI","minCharacters":{"upper":2,"digits":2}}}`),
+	}, nil)
+	require.NoError(t, err)
+	assert.Len(t, got["password"], 20)
+	assert.Zero(t, countChars(string(got["password"]), "0O1lI"))
+
+	got, _, err = (&Generator{}).generate(&apiextensions.JSON{
+		Raw: []byte(`{"spec":{"passphrase":{"words":5,"separator":"."},"secretKeys":["a","b"]}}`),
+	}, nil)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(string(got["a"]), "."), 5)
+	assert.Len(t, strings.Split(string(got["b"]), "."), 5)
+}
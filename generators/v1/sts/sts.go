@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -52,6 +53,12 @@ const (
 
 // Generate creates AWS STS session tokens and returns credentials.
 func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry creates AWS STS session tokens and returns the expiration of the credentials.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	return g.generate(ctx, jsonSpec, kube, namespace, stsFactory)
 }
 
@@ -61,16 +68,16 @@ func (g *Generator) generate(
 	kube client.Client,
 	namespace string,
 	stsFunc stsFactoryFunc,
-) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	if jsonSpec == nil {
-		return nil, nil, errors.New(errNoSpec)
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
 	}
 	res, err := parseSpec(jsonSpec.Raw)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errParseSpec, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errParseSpec, err)
 	}
 	if res.Spec.Auth.JWTAuth != nil {
-		return nil, nil, errors.New("jwt auth cannot be used for STS Session Token generation")
+		return nil, nil, time.Time{}, errors.New("jwt auth cannot be used for STS Session Token generation")
 	}
 	cfg, err := awsauth.NewGeneratorSession(
 		ctx,
@@ -84,7 +91,7 @@ func (g *Generator) generate(
 		awsauth.DefaultSTSProvider,
 		awsauth.DefaultJWTProvider)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errCreateSess, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errCreateSess, err)
 	}
 	api := stsFunc(cfg)
 	input := &sts.GetSessionTokenInput{}
@@ -95,18 +102,19 @@ func (g *Generator) generate(
 	}
 	out, err := api.GetSessionToken(ctx, input)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errGetToken, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errGetToken, err)
 	}
 	if out.Credentials == nil {
-		return nil, nil, errors.New("no credentials found")
+		return nil, nil, time.Time{}, errors.New("no credentials found")
 	}
 
+	expiration := aws.ToTime(out.Credentials.Expiration)
 	return map[string][]byte{
 		"access_key_id":     []byte(*out.Credentials.AccessKeyId),
-		"expiration":        []byte(strconv.FormatInt(out.Credentials.Expiration.Unix(), 10)),
+		"expiration":        []byte(strconv.FormatInt(expiration.Unix(), 10)),
 		"secret_access_key": []byte(*out.Credentials.SecretAccessKey),
 		"session_token":     []byte(*out.Credentials.SessionToken),
-	}, nil, nil
+	}, nil, expiration, nil
 }
 
 // Cleanup is a no-op for STS generator as it doesn't require any cleanup.
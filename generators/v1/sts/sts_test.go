@@ -124,7 +124,7 @@ spec:
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := &Generator{}
-			got, _, err := g.generate(
+			got, _, expiresAt, err := g.generate(
 				tt.args.ctx,
 				tt.args.jsonSpec,
 				tt.args.kube,
@@ -142,6 +142,9 @@ spec:
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Generator.Generate() = %v, want %v", got, tt.want)
 			}
+			if tt.want != nil && expiresAt.Unix() != 1234 {
+				t.Errorf("Generator.Generate() expiry = %v, want %v", expiresAt, time.Unix(1234, 0))
+			}
 		})
 	}
 }
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	vault "github.com/hashicorp/vault/api"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -49,6 +50,12 @@ const (
 
 // Generate creates dynamic credentials using HashiCorp Vault's secrets engines.
 func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+	data, state, _, err := g.GenerateWithExpiry(ctx, jsonSpec, kube, namespace)
+	return data, state, err
+}
+
+// GenerateWithExpiry creates dynamic credentials and returns the end of their lease.
+func (g *Generator) GenerateWithExpiry(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	c := &provider.Provider{NewVaultClient: provider.NewVaultClient}
 
 	// controller-runtime/client does not support TokenRequest or other subresource APIs
@@ -56,11 +63,11 @@ func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON,
 	// (for Kubernetes service account token auth)
 	restCfg, err := ctrlcfg.GetConfig()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 	clientset, err := kubernetes.NewForConfig(restCfg)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 
 	return g.generate(ctx, c, jsonSpec, kube, clientset.CoreV1(), namespace)
@@ -78,35 +85,39 @@ func (g *Generator) generate(
 	kube client.Client,
 	corev1 typedcorev1.CoreV1Interface,
 	namespace string,
-) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
+) (map[string][]byte, genv1alpha1.GeneratorProviderState, time.Time, error) {
 	if jsonSpec == nil {
-		return nil, nil, errors.New(errNoSpec)
+		return nil, nil, time.Time{}, errors.New(errNoSpec)
 	}
 	spec, err := parseSpec(jsonSpec.Raw)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errParseSpec, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errParseSpec, err)
 	}
 	if spec == nil || spec.Spec.Provider == nil {
-		return nil, nil, errors.New("no Vault provider config in spec")
+		return nil, nil, time.Time{}, errors.New("no Vault provider config in spec")
 	}
 	cl, err := c.NewGeneratorClient(ctx, kube, corev1, spec.Spec.Provider, namespace, spec.Spec.RetrySettings)
 	if err != nil {
-		return nil, nil, fmt.Errorf(errVaultClient, err)
+		return nil, nil, time.Time{}, fmt.Errorf(errVaultClient, err)
 	}
 
 	result, err := g.fetchVaultSecret(ctx, spec, cl)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, time.Time{}, err
 	}
 
 	if result == nil && spec.Spec.AllowEmptyResponse {
-		return nil, nil, nil
+		return nil, nil, time.Time{}, nil
 	}
 
 	if result == nil {
-		return nil, nil, fmt.Errorf(errGetSecret, errors.New("empty response from Vault"))
+		return nil, nil, time.Time{}, fmt.Errorf(errGetSecret, errors.New("empty response from Vault"))
+	}
+	response, state, err := g.prepareResponse(spec, result)
+	if err != nil {
+		return nil, nil, time.Time{}, err
 	}
-	return g.prepareResponse(spec, result)
+	return response, state, leaseExpiry(spec, result, time.Now()), nil
 }
 
 func (g *Generator) fetchVaultSecret(ctx context.Context, res *genv1alpha1.VaultDynamicSecret, cl vaultutil.Client) (*vault.Secret, error) {
@@ -170,6 +181,19 @@ func (g *Generator) prepareResponse(res *genv1alpha1.VaultDynamicSecret, result
 	return response, nil, nil
 }
 
+// leaseExpiry returns the end of the lease of the secret or the token,
+// or the zero time if Vault did not lease it.
+func leaseExpiry(res *genv1alpha1.VaultDynamicSecret, result *vault.Secret, now time.Time) time.Time {
+	leaseDuration := result.LeaseDuration
+	if res.Spec.ResultType == genv1alpha1.VaultDynamicSecretResultTypeAuth && result.Auth != nil {
+		leaseDuration = result.Auth.LeaseDuration
+	}
+	if leaseDuration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(time.Duration(leaseDuration) * time.Second)
+}
+
 func parseSpec(data []byte) (*genv1alpha1.VaultDynamicSecret, error) {
 	var spec genv1alpha1.VaultDynamicSecret
 	err := yaml.Unmarshal(data, &spec)
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	vaultapi "github.com/hashicorp/vault/api"
@@ -30,6 +31,7 @@ import (
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
 	provider "github.com/external-secrets/external-secrets/providers/v1/vault"
 	"github.com/external-secrets/external-secrets/providers/v1/vault/fake"
 	vaultutil "github.com/external-secrets/external-secrets/providers/v1/vault/util"
@@ -389,7 +391,7 @@ spec:
 			}
 			c := &provider.Provider{NewVaultClient: newClientFn}
 			gen := &Generator{}
-			val, _, err := gen.generate(context.Background(), c, tc.args.jsonSpec, tc.args.kube, tc.args.corev1, "testing")
+			val, _, _, err := gen.generate(context.Background(), c, tc.args.jsonSpec, tc.args.kube, tc.args.corev1, "testing")
 			if tc.want.err != nil {
 				if err != nil {
 					if diff := cmp.Diff(tc.want.err.Error(), err.Error()); diff != "" {
@@ -445,7 +447,7 @@ spec:
       - "prod"
       - "blue"
   path: "github/token/example"`)}
-		_, _, err := (&Generator{}).generate(context.Background(),
+		_, _, _, err := (&Generator{}).generate(context.Background(),
 			c, spec,
 			clientfake.NewClientBuilder().WithObjects(sa).Build(),
 			utilfake.NewCreateTokenMock().WithToken("ok"), "testing")
@@ -487,7 +489,7 @@ spec:
     nested:
       key: "value"
   path: "github/token/example"`)}
-		_, _, err := (&Generator{}).generate(context.Background(),
+		_, _, _, err := (&Generator{}).generate(context.Background(),
 			c, spec,
 			clientfake.NewClientBuilder().WithObjects(sa).Build(),
 			utilfake.NewCreateTokenMock().WithToken("ok"), "testing")
@@ -502,3 +504,38 @@ spec:
 		}
 	})
 }
+
+func TestLeaseExpiry(t *testing.T) {
+	now := time.Date(2024, 10, 11, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		resultType genv1alpha1.VaultDynamicSecretResultType
+		secret     *vaultapi.Secret
+		want       time.Time
+	}{
+		"SecretLease": {
+			secret: &vaultapi.Secret{LeaseDuration: 3600},
+			want:   now.Add(time.Hour),
+		},
+		"NoLease": {
+			secret: &vaultapi.Secret{},
+		},
+		"AuthLease": {
+			resultType: genv1alpha1.VaultDynamicSecretResultTypeAuth,
+			secret:     &vaultapi.Secret{Auth: &vaultapi.SecretAuth{LeaseDuration: 60}},
+			want:       now.Add(time.Minute),
+		},
+		"AuthWithoutAuthBlock": {
+			resultType: genv1alpha1.VaultDynamicSecretResultTypeAuth,
+			secret:     &vaultapi.Secret{LeaseDuration: 120},
+			want:       now.Add(2 * time.Minute),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := &genv1alpha1.VaultDynamicSecret{Spec: genv1alpha1.VaultDynamicSecretSpec{ResultType: tc.resultType}}
+			if got := leaseExpiry(res, tc.secret, now); !got.Equal(tc.want) {
+				t.Errorf("leaseExpiry() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
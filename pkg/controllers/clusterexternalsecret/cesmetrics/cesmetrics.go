@@ -19,6 +19,7 @@ package cesmetrics
 
 import (
 	"maps"
+	"slices"
 
 	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
@@ -34,6 +35,8 @@ const (
 	ClusterExternalSecretSubsystem            = "clusterexternalsecret"
 	ClusterExternalSecretReconcileDurationKey = "reconcile_duration"
 	ClusterExternalSecretStatusConditionKey   = "status_condition"
+	ClusterExternalSecretChildrenKey          = "children"
+	ClusterExternalSecretOldestFailureKey     = "oldest_failure_timestamp_seconds"
 )
 
 var gaugeVecMetrics = map[string]*prometheus.GaugeVec{}
@@ -53,11 +56,25 @@ func SetUpMetrics() {
 		Help:      "The status condition of a specific Cluster External Secret",
 	}, ctrlmetrics.ConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(clusterExternalSecretReconcileDuration, clusterExternalSecretCondition)
+	clusterExternalSecretChildren := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ClusterExternalSecretSubsystem,
+		Name:      ClusterExternalSecretChildrenKey,
+		Help:      "The number of External Secrets created by a specific Cluster External Secret, by their Ready status",
+	}, append(slices.Clone(ctrlmetrics.NonConditionMetricLabelNames), "status"))
+
+	clusterExternalSecretOldestFailure := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ClusterExternalSecretSubsystem,
+		Name:      ClusterExternalSecretOldestFailureKey,
+		Help:      "The unix time the longest failing External Secret created by a specific Cluster External Secret started failing",
+	}, append(slices.Clone(ctrlmetrics.NonConditionMetricLabelNames), "target_namespace", "reason"))
+
+	metrics.Registry.MustRegister(clusterExternalSecretReconcileDuration, clusterExternalSecretCondition, clusterExternalSecretChildren, clusterExternalSecretOldestFailure)
 
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
 		ClusterExternalSecretStatusConditionKey:   clusterExternalSecretCondition,
 		ClusterExternalSecretReconcileDurationKey: clusterExternalSecretReconcileDuration,
+		ClusterExternalSecretChildrenKey:          clusterExternalSecretChildren,
+		ClusterExternalSecretOldestFailureKey:     clusterExternalSecretOldestFailure,
 	}
 }
 
@@ -99,6 +116,40 @@ func UpdateClusterExternalSecretCondition(ces *esv1.ClusterExternalSecret, condi
 		})).Set(0)
 }
 
+// UpdateClusterExternalSecretChildren updates the metrics for the ExternalSecrets created by a ClusterExternalSecret,
+// based on the aggregated status of the ExternalSecrets.
+func UpdateClusterExternalSecretChildren(ces *esv1.ClusterExternalSecret) {
+	children := ces.Status.Children
+	if children == nil {
+		return
+	}
+
+	cesInfo := make(map[string]string)
+	cesInfo["name"] = ces.Name
+	maps.Copy(cesInfo, ces.Labels)
+	baseLabels := ctrlmetrics.RefineNonConditionMetricLabels(cesInfo)
+	withLabels := func(labels prometheus.Labels) prometheus.Labels {
+		refined := maps.Clone(baseLabels)
+		maps.Copy(refined, labels)
+		return refined
+	}
+
+	// This handles cases where labels, the failing namespace or the reason have changed
+	childrenGauge := GetGaugeVec(ClusterExternalSecretChildrenKey)
+	childrenGauge.DeletePartialMatch(prometheus.Labels{"name": ces.Name})
+	childrenGauge.With(withLabels(prometheus.Labels{"status": "Ready"})).Set(float64(children.Ready))
+	childrenGauge.With(withLabels(prometheus.Labels{"status": "NotReady"})).Set(float64(children.NotReady))
+
+	oldestFailureGauge := GetGaugeVec(ClusterExternalSecretOldestFailureKey)
+	oldestFailureGauge.DeletePartialMatch(prometheus.Labels{"name": ces.Name})
+	if failure := children.OldestFailure; failure != nil {
+		oldestFailureGauge.With(withLabels(prometheus.Labels{
+			"target_namespace": failure.Namespace,
+			"reason":           failure.Reason,
+		})).Set(float64(failure.Since.Unix()))
+	}
+}
+
 // RemoveMetrics deletes all metrics published by the resource.
 func RemoveMetrics(namespace, name string) {
 	for _, gaugeVecMetric := range gaugeVecMetrics {
@@ -120,3 +120,68 @@ func TestUpdateClusterExternalSecretCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateClusterExternalSecretChildren(t *testing.T) {
+	// Evacuate the original non condition metric labels
+	tmpNonConditionMetricLabels := metrics.NonConditionMetricLabels
+	defer func() {
+		metrics.NonConditionMetricLabels = tmpNonConditionMetricLabels
+	}()
+	metrics.NonConditionMetricLabels = map[string]string{"name": "", "namespace": ""}
+
+	// Evacuate the original gauge vecs
+	tmpChildren := GetGaugeVec(ClusterExternalSecretChildrenKey)
+	tmpOldestFailure := GetGaugeVec(ClusterExternalSecretOldestFailureKey)
+	defer func() {
+		gaugeVecMetrics[ClusterExternalSecretChildrenKey] = tmpChildren
+		gaugeVecMetrics[ClusterExternalSecretOldestFailureKey] = tmpOldestFailure
+	}()
+	children := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "cesmetrics",
+		Name:      "TestUpdateClusterExternalSecretChildren",
+	}, []string{"name", "namespace", "status"})
+	oldestFailure := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "cesmetrics",
+		Name:      "TestUpdateClusterExternalSecretOldestFailure",
+	}, []string{"name", "namespace", "target_namespace", "reason"})
+	gaugeVecMetrics[ClusterExternalSecretChildrenKey] = children
+	gaugeVecMetrics[ClusterExternalSecretOldestFailureKey] = oldestFailure
+
+	since := metav1.Unix(1700000000, 0)
+	ces := &esv1.ClusterExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Status: esv1.ClusterExternalSecretStatus{
+			Children: &esv1.ClusterExternalSecretChildrenStatus{
+				Ready:    3,
+				NotReady: 1,
+				OldestFailure: &esv1.ClusterExternalSecretChildFailure{
+					Namespace: "team-a",
+					Reason:    "SecretSyncedError",
+					Since:     since,
+				},
+			},
+		},
+	}
+	UpdateClusterExternalSecretChildren(ces)
+
+	if got := testutil.ToFloat64(children.With(prometheus.Labels{"name": "test", "namespace": "", "status": "Ready"})); got != 3 {
+		t.Fatalf("unexpected number of ready children: got: %v, expected: 3", got)
+	}
+	if got := testutil.ToFloat64(children.With(prometheus.Labels{"name": "test", "namespace": "", "status": "NotReady"})); got != 1 {
+		t.Fatalf("unexpected number of not ready children: got: %v, expected: 1", got)
+	}
+	failureLabels := prometheus.Labels{"name": "test", "namespace": "", "target_namespace": "team-a", "reason": "SecretSyncedError"}
+	if got := testutil.ToFloat64(oldestFailure.With(failureLabels)); got != float64(since.Unix()) {
+		t.Fatalf("unexpected oldest failure: got: %v, expected: %v", got, since.Unix())
+	}
+
+	// the oldest failure is removed once all children are ready.
+	ces.Status.Children = &esv1.ClusterExternalSecretChildrenStatus{Ready: 4}
+	UpdateClusterExternalSecretChildren(ces)
+	if got := testutil.CollectAndCount(oldestFailure); got != 0 {
+		t.Fatalf("unexpected number of oldest failures: got: %d, expected: 0", got)
+	}
+	if got := testutil.CollectAndCount(children); got != 2 {
+		t.Fatalf("unexpected number of children metrics: got: %d, expected: 2", got)
+	}
+}
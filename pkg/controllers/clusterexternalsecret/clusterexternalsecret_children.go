@@ -0,0 +1,59 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// getChildrenStatus aggregates the Ready conditions of the ExternalSecrets in the namespaces.
+// The ExternalSecrets are owned by the ClusterExternalSecret, so their status changes trigger a reconcile.
+func (r *Reconciler) getChildrenStatus(ctx context.Context, esName string, namespaces []string) *esv1.ClusterExternalSecretChildrenStatus {
+	children := &esv1.ClusterExternalSecretChildrenStatus{}
+	for _, ns := range namespaces {
+		var es esv1.ExternalSecret
+		if err := r.Get(ctx, types.NamespacedName{Name: esName, Namespace: ns}, &es); err != nil {
+			// the ExternalSecret was just created and is not synced yet.
+			children.NotReady++
+			continue
+		}
+
+		cond := esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretReady)
+		if cond != nil && cond.Status == v1.ConditionTrue {
+			children.Ready++
+			continue
+		}
+		children.NotReady++
+		if cond == nil || cond.Status != v1.ConditionFalse {
+			continue
+		}
+		if oldest := children.OldestFailure; oldest == nil || cond.LastTransitionTime.Before(&oldest.Since) {
+			children.OldestFailure = &esv1.ClusterExternalSecretChildFailure{
+				Namespace: ns,
+				Reason:    cond.Reason,
+				Message:   cond.Message,
+				Since:     cond.LastTransitionTime,
+			}
+		}
+	}
+	return children
+}
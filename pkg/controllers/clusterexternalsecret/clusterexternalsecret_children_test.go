@@ -0,0 +1,78 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestGetChildrenStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+
+	now := time.Now().Truncate(time.Second)
+	externalSecret := func(ns string, status v1.ConditionStatus, reason string, since time.Time) *esv1.ExternalSecret {
+		es := &esv1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: ns}}
+		if status != "" {
+			es.Status.Conditions = []esv1.ExternalSecretStatusCondition{{
+				Type:               esv1.ExternalSecretReady,
+				Status:             status,
+				Reason:             reason,
+				Message:            "could not get secret data from provider",
+				LastTransitionTime: metav1.NewTime(since),
+			}}
+		}
+		return es
+	}
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		externalSecret("ready", v1.ConditionTrue, esv1.ConditionReasonSecretSynced, now.Add(-time.Hour)),
+		externalSecret("failing", v1.ConditionFalse, esv1.ConditionReasonSecretSyncedError, now.Add(-time.Minute)),
+		externalSecret("failing-longest", v1.ConditionFalse, esv1.ConditionReasonSecretSyncedError, now.Add(-time.Hour)),
+		externalSecret("pending", "", "", now),
+	).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard(), Scheme: scheme}
+
+	// ExternalSecrets without a Ready condition or which do not exist yet are not ready, but not failing.
+	children := r.getChildrenStatus(context.Background(), "es", []string{"ready", "failing", "failing-longest", "pending", "missing"})
+	assert.Equal(t, &esv1.ClusterExternalSecretChildrenStatus{
+		Ready:    1,
+		NotReady: 4,
+		OldestFailure: &esv1.ClusterExternalSecretChildFailure{
+			Namespace: "failing-longest",
+			Reason:    esv1.ConditionReasonSecretSyncedError,
+			Message:   "could not get secret data from provider",
+			Since:     metav1.NewTime(now.Add(-time.Hour)),
+		},
+	}, children)
+
+	children = r.getChildrenStatus(context.Background(), "es", []string{"ready"})
+	assert.Equal(t, &esv1.ClusterExternalSecretChildrenStatus{Ready: 1}, children)
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"time"
@@ -181,6 +182,8 @@ func (r *Reconciler) reconcile(ctx context.Context, log logr.Logger, clusterExte
 	clusterExternalSecret.Status.FailedNamespaces = toNamespaceFailures(failedNamespaces)
 	sort.Strings(provisionedNamespaces)
 	clusterExternalSecret.Status.ProvisionedNamespaces = provisionedNamespaces
+	clusterExternalSecret.Status.Children = r.getChildrenStatus(ctx, esName, provisionedNamespaces)
+	cesmetrics.UpdateClusterExternalSecretChildren(clusterExternalSecret)
 
 	// Check if any failures are due to conflicts - if so, requeue immediately
 	for _, err := range failedNamespaces {
@@ -228,7 +231,14 @@ func (r *Reconciler) gatherProvisionedNamespaces(
 			continue
 		}
 
-		if err := r.createOrUpdateExternalSecret(ctx, clusterExternalSecret, namespace, esName, clusterExternalSecret.Spec.ExternalSecretMetadata); err != nil {
+		esSpec, esMetadata, err := renderNamespacedSpec(clusterExternalSecret, &namespace)
+		if err != nil {
+			log.Error(err, "failed to render external secret", "namespace", namespace.Name)
+			failedNamespaces[namespace.Name] = err
+			continue
+		}
+
+		if err := r.createOrUpdateExternalSecret(ctx, clusterExternalSecret, namespace, esName, esSpec, esMetadata); err != nil {
 			// If conflict, don't log as error - just add to failed namespaces for retry
 			if apierrors.IsConflict(err) {
 				log.V(1).Info("conflict while updating namespace, will retry", "namespace", namespace.Name)
@@ -376,6 +386,7 @@ func (r *Reconciler) createOrUpdateExternalSecret(
 	clusterExternalSecret *esv1.ClusterExternalSecret,
 	namespace v1.Namespace,
 	esName string,
+	esSpec esv1.ExternalSecretSpec,
 	esMetadata esv1.ExternalSecretMetadata,
 ) error {
 	// Add namespace finalizer first to prevent deletion race conditions
@@ -392,8 +403,17 @@ func (r *Reconciler) createOrUpdateExternalSecret(
 	}
 
 	mutateFunc := func() error {
+		// keep the refresh requested by the refresh receiver, removing it would trigger another refresh.
+		refreshRequested, hasRefreshRequested := externalSecret.Annotations[esv1.AnnotationRefreshRequested]
 		externalSecret.Labels = esMetadata.Labels
 		externalSecret.Annotations = esMetadata.Annotations
+		if hasRefreshRequested {
+			externalSecret.Annotations = maps.Clone(externalSecret.Annotations)
+			if externalSecret.Annotations == nil {
+				externalSecret.Annotations = map[string]string{}
+			}
+			externalSecret.Annotations[esv1.AnnotationRefreshRequested] = refreshRequested
+		}
 		if value, ok := clusterExternalSecret.Annotations[esv1.AnnotationForceSync]; ok {
 			if externalSecret.Annotations == nil {
 				externalSecret.Annotations = map[string]string{}
@@ -403,7 +423,7 @@ func (r *Reconciler) createOrUpdateExternalSecret(
 			delete(externalSecret.Annotations, esv1.AnnotationForceSync)
 		}
 
-		externalSecret.Spec = clusterExternalSecret.Spec.ExternalSecretSpec
+		externalSecret.Spec = esSpec
 
 		if err := controllerutil.SetControllerReference(clusterExternalSecret, externalSecret, r.Scheme); err != nil {
 			return fmt.Errorf("could not set the controller owner reference %w", err)
@@ -488,7 +508,7 @@ func (r *Reconciler) deferPatch(ctx context.Context, log logr.Logger, clusterExt
 func (r *Reconciler) deleteOutdatedExternalSecrets(ctx context.Context, namespaces []v1.Namespace, esName, cesName string, provisionedNamespaces []string) map[string]error {
 	failedNamespaces := map[string]error{}
 	// Loop through existing namespaces first to make sure they still have our labels
-	for _, namespace := range getRemovedNamespaces(namespaces, provisionedNamespaces) {
+	for _, namespace := range esutils.RemovedNamespaces(namespaces, provisionedNamespaces) {
 		err := r.deleteExternalSecret(ctx, esName, cesName, namespace)
 		if err != nil {
 			r.Log.Error(err, "unable to delete external secret")
@@ -504,35 +524,10 @@ func isExternalSecretOwnedBy(es *esv1.ExternalSecret, cesName string) bool {
 	return owner != nil && schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).GroupKind().String() == esv1.ClusterExtSecretGroupKind && owner.Name == cesName
 }
 
-func getRemovedNamespaces(currentNSs []v1.Namespace, provisionedNSs []string) []string {
-	currentNSSet := map[string]struct{}{}
-	for _, currentNs := range currentNSs {
-		currentNSSet[currentNs.Name] = struct{}{}
-	}
-
-	var removedNSs []string
-	for _, ns := range provisionedNSs {
-		if _, ok := currentNSSet[ns]; !ok {
-			removedNSs = append(removedNSs, ns)
-		}
-	}
-
-	return removedNSs
-}
-
 func toNamespaceFailures(failedNamespaces map[string]error) []esv1.ClusterExternalSecretNamespaceFailure {
-	namespaceFailures := make([]esv1.ClusterExternalSecretNamespaceFailure, len(failedNamespaces))
-
-	i := 0
-	for namespace, err := range failedNamespaces {
-		namespaceFailures[i] = esv1.ClusterExternalSecretNamespaceFailure{
-			Namespace: namespace,
-			Reason:    err.Error(),
-		}
-		i++
-	}
-	sort.Slice(namespaceFailures, func(i, j int) bool { return namespaceFailures[i].Namespace < namespaceFailures[j].Namespace })
-	return namespaceFailures
+	return esutils.NamespaceFailures(failedNamespaces, func(namespace, reason string) esv1.ClusterExternalSecretNamespaceFailure {
+		return esv1.ClusterExternalSecretNamespaceFailure{Namespace: namespace, Reason: reason}
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -185,6 +185,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -231,6 +232,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    "test-es",
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -290,6 +292,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    "new-es-name",
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -357,6 +360,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -417,6 +421,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 								Reason:    "external secret already exists in namespace",
 							},
 						},
+						Children: &esv1.ClusterExternalSecretChildrenStatus{},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:    esv1.ClusterExternalSecretReady,
@@ -489,6 +494,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -561,6 +567,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: []string{namespaces[1].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -628,6 +635,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: provisionedNamespaces,
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 2},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -679,6 +687,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Spec: created.Spec,
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName: created.Name,
+						Children:           &esv1.ClusterExternalSecretChildrenStatus{},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -743,6 +752,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 							"namespace1",
 							"namespace2",
 						},
+						Children: &esv1.ClusterExternalSecretChildrenStatus{NotReady: 2},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -797,6 +807,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 						ProvisionedNamespaces: []string{
 							"not-matching-namespace",
 						},
+						Children: &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -840,6 +851,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -899,6 +911,7 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 					Status: esv1.ClusterExternalSecretStatus{
 						ExternalSecretName:    created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &esv1.ClusterExternalSecretChildrenStatus{NotReady: 1},
 						Conditions: []esv1.ClusterExternalSecretStatusCondition{
 							{
 								Type:   esv1.ClusterExternalSecretReady,
@@ -0,0 +1,141 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	estemplate "github.com/external-secrets/external-secrets/runtime/template/v2"
+)
+
+const (
+	errRenderSpec = "could not render ExternalSecret for namespace %s: %w"
+	errRenderPath = "unable to render %s: %w"
+
+	defaultLeftDelimiter  = "[["
+	defaultRightDelimiter = "]]"
+)
+
+// namespacedSpec is the part of a ClusterExternalSecret that is rendered for each namespace.
+type namespacedSpec struct {
+	Spec     esv1.ExternalSecretSpec     `json:"spec"`
+	Metadata esv1.ExternalSecretMetadata `json:"metadata"`
+}
+
+// renderNamespacedSpec returns the spec and metadata of the ExternalSecret in the namespace.
+// With namespace templating, their string values are rendered with the namespace as context.
+func renderNamespacedSpec(ces *esv1.ClusterExternalSecret, namespace *v1.Namespace) (esv1.ExternalSecretSpec, esv1.ExternalSecretMetadata, error) {
+	tpl := ces.Spec.NamespaceTemplating
+	if tpl == nil {
+		return ces.Spec.ExternalSecretSpec, ces.Spec.ExternalSecretMetadata, nil
+	}
+	left, right := tpl.LeftDelimiter, tpl.RightDelimiter
+	if left == "" {
+		left = defaultLeftDelimiter
+	}
+	if right == "" {
+		right = defaultRightDelimiter
+	}
+
+	spec := ces.Spec.ExternalSecretSpec.DeepCopy()
+	if tpl.TargetName != "" {
+		spec.Target.Name = tpl.TargetName
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&namespacedSpec{
+		Spec:     *spec,
+		Metadata: ces.Spec.ExternalSecretMetadata,
+	})
+	if err != nil {
+		return esv1.ExternalSecretSpec{}, esv1.ExternalSecretMetadata{}, fmt.Errorf(errRenderSpec, namespace.Name, err)
+	}
+
+	r := &specRenderer{
+		left:  left,
+		right: right,
+		data: map[string]any{
+			"namespace": map[string]any{
+				"name":        namespace.Name,
+				"labels":      namespace.Labels,
+				"annotations": namespace.Annotations,
+			},
+		},
+	}
+	rendered, err := r.render("", obj)
+	if err != nil {
+		return esv1.ExternalSecretSpec{}, esv1.ExternalSecretMetadata{}, fmt.Errorf(errRenderSpec, namespace.Name, err)
+	}
+
+	var out namespacedSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rendered.(map[string]any), &out); err != nil {
+		return esv1.ExternalSecretSpec{}, esv1.ExternalSecretMetadata{}, fmt.Errorf(errRenderSpec, namespace.Name, err)
+	}
+	return out.Spec, out.Metadata, nil
+}
+
+// specRenderer renders all string values of an unstructured object as templates.
+type specRenderer struct {
+	left, right string
+	data        map[string]any
+}
+
+func (r *specRenderer) render(path string, value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			rendered, err := r.render(path+"."+k, item)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = rendered
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			rendered, err := r.render(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	case string:
+		if !strings.Contains(v, r.left) {
+			return v, nil
+		}
+		tmpl, err := template.New(path).
+			Funcs(estemplate.FuncMap()).
+			Delims(r.left, r.right).
+			Option("missingkey=error").
+			Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf(errRenderPath, path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, r.data); err != nil {
+			return nil, fmt.Errorf(errRenderPath, path, err)
+		}
+		return buf.String(), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestRenderNamespacedSpec(t *testing.T) {
+	namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "team-a",
+		Labels: map[string]string{"env": "prod"},
+	}}
+	ces := &esv1.ClusterExternalSecret{
+		Spec: esv1.ClusterExternalSecretSpec{
+			ExternalSecretSpec: esv1.ExternalSecretSpec{
+				RefreshInterval: &metav1.Duration{Duration: time.Hour},
+				Target: esv1.ExternalSecretTarget{
+					Name: "db",
+					Template: &esv1.ExternalSecretTemplate{
+						Data: map[string]string{"dsn": "postgres://{{ .user }}@db"},
+					},
+				},
+				Data: []esv1.ExternalSecretData{{
+					SecretKey: "user",
+					RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "secret/[[ .namespace.name ]]/db", Property: "user"},
+				}},
+			},
+			ExternalSecretMetadata: esv1.ExternalSecretMetadata{
+				Labels: map[string]string{"env": `[[ index .namespace.labels "env" ]]`},
+			},
+		},
+	}
+
+	// without namespace templating, the spec is copied as is.
+	spec, metadata, err := renderNamespacedSpec(ces, namespace)
+	require.NoError(t, err)
+	assert.Equal(t, ces.Spec.ExternalSecretSpec, spec)
+	assert.Equal(t, ces.Spec.ExternalSecretMetadata, metadata)
+
+	// string values are rendered with the namespace, the template of the target is kept.
+	ces.Spec.NamespaceTemplating = &esv1.ClusterExternalSecretNamespaceTemplating{TargetName: "[[ .namespace.name ]]-db"}
+	spec, metadata, err = renderNamespacedSpec(ces, namespace)
+	require.NoError(t, err)
+	assert.Equal(t, "secret/team-a/db", spec.Data[0].RemoteRef.Key)
+	assert.Equal(t, "team-a-db", spec.Target.Name)
+	assert.Equal(t, "postgres://{{ .user }}@db", spec.Target.Template.Data["dsn"])
+	assert.Equal(t, time.Hour, spec.RefreshInterval.Duration)
+	assert.Equal(t, map[string]string{"env": "prod"}, metadata.Labels)
+	// the ClusterExternalSecret is not changed.
+	assert.Equal(t, "secret/[[ .namespace.name ]]/db", ces.Spec.ExternalSecretSpec.Data[0].RemoteRef.Key)
+	assert.Equal(t, "db", ces.Spec.ExternalSecretSpec.Target.Name)
+
+	// rendering errors name the field.
+	ces.Spec.ExternalSecretSpec.Data[0].RemoteRef.Property = "[[ .namespace.uid ]]"
+	_, _, err = renderNamespacedSpec(ces, namespace)
+	assert.ErrorContains(t, err, "could not render ExternalSecret for namespace team-a: unable to render .spec.data[0].remoteRef.property")
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpushsecret
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret"
+)
+
+// getChildrenStatus aggregates the Ready conditions of the PushSecrets in the namespaces.
+// The PushSecrets are owned by the ClusterPushSecret, so their status changes trigger a reconcile.
+func (r *Reconciler) getChildrenStatus(ctx context.Context, psName string, namespaces []string) *v1alpha1.ClusterPushSecretChildrenStatus {
+	children := &v1alpha1.ClusterPushSecretChildrenStatus{}
+	for _, ns := range namespaces {
+		var ps v1alpha1.PushSecret
+		if err := r.Get(ctx, types.NamespacedName{Name: psName, Namespace: ns}, &ps); err != nil {
+			// the PushSecret was just created and is not synced yet.
+			children.NotReady++
+			continue
+		}
+
+		cond := pushsecret.GetPushSecretCondition(ps.Status.Conditions, v1alpha1.PushSecretReady)
+		if cond != nil && cond.Status == v1.ConditionTrue {
+			children.Ready++
+			continue
+		}
+		children.NotReady++
+		if cond == nil || cond.Status != v1.ConditionFalse {
+			continue
+		}
+		if oldest := children.OldestFailure; oldest == nil || cond.LastTransitionTime.Before(&oldest.Since) {
+			children.OldestFailure = &v1alpha1.ClusterPushSecretChildFailure{
+				Namespace: ns,
+				Reason:    cond.Reason,
+				Message:   cond.Message,
+				Since:     cond.LastTransitionTime,
+			}
+		}
+	}
+	return children
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpushsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestGetChildrenStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	since := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	pushSecret := func(ns string, status v1.ConditionStatus) *v1alpha1.PushSecret {
+		return &v1alpha1.PushSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: ns},
+			Status: v1alpha1.PushSecretStatus{Conditions: []v1alpha1.PushSecretStatusCondition{{
+				Type:               v1alpha1.PushSecretReady,
+				Status:             status,
+				Reason:             v1alpha1.ReasonErrored,
+				Message:            "set secret failed",
+				LastTransitionTime: since,
+			}}},
+		}
+	}
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pushSecret("ready", v1.ConditionTrue),
+		pushSecret("failing", v1.ConditionFalse),
+	).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard(), Scheme: scheme}
+
+	children := r.getChildrenStatus(context.Background(), "ps", []string{"ready", "failing", "missing"})
+	assert.Equal(t, &v1alpha1.ClusterPushSecretChildrenStatus{
+		Ready:    1,
+		NotReady: 2,
+		OldestFailure: &v1alpha1.ClusterPushSecretChildFailure{
+			Namespace: "failing",
+			Reason:    v1alpha1.ReasonErrored,
+			Message:   "set secret failed",
+			Since:     since,
+		},
+	}, children)
+}
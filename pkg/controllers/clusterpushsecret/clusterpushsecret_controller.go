@@ -115,16 +115,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	failedNamespaces := r.deleteOutdatedPushSecrets(ctx, namespaces, esName, cps.Name, cps.Status.ProvisionedNamespaces)
-	provisionedNamespaces := r.updateProvisionedNamespaces(ctx, namespaces, esName, log, failedNamespaces, &cps)
+	var provisionedNamespaces []string
+	requeueAfter := refreshInt
+	if cps.Spec.RolloutStrategy != nil {
+		provisionedNamespaces, requeueAfter = r.rollout(ctx, &cps, namespaces, esName, failedNamespaces, refreshInt)
+	} else {
+		cps.Status.Rollout = nil
+		provisionedNamespaces = r.updateProvisionedNamespaces(ctx, namespaces, esName, log, failedNamespaces, &cps)
+	}
 
 	condition := NewClusterPushSecretCondition(failedNamespaces)
+	if rollout := cps.Status.Rollout; rollout != nil && rollout.Phase == v1alpha1.ClusterPushSecretRolloutHalted {
+		condition = pushsecret.NewPushSecretCondition(v1alpha1.PushSecretReady, v1.ConditionFalse, v1alpha1.ReasonRolloutHalted, rollout.Message)
+	}
 	SetClusterPushSecretCondition(&cps, *condition)
 
 	cps.Status.FailedNamespaces = toNamespaceFailures(failedNamespaces)
 	sort.Strings(provisionedNamespaces)
 	cps.Status.ProvisionedNamespaces = provisionedNamespaces
+	cps.Status.Children = r.getChildrenStatus(ctx, esName, provisionedNamespaces)
+	cpsmetrics.UpdateClusterPushSecretChildren(&cps)
 
-	return ctrl.Result{RequeueAfter: refreshInt}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *Reconciler) updateProvisionedNamespaces(
@@ -271,7 +283,7 @@ func (r *Reconciler) deferPatch(ctx context.Context, log logr.Logger, cps *v1alp
 func (r *Reconciler) deleteOutdatedPushSecrets(ctx context.Context, namespaces []v1.Namespace, esName, cesName string, provisionedNamespaces []string) map[string]error {
 	failedNamespaces := map[string]error{}
 	// Loop through existing namespaces first to make sure they still have our labels
-	for _, namespace := range getRemovedNamespaces(namespaces, provisionedNamespaces) {
+	for _, namespace := range esutils.RemovedNamespaces(namespaces, provisionedNamespaces) {
 		err := r.deletePushSecret(ctx, esName, cesName, namespace)
 		if err != nil {
 			r.Log.Error(err, "unable to delete external secret")
@@ -287,35 +299,10 @@ func isPushSecretOwnedBy(ps *v1alpha1.PushSecret, cesName string) bool {
 	return owner != nil && owner.APIVersion == v1alpha1.SchemeGroupVersion.String() && owner.Kind == "ClusterPushSecret" && owner.Name == cesName
 }
 
-func getRemovedNamespaces(currentNSs []v1.Namespace, provisionedNSs []string) []string {
-	currentNSSet := map[string]struct{}{}
-	for _, currentNs := range currentNSs {
-		currentNSSet[currentNs.Name] = struct{}{}
-	}
-
-	var removedNSs []string
-	for _, ns := range provisionedNSs {
-		if _, ok := currentNSSet[ns]; !ok {
-			removedNSs = append(removedNSs, ns)
-		}
-	}
-
-	return removedNSs
-}
-
 func toNamespaceFailures(failedNamespaces map[string]error) []v1alpha1.ClusterPushSecretNamespaceFailure {
-	namespaceFailures := make([]v1alpha1.ClusterPushSecretNamespaceFailure, len(failedNamespaces))
-
-	i := 0
-	for namespace, err := range failedNamespaces {
-		namespaceFailures[i] = v1alpha1.ClusterPushSecretNamespaceFailure{
-			Namespace: namespace,
-			Reason:    err.Error(),
-		}
-		i++
-	}
-	sort.Slice(namespaceFailures, func(i, j int) bool { return namespaceFailures[i].Namespace < namespaceFailures[j].Namespace })
-	return namespaceFailures
+	return esutils.NamespaceFailures(failedNamespaces, func(namespace, reason string) v1alpha1.ClusterPushSecretNamespaceFailure {
+		return v1alpha1.ClusterPushSecretNamespaceFailure{Namespace: namespace, Reason: reason}
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -224,6 +224,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 1},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -273,6 +274,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        testPushSecret,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 1},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -335,6 +337,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        newPushSecret,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 1},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -415,6 +418,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 1},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -496,6 +500,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 								Reason:    "push secret already exists in namespace",
 							},
 						},
+						Children: &v1alpha1.ClusterPushSecretChildrenStatus{},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:    v1alpha1.PushSecretReady,
@@ -593,6 +598,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        created.Name,
 						ProvisionedNamespaces: []string{namespaces[0].Name},
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 1},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -661,6 +667,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        created.Name,
 						ProvisionedNamespaces: []string{namespaces[1].Name},
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 1},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -731,6 +738,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName:        created.Name,
 						ProvisionedNamespaces: provisionedNamespaces,
+						Children:              &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 2},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -785,6 +793,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 					Spec: created.Spec,
 					Status: v1alpha1.ClusterPushSecretStatus{
 						PushSecretName: created.Name,
+						Children:       &v1alpha1.ClusterPushSecretChildrenStatus{},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -850,6 +859,7 @@ var _ = Describe("ClusterPushSecret controller", func() {
 							"namespace1",
 							"namespace2",
 						},
+						Children: &v1alpha1.ClusterPushSecretChildrenStatus{NotReady: 2},
 						Conditions: []v1alpha1.PushSecretStatusCondition{
 							{
 								Type:   v1alpha1.PushSecretReady,
@@ -0,0 +1,291 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	msgRolloutUpdateFailed = "failed to update PushSecret in namespace %s: %v"
+	msgRolloutBatchFailed  = "PushSecret in namespace %s failed: %s"
+	msgRolloutTimeout      = "PushSecret in namespace %s did not become ready within %s"
+	msgRolloutResumed      = "rollout of revision %s resumed"
+
+	defaultHealthGateTimeout = 10 * time.Minute
+)
+
+// rolloutRevision returns the revision of the PushSecret spec and metadata of a ClusterPushSecret.
+func rolloutRevision(cps *v1alpha1.ClusterPushSecret) string {
+	return esutils.ObjectHash(struct {
+		Spec     v1alpha1.PushSecretSpec
+		Metadata v1alpha1.PushSecretMetadata
+	}{cps.Spec.PushSecretSpec, cps.Spec.PushSecretMetadata})
+}
+
+// rolloutState is the state of the PushSecrets in the target namespaces.
+type rolloutState struct {
+	provisioned []string
+	// updated are the PushSecrets which were updated to the revision, keyed by namespace.
+	updated map[string]*v1alpha1.PushSecret
+	// pending are the namespaces whose PushSecret does not exist or was not updated to the revision.
+	pending []string
+}
+
+// rollout creates and updates the PushSecrets in the namespaces in batches, according to the rollout strategy.
+// It returns the provisioned namespaces and when the rollout should be continued.
+func (r *Reconciler) rollout(
+	ctx context.Context,
+	cps *v1alpha1.ClusterPushSecret,
+	namespaces []v1.Namespace,
+	psName string,
+	failedNamespaces map[string]error,
+	refreshInt time.Duration,
+) ([]string, time.Duration) {
+	revision := rolloutRevision(cps)
+	if cps.Status.Rollout == nil || cps.Status.Rollout.Revision != revision {
+		// a resume requested before the rollout was started does not resume it once it is halted.
+		cps.Status.Rollout = &v1alpha1.ClusterPushSecretRolloutStatus{
+			Revision:   revision,
+			Phase:      v1alpha1.ClusterPushSecretRolloutProgressing,
+			LastResume: cps.Annotations[v1alpha1.AnnotationRolloutResume],
+		}
+	}
+	status := cps.Status.Rollout
+	state := r.getRolloutState(ctx, cps, namespaces, psName, revision, failedNamespaces)
+	requeueAfter := refreshInt
+	defer func() {
+		status.UpdatedNamespaces = int32(len(state.updated))
+		status.PendingNamespaces = int32(len(state.pending))
+	}()
+
+	if status.Phase == v1alpha1.ClusterPushSecretRolloutHalted {
+		resume := cps.Annotations[v1alpha1.AnnotationRolloutResume]
+		if resume == status.LastResume {
+			return state.provisioned, requeueAfter
+		}
+		r.resumeRollout(cps, resume)
+	}
+
+	strategy := cps.Spec.RolloutStrategy
+	for {
+		if len(status.CurrentBatch) > 0 {
+			wait, err := checkBatch(cps, state)
+			if err != nil {
+				r.haltRollout(cps, err.Error())
+				return state.provisioned, requeueAfter
+			}
+			if wait > 0 {
+				return state.provisioned, min(wait, requeueAfter)
+			}
+			status.CurrentBatch = nil
+			status.LastBatchCompletionTime = new(metav1.Now())
+		}
+
+		if len(state.pending) == 0 {
+			status.Phase = v1alpha1.ClusterPushSecretRolloutComplete
+			status.BatchStartTime = nil
+			return state.provisioned, requeueAfter
+		}
+
+		if strategy.Pause != nil && status.LastBatchCompletionTime != nil {
+			if wait := time.Until(status.LastBatchCompletionTime.Add(strategy.Pause.Duration)); wait > 0 {
+				return state.provisioned, min(wait, requeueAfter)
+			}
+		}
+
+		if err := r.startBatch(ctx, cps, psName, revision, state, failedNamespaces); err != nil {
+			r.haltRollout(cps, err.Error())
+			return state.provisioned, requeueAfter
+		}
+	}
+}
+
+// getRolloutState reads the PushSecrets in the namespaces and finds out which were updated to the revision.
+func (r *Reconciler) getRolloutState(
+	ctx context.Context,
+	cps *v1alpha1.ClusterPushSecret,
+	namespaces []v1.Namespace,
+	psName, revision string,
+	failedNamespaces map[string]error,
+) *rolloutState {
+	state := &rolloutState{updated: map[string]*v1alpha1.PushSecret{}}
+	for _, namespace := range namespaces {
+		var ps v1alpha1.PushSecret
+		err := r.Get(ctx, types.NamespacedName{Name: psName, Namespace: namespace.Name}, &ps)
+		if err != nil && !apierrors.IsNotFound(err) {
+			r.Log.Error(err, errGetExistingPS)
+			failedNamespaces[namespace.Name] = err
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			state.pending = append(state.pending, namespace.Name)
+			continue
+		}
+		if !isPushSecretOwnedBy(&ps, cps.Name) {
+			failedNamespaces[namespace.Name] = errors.New("push secret already exists in namespace")
+			continue
+		}
+
+		state.provisioned = append(state.provisioned, namespace.Name)
+		if ps.Annotations[v1alpha1.AnnotationRolloutRevision] == revision {
+			state.updated[namespace.Name] = &ps
+		} else {
+			state.pending = append(state.pending, namespace.Name)
+		}
+	}
+	slices.Sort(state.pending)
+	return state
+}
+
+// startBatch updates the PushSecrets of the next batch of pending namespaces to the revision.
+// Without a health gate, the batch is completed right away.
+func (r *Reconciler) startBatch(
+	ctx context.Context,
+	cps *v1alpha1.ClusterPushSecret,
+	psName, revision string,
+	state *rolloutState,
+	failedNamespaces map[string]error,
+) error {
+	status := cps.Status.Rollout
+	batchSize := max(int(cps.Spec.RolloutStrategy.BatchSize), 1)
+	batch := state.pending[:min(batchSize, len(state.pending))]
+	state.pending = state.pending[len(batch):]
+
+	metadata := *cps.Spec.PushSecretMetadata.DeepCopy()
+	metadata.Annotations = maps.Clone(metadata.Annotations)
+	if metadata.Annotations == nil {
+		metadata.Annotations = map[string]string{}
+	}
+	metadata.Annotations[v1alpha1.AnnotationRolloutRevision] = revision
+
+	status.CurrentBatch = nil
+	status.BatchStartTime = new(metav1.NewTime(time.Now().Truncate(time.Second)))
+	for _, ns := range batch {
+		status.CurrentBatch = append(status.CurrentBatch, ns)
+		namespace := v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}
+		if err := r.createOrUpdatePushSecret(ctx, cps, namespace, psName, metadata); err != nil {
+			failedNamespaces[ns] = err
+			return fmt.Errorf(msgRolloutUpdateFailed, ns, err)
+		}
+		if !slices.Contains(state.provisioned, ns) {
+			state.provisioned = append(state.provisioned, ns)
+		}
+		// the status of the updated PushSecret is only known on the next reconcile.
+		state.updated[ns] = nil
+	}
+
+	if cps.Spec.RolloutStrategy.HealthGate == nil {
+		status.CurrentBatch = nil
+		status.LastBatchCompletionTime = new(metav1.Now())
+	}
+	return nil
+}
+
+// checkBatch returns how long to wait for the PushSecrets of the current batch to become ready,
+// or an error if one of them failed or did not become ready in time.
+func checkBatch(cps *v1alpha1.ClusterPushSecret, state *rolloutState) (time.Duration, error) {
+	status := cps.Status.Rollout
+	gate := cps.Spec.RolloutStrategy.HealthGate
+	if gate == nil {
+		return 0, nil
+	}
+	timeout := defaultHealthGateTimeout
+	if gate.Timeout != nil {
+		timeout = gate.Timeout.Duration
+	}
+	batchStart := time.Now()
+	if status.BatchStartTime != nil {
+		batchStart = status.BatchStartTime.Time
+	}
+
+	for _, ns := range status.CurrentBatch {
+		ps, ok := state.updated[ns]
+		if !ok {
+			// the namespace is no longer targeted, or the PushSecret was changed since.
+			continue
+		}
+		ready, err := pushSecretHealth(ps, batchStart)
+		if err != nil {
+			return 0, fmt.Errorf(msgRolloutBatchFailed, ns, err)
+		}
+		if ready {
+			continue
+		}
+		wait := time.Until(batchStart.Add(timeout))
+		if wait <= 0 {
+			return 0, fmt.Errorf(msgRolloutTimeout, ns, timeout)
+		}
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// pushSecretHealth returns true if the PushSecret was synced since it was updated,
+// or an error if it failed since the batch was started.
+func pushSecretHealth(ps *v1alpha1.PushSecret, batchStart time.Time) (bool, error) {
+	if ps == nil {
+		return false, nil
+	}
+	cond := pushsecret.GetPushSecretCondition(ps.Status.Conditions, v1alpha1.PushSecretReady)
+	if cond == nil {
+		return false, nil
+	}
+	synced := ps.Status.SyncedResourceVersion == ctrlutil.GetResourceVersion(ps.ObjectMeta)
+	if cond.Status == v1.ConditionTrue && synced {
+		return true, nil
+	}
+	if cond.Status == v1.ConditionFalse && !cond.LastTransitionTime.Time.Before(batchStart) {
+		return false, errors.New(cond.Message)
+	}
+	return false, nil
+}
+
+// resumeRollout continues a halted rollout with the current batch, which gets a new health gate timeout.
+// Failures of the PushSecrets of the batch before the rollout was resumed are ignored.
+func (r *Reconciler) resumeRollout(cps *v1alpha1.ClusterPushSecret, resume string) {
+	status := cps.Status.Rollout
+	status.Phase = v1alpha1.ClusterPushSecretRolloutProgressing
+	status.Message = ""
+	status.LastResume = resume
+	if len(status.CurrentBatch) > 0 {
+		status.BatchStartTime = new(metav1.NewTime(time.Now().Truncate(time.Second)))
+	}
+	r.Recorder.Eventf(cps, v1.EventTypeNormal, v1alpha1.ReasonRolloutResumed, msgRolloutResumed, status.Revision)
+}
+
+// haltRollout stops the rollout until the PushSecret spec or metadata change, or it is resumed.
+func (r *Reconciler) haltRollout(cps *v1alpha1.ClusterPushSecret, msg string) {
+	cps.Status.Rollout.Phase = v1alpha1.ClusterPushSecretRolloutHalted
+	cps.Status.Rollout.Message = msg
+	r.Recorder.Event(cps, v1.EventTypeWarning, v1alpha1.ReasonRolloutHalted, msg)
+}
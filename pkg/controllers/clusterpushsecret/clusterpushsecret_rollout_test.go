@@ -0,0 +1,146 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterpushsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+func TestRollout(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.PushSecret{}).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard(), Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+	namespaces := []v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-c"}},
+	}
+	cps := &v1alpha1.ClusterPushSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cps", UID: "cps"},
+		Spec: v1alpha1.ClusterPushSecretSpec{
+			PushSecretSpec: v1alpha1.PushSecretSpec{UpdatePolicy: v1alpha1.PushSecretUpdatePolicyReplace},
+			RolloutStrategy: &v1alpha1.ClusterPushSecretRolloutStrategy{
+				BatchSize:  2,
+				HealthGate: &v1alpha1.ClusterPushSecretHealthGate{Timeout: &metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+	rollout := func() ([]string, time.Duration, map[string]error) {
+		t.Helper()
+		failed := map[string]error{}
+		provisioned, requeueAfter := r.rollout(ctx, cps, namespaces, "ps", failed, time.Hour)
+		return provisioned, requeueAfter, failed
+	}
+	pushSecret := func(ns string) *v1alpha1.PushSecret {
+		t.Helper()
+		var ps v1alpha1.PushSecret
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "ps", Namespace: ns}, &ps))
+		return &ps
+	}
+	setReady := func(ns string, status v1.ConditionStatus) {
+		t.Helper()
+		ps := pushSecret(ns)
+		ps.Status.Conditions = []v1alpha1.PushSecretStatusCondition{{
+			Type: v1alpha1.PushSecretReady, Status: status, Message: "push failed", LastTransitionTime: metav1.Now(),
+		}}
+		ps.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(ps.ObjectMeta)
+		require.NoError(t, fakeClient.Status().Update(ctx, ps))
+	}
+
+	// the first batch is rolled out.
+	provisioned, requeueAfter, _ := rollout()
+	assert.Equal(t, []string{"ns-a", "ns-b"}, provisioned)
+	assert.Equal(t, []string{"ns-a", "ns-b"}, cps.Status.Rollout.CurrentBatch)
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutProgressing, cps.Status.Rollout.Phase)
+	assert.Equal(t, int32(1), cps.Status.Rollout.PendingNamespaces)
+	assert.Equal(t, rolloutRevision(cps), pushSecret("ns-a").Annotations[v1alpha1.AnnotationRolloutRevision])
+	assert.LessOrEqual(t, requeueAfter, time.Minute)
+
+	// the next batch waits for the PushSecrets of the current batch to become ready.
+	provisioned, _, _ = rollout()
+	assert.Equal(t, []string{"ns-a", "ns-b"}, provisioned)
+	setReady("ns-a", v1.ConditionTrue)
+	rollout()
+	assert.Equal(t, []string{"ns-a", "ns-b"}, cps.Status.Rollout.CurrentBatch)
+
+	setReady("ns-b", v1.ConditionTrue)
+	provisioned, _, _ = rollout()
+	assert.Equal(t, []string{"ns-a", "ns-b", "ns-c"}, provisioned)
+	assert.Equal(t, []string{"ns-c"}, cps.Status.Rollout.CurrentBatch)
+	assert.Equal(t, int32(3), cps.Status.Rollout.UpdatedNamespaces)
+	assert.NotNil(t, cps.Status.Rollout.LastBatchCompletionTime)
+
+	// a failing PushSecret halts the rollout.
+	setReady("ns-c", v1.ConditionFalse)
+	rollout()
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutHalted, cps.Status.Rollout.Phase)
+	assert.Contains(t, cps.Status.Rollout.Message, "PushSecret in namespace ns-c failed: push failed")
+	rollout()
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutHalted, cps.Status.Rollout.Phase)
+
+	// a new value of the resume annotation resumes the rollout, failures before the resume are ignored.
+	ps := pushSecret("ns-c")
+	ps.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	require.NoError(t, fakeClient.Status().Update(ctx, ps))
+	cps.Annotations = map[string]string{v1alpha1.AnnotationRolloutResume: "1"}
+	rollout()
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutProgressing, cps.Status.Rollout.Phase)
+	assert.Empty(t, cps.Status.Rollout.Message)
+	assert.Equal(t, "1", cps.Status.Rollout.LastResume)
+	assert.Equal(t, []string{"ns-c"}, cps.Status.Rollout.CurrentBatch)
+
+	setReady("ns-c", v1.ConditionTrue)
+	rollout()
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutComplete, cps.Status.Rollout.Phase)
+
+	// a change of the spec restarts the rollout, without a health gate the batches are rolled out after the pause.
+	cps.Spec.PushSecretSpec.UpdatePolicy = v1alpha1.PushSecretUpdatePolicyIfNotExists
+	cps.Spec.RolloutStrategy = &v1alpha1.ClusterPushSecretRolloutStrategy{BatchSize: 2, Pause: &metav1.Duration{Duration: time.Minute}}
+	_, requeueAfter, _ = rollout()
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutProgressing, cps.Status.Rollout.Phase)
+	assert.Equal(t, "1", cps.Status.Rollout.LastResume)
+	assert.Empty(t, cps.Status.Rollout.CurrentBatch)
+	assert.Equal(t, int32(2), cps.Status.Rollout.UpdatedNamespaces)
+	assert.Equal(t, v1alpha1.PushSecretUpdatePolicyIfNotExists, pushSecret("ns-b").Spec.UpdatePolicy)
+	assert.Equal(t, v1alpha1.PushSecretUpdatePolicyReplace, pushSecret("ns-c").Spec.UpdatePolicy)
+	assert.Greater(t, requeueAfter, 50*time.Second)
+
+	cps.Spec.RolloutStrategy.Pause = nil
+	rollout()
+	assert.Equal(t, v1alpha1.ClusterPushSecretRolloutComplete, cps.Status.Rollout.Phase)
+	assert.Equal(t, v1alpha1.PushSecretUpdatePolicyIfNotExists, pushSecret("ns-c").Spec.UpdatePolicy)
+}
@@ -19,6 +19,7 @@ package cpsmetrics
 
 import (
 	"maps"
+	"slices"
 
 	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
@@ -33,6 +34,8 @@ const (
 	ClusterPushSecretSubsystem            = "clusterpushsecret"
 	ClusterPushSecretReconcileDurationKey = "reconcile_duration"
 	ClusterPushSecretStatusConditionKey   = "status_condition"
+	ClusterPushSecretChildrenKey          = "children"
+	ClusterPushSecretOldestFailureKey     = "oldest_failure_timestamp_seconds"
 )
 
 var gaugeVecMetrics = map[string]*prometheus.GaugeVec{}
@@ -52,11 +55,25 @@ func SetUpMetrics() {
 		Help:      "The status condition of a specific Cluster Push Secret",
 	}, ctrlmetrics.ConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(ClusterPushSecretReconcileDuration, ClusterPushSecretCondition)
+	ClusterPushSecretChildren := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ClusterPushSecretSubsystem,
+		Name:      ClusterPushSecretChildrenKey,
+		Help:      "The number of Push Secrets created by a specific Cluster Push Secret, by their Ready status",
+	}, append(slices.Clone(ctrlmetrics.NonConditionMetricLabelNames), "status"))
+
+	ClusterPushSecretOldestFailure := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ClusterPushSecretSubsystem,
+		Name:      ClusterPushSecretOldestFailureKey,
+		Help:      "The unix time the longest failing Push Secret created by a specific Cluster Push Secret started failing",
+	}, append(slices.Clone(ctrlmetrics.NonConditionMetricLabelNames), "target_namespace", "reason"))
+
+	metrics.Registry.MustRegister(ClusterPushSecretReconcileDuration, ClusterPushSecretCondition, ClusterPushSecretChildren, ClusterPushSecretOldestFailure)
 
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
 		ClusterPushSecretStatusConditionKey:   ClusterPushSecretCondition,
 		ClusterPushSecretReconcileDurationKey: ClusterPushSecretReconcileDuration,
+		ClusterPushSecretChildrenKey:          ClusterPushSecretChildren,
+		ClusterPushSecretOldestFailureKey:     ClusterPushSecretOldestFailure,
 	}
 }
 
@@ -98,6 +115,40 @@ func UpdateClusterPushSecretCondition(ces *v1alpha1.ClusterPushSecret, condition
 		})).Set(0)
 }
 
+// UpdateClusterPushSecretChildren updates the metrics for the PushSecrets created by a ClusterPushSecret,
+// based on the aggregated status of the PushSecrets.
+func UpdateClusterPushSecretChildren(cps *v1alpha1.ClusterPushSecret) {
+	children := cps.Status.Children
+	if children == nil {
+		return
+	}
+
+	cpsInfo := make(map[string]string)
+	cpsInfo["name"] = cps.Name
+	maps.Copy(cpsInfo, cps.Labels)
+	baseLabels := ctrlmetrics.RefineNonConditionMetricLabels(cpsInfo)
+	withLabels := func(labels prometheus.Labels) prometheus.Labels {
+		refined := maps.Clone(baseLabels)
+		maps.Copy(refined, labels)
+		return refined
+	}
+
+	// This handles cases where labels, the failing namespace or the reason have changed
+	childrenGauge := GetGaugeVec(ClusterPushSecretChildrenKey)
+	childrenGauge.DeletePartialMatch(prometheus.Labels{"name": cps.Name})
+	childrenGauge.With(withLabels(prometheus.Labels{"status": "Ready"})).Set(float64(children.Ready))
+	childrenGauge.With(withLabels(prometheus.Labels{"status": "NotReady"})).Set(float64(children.NotReady))
+
+	oldestFailureGauge := GetGaugeVec(ClusterPushSecretOldestFailureKey)
+	oldestFailureGauge.DeletePartialMatch(prometheus.Labels{"name": cps.Name})
+	if failure := children.OldestFailure; failure != nil {
+		oldestFailureGauge.With(withLabels(prometheus.Labels{
+			"target_namespace": failure.Namespace,
+			"reason":           failure.Reason,
+		})).Set(float64(failure.Since.Unix()))
+	}
+}
+
 // RemoveMetrics deletes all metrics published by the resource.
 func RemoveMetrics(namespace, name string) {
 	for _, gaugeVecMetric := range gaugeVecMetrics {
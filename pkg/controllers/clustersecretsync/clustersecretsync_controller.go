@@ -0,0 +1,410 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clustersecretsync implements a controller for managing ClusterSecretSync resources,
+// which replicate a Secret to multiple namespaces.
+package clustersecretsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	indexSourceSecretField = ".spec.source"
+
+	errGetCSS               = "could not get ClusterSecretSync"
+	errPatchStatus          = "unable to patch status"
+	errConvertLabelSelector = "unable to convert labelselector"
+	errGetSource            = "could not get source Secret"
+	errNamespacesFailed     = "one or more namespaces failed"
+	errSecretExists         = "secret already exists in namespace"
+
+	msgSourceNotFound = "source Secret %s/%s does not exist"
+)
+
+// Reconciler reconciles a ClusterSecretSync object.
+// The source Secret is watched, so its changes are replicated right away.
+type Reconciler struct {
+	client.Client
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	RequeueInterval time.Duration
+}
+
+// Reconcile replicates the source Secret of a ClusterSecretSync to the target namespaces
+// and removes it from the namespaces that are no longer targeted.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("ClusterSecretSync", req.NamespacedName)
+
+	var css esapi.ClusterSecretSync
+	if err := r.Get(ctx, req.NamespacedName, &css); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, errGetCSS)
+		return ctrl.Result{}, err
+	}
+
+	// the replicated Secrets are owned by the ClusterSecretSync and garbage collected with it.
+	if css.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	p := client.MergeFrom(css.DeepCopy())
+	defer func() {
+		if err := r.Status().Patch(ctx, &css, p); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, errPatchStatus)
+		}
+	}()
+
+	return r.reconcile(ctx, log, &css)
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log logr.Logger, css *esapi.ClusterSecretSync) (ctrl.Result, error) {
+	refreshInt := r.RequeueInterval
+	if css.Spec.RefreshInterval != nil {
+		refreshInt = css.Spec.RefreshInterval.Duration
+	}
+
+	targetName := getTargetName(css)
+	if prevName := css.Status.TargetName; prevName != "" && prevName != targetName {
+		// the name of the target has changed, so remove the old Secrets
+		failedNamespaces := map[string]error{}
+		for _, ns := range css.Status.ProvisionedNamespaces {
+			if err := r.deleteSecret(ctx, css.Name, prevName, ns); err != nil {
+				log.Error(err, "could not delete Secret")
+				failedNamespaces[ns] = err
+			}
+		}
+		if len(failedNamespaces) > 0 {
+			setFailed(css, failedNamespaces)
+			return ctrl.Result{}, errors.New(errNamespacesFailed)
+		}
+		css.Status.ProvisionedNamespaces = nil
+	}
+	css.Status.TargetName = targetName
+
+	namespaces, err := esutils.GetTargetNamespaces(ctx, r.Client, css.Spec.Namespaces, css.Spec.NamespaceSelectors)
+	if err != nil {
+		log.Error(err, "failed to get target Namespaces")
+		setFailed(css, map[string]error{"unknown": err})
+		return ctrl.Result{}, err
+	}
+
+	failedNamespaces := r.deleteOutdatedSecrets(ctx, namespaces, targetName, css.Name, css.Status.ProvisionedNamespaces)
+
+	var source v1.Secret
+	err = r.Get(ctx, types.NamespacedName{Name: css.Spec.Source.Name, Namespace: css.Spec.Source.Namespace}, &source)
+	if apierrors.IsNotFound(err) {
+		// keep the replicated Secrets, the source is replicated again once it exists.
+		removed := esutils.RemovedNamespaces(namespaces, css.Status.ProvisionedNamespaces)
+		css.Status.ProvisionedNamespaces = slices.DeleteFunc(slices.Clone(css.Status.ProvisionedNamespaces), func(ns string) bool {
+			_, failed := failedNamespaces[ns]
+			return slices.Contains(removed, ns) && !failed
+		})
+		css.Status.FailedNamespaces = toNamespaceFailures(failedNamespaces)
+		msg := fmt.Sprintf(msgSourceNotFound, css.Spec.Source.Namespace, css.Spec.Source.Name)
+		SetClusterSecretSyncCondition(css, *NewClusterSecretSyncCondition(v1.ConditionFalse, esapi.ReasonSourceNotFound, msg))
+		return ctrl.Result{RequeueAfter: refreshInt}, nil
+	}
+	if err != nil {
+		log.Error(err, errGetSource)
+		setFailed(css, map[string]error{"unknown": fmt.Errorf("%s: %w", errGetSource, err)})
+		return ctrl.Result{}, err
+	}
+
+	provisionedNamespaces := r.replicate(ctx, log, css, &source, namespaces, targetName, failedNamespaces)
+	sort.Strings(provisionedNamespaces)
+	css.Status.ProvisionedNamespaces = provisionedNamespaces
+	css.Status.SyncedResourceVersion = source.ResourceVersion
+	setFailed(css, failedNamespaces)
+
+	// Check if any failures are due to conflicts - if so, requeue immediately
+	for _, err := range failedNamespaces {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("conflict detected, requeuing immediately")
+			return ctrl.Result{}, fmt.Errorf("conflict detected, will retry: %w", err)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: refreshInt}, nil
+}
+
+// replicate creates or updates the target Secrets in the namespaces and returns the provisioned namespaces.
+func (r *Reconciler) replicate(
+	ctx context.Context,
+	log logr.Logger,
+	css *esapi.ClusterSecretSync,
+	source *v1.Secret,
+	namespaces []v1.Namespace,
+	targetName string,
+	failedNamespaces map[string]error,
+) []string {
+	var provisionedNamespaces []string
+	for _, namespace := range namespaces {
+		if namespace.DeletionTimestamp != nil {
+			continue
+		}
+		// never overwrite the source itself.
+		if namespace.Name == source.Namespace && targetName == source.Name {
+			continue
+		}
+		if err := r.createOrUpdateSecret(ctx, css, source, namespace.Name, targetName); err != nil {
+			if apierrors.IsConflict(err) {
+				log.V(1).Info("conflict while updating namespace, will retry", "namespace", namespace.Name)
+			} else {
+				log.Error(err, "failed to create or update secret", "namespace", namespace.Name)
+			}
+			failedNamespaces[namespace.Name] = err
+			continue
+		}
+		provisionedNamespaces = append(provisionedNamespaces, namespace.Name)
+	}
+	return provisionedNamespaces
+}
+
+func (r *Reconciler) createOrUpdateSecret(ctx context.Context, css *esapi.ClusterSecretSync, source *v1.Secret, namespace, targetName string) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      targetName,
+		},
+	}
+
+	mutateFunc := func() error {
+		// existing Secrets which were not replicated by the ClusterSecretSync are never overwritten.
+		if secret.ResourceVersion != "" && !isSecretOwnedBy(secret, css.Name) {
+			return errors.New(errSecretExists)
+		}
+		secret.Labels = css.Spec.Target.Labels
+		secret.Annotations = css.Spec.Target.Annotations
+		secret.Type = source.Type
+		secret.Data = source.Data
+
+		if err := controllerutil.SetControllerReference(css, secret, r.Scheme); err != nil {
+			return fmt.Errorf("could not set the controller owner reference %w", err)
+		}
+		return nil
+	}
+
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, secret, mutateFunc); err != nil {
+		return fmt.Errorf("could not create or update Secret: %w", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteSecret(ctx context.Context, cssName, secretName, namespace string) error {
+	var existing v1.Secret
+	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, &existing)
+	if err != nil {
+		// If we can't find it then just leave
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !isSecretOwnedBy(&existing, cssName) {
+		return nil
+	}
+
+	if err := r.Delete(ctx, &existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("secret in non matching namespace could not be deleted: %w", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteOutdatedSecrets(ctx context.Context, namespaces []v1.Namespace, secretName, cssName string, provisionedNamespaces []string) map[string]error {
+	failedNamespaces := map[string]error{}
+	for _, namespace := range esutils.RemovedNamespaces(namespaces, provisionedNamespaces) {
+		if err := r.deleteSecret(ctx, cssName, secretName, namespace); err != nil {
+			r.Log.Error(err, "unable to delete secret")
+			failedNamespaces[namespace] = err
+		}
+	}
+	return failedNamespaces
+}
+
+func getTargetName(css *esapi.ClusterSecretSync) string {
+	if css.Spec.Target.Name != "" {
+		return css.Spec.Target.Name
+	}
+	return css.Spec.Source.Name
+}
+
+func isSecretOwnedBy(secret *v1.Secret, cssName string) bool {
+	owner := metav1.GetControllerOf(secret)
+	return owner != nil && schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).GroupKind().String() == esapi.ClusterSecretSyncGroupKind && owner.Name == cssName
+}
+
+func setFailed(css *esapi.ClusterSecretSync, failedNamespaces map[string]error) {
+	css.Status.FailedNamespaces = toNamespaceFailures(failedNamespaces)
+	if len(failedNamespaces) == 0 {
+		SetClusterSecretSyncCondition(css, *NewClusterSecretSyncCondition(v1.ConditionTrue, esapi.ReasonSynced, ""))
+		return
+	}
+	SetClusterSecretSyncCondition(css, *NewClusterSecretSyncCondition(v1.ConditionFalse, esapi.ReasonErrored, errNamespacesFailed))
+}
+
+func toNamespaceFailures(failedNamespaces map[string]error) []esapi.ClusterSecretSyncNamespaceFailure {
+	return esutils.NamespaceFailures(failedNamespaces, func(namespace, reason string) esapi.ClusterSecretSyncNamespaceFailure {
+		return esapi.ClusterSecretSyncNamespaceFailure{Namespace: namespace, Reason: reason}
+	})
+}
+
+// NewClusterSecretSyncCondition creates a new Ready condition of a ClusterSecretSync.
+func NewClusterSecretSyncCondition(status v1.ConditionStatus, reason, message string) *esapi.ClusterSecretSyncStatusCondition {
+	return &esapi.ClusterSecretSyncStatusCondition{
+		Type:               esapi.ClusterSecretSyncReady,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// SetClusterSecretSyncCondition updates the ClusterSecretSync to include the provided condition.
+func SetClusterSecretSyncCondition(css *esapi.ClusterSecretSync, condition esapi.ClusterSecretSyncStatusCondition) {
+	conditions := make([]esapi.ClusterSecretSyncStatusCondition, 0, len(css.Status.Conditions))
+	for _, c := range css.Status.Conditions {
+		if c.Type != condition.Type {
+			conditions = append(conditions, c)
+			continue
+		}
+		// Do not update lastTransitionTime if the status of the condition doesn't change.
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+	}
+	css.Status.Conditions = append(conditions, condition)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts controller.Options) error {
+	// Index ClusterSecretSyncs by their source Secret, so changes of the source are replicated right away
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &esapi.ClusterSecretSync{}, indexSourceSecretField, func(obj client.Object) []string {
+		css := obj.(*esapi.ClusterSecretSync)
+		return []string{sourceKey(css.Spec.Source.Namespace, css.Spec.Source.Name)}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&esapi.ClusterSecretSync{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+		))).
+		// we use WatchesMetadata() to reduce memory usage, as otherwise we have to process full secret objects.
+		WatchesMetadata(
+			&v1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&v1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForNamespace),
+			builder.WithPredicates(esutils.NamespacePredicate()),
+		).
+		Complete(r)
+}
+
+func sourceKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// findObjectsForSecret returns the ClusterSecretSyncs which replicate the Secret, or which own it.
+func (r *Reconciler) findObjectsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	if owner := metav1.GetControllerOf(secret); owner != nil &&
+		schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).GroupKind().String() == esapi.ClusterSecretSyncGroupKind {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: owner.Name}})
+	}
+
+	var clusterSecretSyncs esapi.ClusterSecretSyncList
+	if err := r.List(ctx, &clusterSecretSyncs, client.MatchingFields{
+		indexSourceSecretField: sourceKey(secret.GetNamespace(), secret.GetName()),
+	}); err != nil {
+		r.Log.Error(err, errGetCSS)
+		return requests
+	}
+	for i := range clusterSecretSyncs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterSecretSyncs.Items[i].Name}})
+	}
+	return requests
+}
+
+// findObjectsForNamespace returns the ClusterSecretSyncs which target the namespace,
+// or which replicated the Secret to it before so it can be removed.
+func (r *Reconciler) findObjectsForNamespace(ctx context.Context, namespace client.Object) []reconcile.Request {
+	var clusterSecretSyncs esapi.ClusterSecretSyncList
+	if err := r.List(ctx, &clusterSecretSyncs); err != nil {
+		r.Log.Error(err, errGetCSS)
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for i := range clusterSecretSyncs.Items {
+		css := &clusterSecretSyncs.Items[i]
+		if r.targetsNamespace(css, namespace) || slices.Contains(css.Status.ProvisionedNamespaces, namespace.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: css.Name}})
+		}
+	}
+	return requests
+}
+
+func (r *Reconciler) targetsNamespace(css *esapi.ClusterSecretSync, namespace client.Object) bool {
+	if slices.Contains(css.Spec.Namespaces, namespace.GetName()) {
+		return true
+	}
+	for _, selector := range css.Spec.NamespaceSelectors {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			r.Log.Error(err, errConvertLabelSelector)
+			continue
+		}
+		if labelSelector.Matches(labels.Set(namespace.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustersecretsync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esapi.AddToScheme(scheme))
+
+	namespace := func(name string, lbls map[string]string) *v1.Namespace {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/metadata.name": name}}}
+		for k, v := range lbls {
+			ns.Labels[k] = v
+		}
+		return ns
+	}
+	css := &esapi.ClusterSecretSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "css"},
+		Spec: esapi.ClusterSecretSyncSpec{
+			Source: esapi.ClusterSecretSyncSource{Name: "tls", Namespace: "source"},
+			Target: esapi.ClusterSecretSyncTarget{Labels: map[string]string{"app": "web"}},
+			NamespaceSelectors: []*metav1.LabelSelector{
+				{MatchLabels: map[string]string{"tls": "true"}},
+			},
+			Namespaces: []string{"source"},
+		},
+	}
+	fakeClient := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&esapi.ClusterSecretSync{}).
+		WithIndex(&esapi.ClusterSecretSync{}, indexSourceSecretField, func(obj client.Object) []string {
+			css := obj.(*esapi.ClusterSecretSync)
+			return []string{sourceKey(css.Spec.Source.Namespace, css.Spec.Source.Name)}
+		}).
+		WithObjects(
+			css,
+			namespace("source", nil),
+			namespace("team-a", map[string]string{"tls": "true"}),
+			namespace("team-b", map[string]string{"tls": "true"}),
+			namespace("team-c", map[string]string{"tls": "true"}),
+			namespace("other", nil),
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "source"},
+				Type:       v1.SecretTypeTLS,
+				Data:       map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "team-c"},
+				Data:       map[string][]byte{"unmanaged": []byte("true")},
+			},
+		).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard(), Scheme: scheme, RequeueInterval: time.Hour}
+	reconcileCSS := func() *esapi.ClusterSecretSync {
+		t.Helper()
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "css"}})
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, res.RequeueAfter)
+		var got esapi.ClusterSecretSync
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "css"}, &got))
+		return &got
+	}
+	getSecret := func(ns string) (*v1.Secret, error) {
+		var secret v1.Secret
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: "tls", Namespace: ns}, &secret)
+		return &secret, err
+	}
+
+	// the source is replicated to the selected namespaces, existing Secrets are not overwritten.
+	got := reconcileCSS()
+	assert.Equal(t, []string{"team-a", "team-b"}, got.Status.ProvisionedNamespaces)
+	assert.Equal(t, []esapi.ClusterSecretSyncNamespaceFailure{{Namespace: "team-c", Reason: "could not create or update Secret: " + errSecretExists}}, got.Status.FailedNamespaces)
+	assert.Equal(t, v1.ConditionFalse, got.Status.Conditions[0].Status)
+	secret, err := getSecret("team-a")
+	require.NoError(t, err)
+	assert.Equal(t, v1.SecretTypeTLS, secret.Type)
+	assert.Equal(t, []byte("crt"), secret.Data["tls.crt"])
+	assert.Equal(t, map[string]string{"app": "web"}, secret.Labels)
+	assert.True(t, isSecretOwnedBy(secret, "css"))
+	unmanaged, err := getSecret("team-c")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("true"), unmanaged.Data["unmanaged"])
+
+	// changes of the source and of the replicated Secrets map to the ClusterSecretSync.
+	source, err := getSecret("source")
+	require.NoError(t, err)
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "css"}}}
+	assert.Equal(t, want, r.findObjectsForSecret(ctx, source))
+	assert.Equal(t, want, r.findObjectsForSecret(ctx, secret))
+	assert.Empty(t, r.findObjectsForSecret(ctx, unmanaged))
+
+	source.Data["tls.crt"] = []byte("renewed")
+	require.NoError(t, fakeClient.Update(ctx, source))
+	require.NoError(t, fakeClient.Delete(ctx, unmanaged))
+	got = reconcileCSS()
+	assert.Equal(t, []string{"team-a", "team-b", "team-c"}, got.Status.ProvisionedNamespaces)
+	assert.Equal(t, source.ResourceVersion, got.Status.SyncedResourceVersion)
+	assert.Equal(t, v1.ConditionTrue, got.Status.Conditions[0].Status)
+	secret, err = getSecret("team-b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("renewed"), secret.Data["tls.crt"])
+
+	// Secrets are removed from namespaces which are no longer selected, the namespace still maps to the ClusterSecretSync.
+	var teamB v1.Namespace
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "team-b"}, &teamB))
+	delete(teamB.Labels, "tls")
+	require.NoError(t, fakeClient.Update(ctx, &teamB))
+	assert.Equal(t, want, r.findObjectsForNamespace(ctx, &teamB))
+	got = reconcileCSS()
+	assert.Equal(t, []string{"team-a", "team-c"}, got.Status.ProvisionedNamespaces)
+	_, err = getSecret("team-b")
+	assert.True(t, apierrors.IsNotFound(err))
+	var other v1.Namespace
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "other"}, &other))
+	assert.Empty(t, r.findObjectsForNamespace(ctx, &other))
+
+	// a missing source keeps the replicated Secrets.
+	require.NoError(t, fakeClient.Delete(ctx, source))
+	got = reconcileCSS()
+	assert.Equal(t, esapi.ReasonSourceNotFound, got.Status.Conditions[0].Reason)
+	assert.Equal(t, []string{"team-a", "team-c"}, got.Status.ProvisionedNamespaces)
+	_, err = getSecret("team-a")
+	require.NoError(t, err)
+}
@@ -0,0 +1,347 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package esindex implements a reverse index from the remote refs of stores to the ExternalSecrets reading them.
+package esindex
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+const (
+	// DebugPath is the path of the debug endpoint serving the index.
+	DebugPath = "/debug/externalsecrets/index"
+
+	// IndexStoreKeyField indexes ExternalSecrets by the store and remote key they read, see FieldValue.
+	IndexStoreKeyField = ".spec.remoteRef.storeKey"
+
+	// AnyKey is the key of refs using dataFrom.find, as they may read any key of the store.
+	AnyKey = "*"
+)
+
+// StoreRef identifies a store, the namespace is empty for ClusterSecretStores.
+type StoreRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Ref is a remote ref of a store read by an ExternalSecret.
+type Ref struct {
+	Store    StoreRef `json:"store"`
+	Key      string   `json:"key"`
+	Property string   `json:"property,omitempty"`
+}
+
+// matches returns true if the ref matches the query, empty fields of the query match any value.
+// Refs with AnyKey match every key.
+func (r Ref) matches(query Ref) bool {
+	return (query.Store.Kind == "" || query.Store.Kind == r.Store.Kind) &&
+		(query.Store.Namespace == "" || query.Store.Namespace == r.Store.Namespace) &&
+		(query.Store.Name == "" || query.Store.Name == r.Store.Name) &&
+		(query.Key == "" || r.Key == AnyKey || query.Key == r.Key) &&
+		(query.Property == "" || r.Key == AnyKey || query.Property == r.Property)
+}
+
+// Index maps remote refs to the ExternalSecrets reading them.
+// It is maintained by the ExternalSecret controller during Reconcile, and is safe for concurrent use.
+type Index struct {
+	mu    sync.RWMutex
+	refs  map[types.NamespacedName][]Ref
+	byRef map[Ref]map[types.NamespacedName]struct{}
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{
+		refs:  make(map[types.NamespacedName][]Ref),
+		byRef: make(map[Ref]map[types.NamespacedName]struct{}),
+	}
+}
+
+// Update replaces the refs of an ExternalSecret with the refs of its current spec.
+// It is a no-op on a nil index.
+func (i *Index) Update(es *esv1.ExternalSecret) {
+	if i == nil {
+		return
+	}
+	name := client.ObjectKeyFromObject(es)
+	refs := RefsFor(es)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if slices.Equal(i.refs[name], refs) {
+		return
+	}
+	i.remove(name)
+	if len(refs) == 0 {
+		return
+	}
+	i.refs[name] = refs
+	for _, ref := range refs {
+		if i.byRef[ref] == nil {
+			i.byRef[ref] = make(map[types.NamespacedName]struct{})
+		}
+		i.byRef[ref][name] = struct{}{}
+	}
+}
+
+// Delete removes an ExternalSecret from the index.
+// It is a no-op on a nil index.
+func (i *Index) Delete(name types.NamespacedName) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(name)
+}
+
+func (i *Index) remove(name types.NamespacedName) {
+	for _, ref := range i.refs[name] {
+		delete(i.byRef[ref], name)
+		if len(i.byRef[ref]) == 0 {
+			delete(i.byRef, ref)
+		}
+	}
+	delete(i.refs, name)
+}
+
+// Lookup returns the ExternalSecrets reading a remote ref, sorted by namespace and name.
+// Empty fields of the query match any value, e.g. a query with only the store set
+// returns all ExternalSecrets reading from that store.
+func (i *Index) Lookup(query Ref) []types.NamespacedName {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	found := make(map[types.NamespacedName]struct{})
+	for ref, names := range i.byRef {
+		if !ref.matches(query) {
+			continue
+		}
+		for name := range names {
+			found[name] = struct{}{}
+		}
+	}
+	out := make([]types.NamespacedName, 0, len(found))
+	for name := range found {
+		out = append(out, name)
+	}
+	slices.SortFunc(out, compareNames)
+	return out
+}
+
+// Refs returns the remote refs read by an ExternalSecret.
+func (i *Index) Refs(name types.NamespacedName) []Ref {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return slices.Clone(i.refs[name])
+}
+
+// Entry is an entry of the index as served by the debug endpoint.
+type Entry struct {
+	Ref             Ref                    `json:"ref"`
+	ExternalSecrets []types.NamespacedName `json:"externalSecrets"`
+}
+
+// Entries returns all entries of the index matching the query, sorted by ref.
+func (i *Index) Entries(query Ref) []Entry {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var entries []Entry
+	for ref, names := range i.byRef {
+		if !ref.matches(query) {
+			continue
+		}
+		entry := Entry{Ref: ref}
+		for name := range names {
+			entry.ExternalSecrets = append(entry.ExternalSecrets, name)
+		}
+		slices.SortFunc(entry.ExternalSecrets, compareNames)
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(
+			cmp.Compare(a.Ref.Store.Kind, b.Ref.Store.Kind),
+			cmp.Compare(a.Ref.Store.Namespace, b.Ref.Store.Namespace),
+			cmp.Compare(a.Ref.Store.Name, b.Ref.Store.Name),
+			cmp.Compare(a.Ref.Key, b.Ref.Key),
+			cmp.Compare(a.Ref.Property, b.Ref.Property),
+		)
+	})
+	return entries
+}
+
+// ServeHTTP serves the entries of the index as JSON.
+// The entries can be filtered with the query parameters kind, namespace, store, key and property.
+func (i *Index) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	params := req.URL.Query()
+	query := Ref{
+		Store: StoreRef{
+			Kind:      params.Get("kind"),
+			Namespace: params.Get("namespace"),
+			Name:      params.Get("store"),
+		},
+		Key:      params.Get("key"),
+		Property: params.Get("property"),
+	}
+	entries := i.Entries(query)
+	if entries == nil {
+		entries = []Entry{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// RefsFor returns the remote refs read by an ExternalSecret, sorted and without duplicates.
+// Refs of fallback stores are included, refs of generators are not.
+func RefsFor(es *esv1.ExternalSecret) []Ref {
+	var refs []Ref
+	add := func(storeRef esv1.SecretStoreRef, key, property string) {
+		if storeRef.Name == "" {
+			storeRef = es.Spec.SecretStoreRef
+		}
+		if storeRef.Name == "" {
+			return
+		}
+		stores := []StoreRef{storeRefFor(es, storeRef.Name, storeRef.Kind)}
+		for _, fallback := range storeRef.FallbackStoreRefs {
+			stores = append(stores, storeRefFor(es, fallback.Name, fallback.Kind))
+		}
+		for _, store := range stores {
+			refs = append(refs, Ref{Store: store, Key: key, Property: property})
+		}
+	}
+
+	for _, data := range es.Spec.Data {
+		var storeRef esv1.SecretStoreRef
+		if data.SourceRef != nil {
+			storeRef = data.SourceRef.SecretStoreRef
+		}
+		add(storeRef, data.RemoteRef.Key, data.RemoteRef.Property)
+	}
+	for _, dataFrom := range es.Spec.DataFrom {
+		var storeRef esv1.SecretStoreRef
+		if dataFrom.SourceRef != nil {
+			if dataFrom.SourceRef.GeneratorRef != nil {
+				continue
+			}
+			if dataFrom.SourceRef.SecretStoreRef != nil {
+				storeRef = *dataFrom.SourceRef.SecretStoreRef
+			}
+		}
+		if dataFrom.Extract != nil {
+			add(storeRef, dataFrom.Extract.Key, dataFrom.Extract.Property)
+		}
+		if dataFrom.Find != nil {
+			add(storeRef, AnyKey, "")
+		}
+	}
+
+	slices.SortFunc(refs, func(a, b Ref) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return slices.Compact(refs)
+}
+
+func storeRefFor(es *esv1.ExternalSecret, name, kind string) StoreRef {
+	if kind == esv1.ClusterSecretStoreKind {
+		return StoreRef{Kind: kind, Name: name}
+	}
+	return StoreRef{Kind: esv1.SecretStoreKind, Namespace: es.Namespace, Name: name}
+}
+
+// String returns the ref in the format kind/namespace/name/key#property.
+func (r Ref) String() string {
+	s := FieldValue(r.Store, r.Key)
+	if r.Property != "" {
+		s += "#" + r.Property
+	}
+	return s
+}
+
+// FieldValue returns the value of IndexStoreKeyField for a store and remote key.
+// A zero store returns the value indexed for the key regardless of the store.
+func FieldValue(store StoreRef, key string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", store.Kind, store.Namespace, store.Name, key)
+}
+
+// IndexStoreKeys returns the values of IndexStoreKeyField of an ExternalSecret.
+// Every key is indexed with and without its store, as some lookups do not know the store.
+func IndexStoreKeys(obj client.Object) []string {
+	es, ok := obj.(*esv1.ExternalSecret)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, ref := range RefsFor(es) {
+		values = append(values, FieldValue(ref.Store, ref.Key), FieldValue(StoreRef{}, ref.Key))
+	}
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+// List returns the ExternalSecrets reading a remote key of a store, using the field indexed cache of the reader.
+// Unlike Lookup, it does not depend on the ExternalSecrets having been reconciled,
+// but the key of the query must be set and the property is not taken into account.
+// A zero store matches any store.
+func List(ctx context.Context, reader client.Reader, store StoreRef, key string) ([]types.NamespacedName, error) {
+	found := make(map[types.NamespacedName]struct{})
+	for _, k := range []string{key, AnyKey} {
+		list, err := ListByKey(ctx, reader, store, k)
+		if err != nil {
+			return nil, err
+		}
+		for j := range list {
+			found[client.ObjectKeyFromObject(&list[j])] = struct{}{}
+		}
+	}
+	out := make([]types.NamespacedName, 0, len(found))
+	for name := range found {
+		out = append(out, name)
+	}
+	slices.SortFunc(out, compareNames)
+	return out, nil
+}
+
+// ListByKey returns the ExternalSecrets indexed with exactly the store and key.
+// The ExternalSecrets using dataFrom.find are only returned for AnyKey.
+func ListByKey(ctx context.Context, reader client.Reader, store StoreRef, key string) ([]esv1.ExternalSecret, error) {
+	var list esv1.ExternalSecretList
+	if err := reader.List(ctx, &list, client.MatchingFields{IndexStoreKeyField: FieldValue(store, key)}); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func compareNames(a, b types.NamespacedName) int {
+	return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package esindex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+var (
+	vault  = StoreRef{Kind: esv1.SecretStoreKind, Namespace: "default", Name: "vault"}
+	aws    = StoreRef{Kind: esv1.ClusterSecretStoreKind, Name: "aws"}
+	backup = StoreRef{Kind: esv1.SecretStoreKind, Namespace: "default", Name: "backup"}
+)
+
+func newES(name string) *esv1.ExternalSecret {
+	return &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef: esv1.SecretStoreRef{
+				Name:              "vault",
+				FallbackStoreRefs: []esv1.NamedStoreRef{{Name: "backup"}},
+			},
+			Data: []esv1.ExternalSecretData{
+				{SecretKey: "user", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "user"}},
+				{SecretKey: "pass", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "pass"}},
+				{
+					SecretKey: "token",
+					RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "token"},
+					SourceRef: &esv1.StoreSourceRef{SecretStoreRef: esv1.SecretStoreRef{Name: "aws", Kind: esv1.ClusterSecretStoreKind}},
+				},
+			},
+			DataFrom: []esv1.ExternalSecretDataFromRemoteRef{
+				{Find: &esv1.ExternalSecretFind{Path: new("app/")}, SourceRef: &esv1.StoreGeneratorSourceRef{SecretStoreRef: &esv1.SecretStoreRef{Name: "aws", Kind: esv1.ClusterSecretStoreKind}}},
+				{SourceRef: &esv1.StoreGeneratorSourceRef{GeneratorRef: &esv1.GeneratorRef{Name: "password"}}},
+			},
+		},
+	}
+}
+
+func TestRefsFor(t *testing.T) {
+	assert.Equal(t, []Ref{
+		{Store: aws, Key: AnyKey},
+		{Store: aws, Key: "token"},
+		{Store: backup, Key: "db", Property: "pass"},
+		{Store: backup, Key: "db", Property: "user"},
+		{Store: vault, Key: "db", Property: "pass"},
+		{Store: vault, Key: "db", Property: "user"},
+	}, RefsFor(newES("es")))
+}
+
+func TestIndex(t *testing.T) {
+	index := New()
+	es := newES("es")
+	other := newES("other")
+	other.Spec.Data = other.Spec.Data[:1]
+	other.Spec.DataFrom = nil
+	index.Update(es)
+	index.Update(other)
+
+	esName := types.NamespacedName{Namespace: "default", Name: "es"}
+	otherName := types.NamespacedName{Namespace: "default", Name: "other"}
+	assert.Equal(t, []types.NamespacedName{esName, otherName}, index.Lookup(Ref{Store: vault, Key: "db"}))
+	assert.Equal(t, []types.NamespacedName{esName}, index.Lookup(Ref{Store: vault, Key: "db", Property: "pass"}))
+	assert.Equal(t, []types.NamespacedName{esName, otherName}, index.Lookup(Ref{Store: backup}))
+	// find matches any key of the store.
+	assert.Equal(t, []types.NamespacedName{esName}, index.Lookup(Ref{Store: aws, Key: "app/unknown"}))
+	assert.Empty(t, index.Lookup(Ref{Store: vault, Key: "unknown"}))
+
+	// changing the spec replaces the refs.
+	other.Spec.Data[0].RemoteRef.Key = "cache"
+	index.Update(other)
+	assert.Equal(t, []types.NamespacedName{esName}, index.Lookup(Ref{Store: vault, Key: "db", Property: "user"}))
+	assert.Equal(t, []types.NamespacedName{otherName}, index.Lookup(Ref{Store: vault, Key: "cache"}))
+
+	index.Delete(esName)
+	assert.Empty(t, index.Refs(esName))
+	assert.Empty(t, index.Lookup(Ref{Store: aws}))
+
+	// the debug endpoint serves the matching entries.
+	rec := httptest.NewRecorder()
+	index.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugPath+"?store=vault", http.NoBody))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var entries []Entry
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	assert.Equal(t, []Entry{{Ref: Ref{Store: vault, Key: "cache", Property: "user"}, ExternalSecrets: []types.NamespacedName{otherName}}}, entries)
+
+	// a nil index is a no-op.
+	var nilIndex *Index
+	nilIndex.Update(es)
+	nilIndex.Delete(esName)
+}
+
+func TestList(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, esv1.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newES("es")).
+		WithIndex(&esv1.ExternalSecret{}, IndexStoreKeyField, IndexStoreKeys).
+		Build()
+
+	esName := types.NamespacedName{Namespace: "default", Name: "es"}
+	found, err := List(context.Background(), kube, vault, "db")
+	require.NoError(t, err)
+	assert.Equal(t, []types.NamespacedName{esName}, found)
+
+	found, err = List(context.Background(), kube, aws, "app/any")
+	require.NoError(t, err)
+	assert.Equal(t, []types.NamespacedName{esName}, found)
+
+	found, err = List(context.Background(), kube, vault, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// a zero store matches the key in any store.
+	found, err = List(context.Background(), kube, StoreRef{}, "token")
+	require.NoError(t, err)
+	assert.Equal(t, []types.NamespacedName{esName}, found)
+
+	// dataFrom.find is only listed by key for AnyKey.
+	byKey, err := ListByKey(context.Background(), kube, aws, "app/any")
+	require.NoError(t, err)
+	assert.Empty(t, byKey)
+	byKey, err = ListByKey(context.Background(), kube, aws, AnyKey)
+	require.NoError(t, err)
+	assert.Len(t, byKey, 1)
+}
@@ -39,6 +39,10 @@ const (
 	ExternalSecretStatusConditionKey = "status_condition"
 	// ExternalSecretReconcileDurationKey is the metric key for the external secret reconcile duration.
 	ExternalSecretReconcileDurationKey = "reconcile_duration"
+	// ExternalSecretNextRefreshKey is the metric key for the time of the next scheduled refresh.
+	ExternalSecretNextRefreshKey = "next_refresh_timestamp_seconds"
+	// ExternalSecretRefreshJitterKey is the metric key for the jitter of the next scheduled refresh.
+	ExternalSecretRefreshJitterKey = "refresh_jitter_seconds"
 )
 
 var counterVecMetrics = map[string]*prometheus.CounterVec{}
@@ -73,7 +77,20 @@ func SetUpMetrics() {
 		Help:      "The duration time to reconcile the External Secret",
 	}, ctrlmetrics.NonConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration)
+	externalSecretNextRefresh := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      ExternalSecretNextRefreshKey,
+		Help:      "The unix time of the next scheduled refresh of the External Secret",
+	}, ctrlmetrics.NonConditionMetricLabelNames)
+
+	externalSecretRefreshJitter := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      ExternalSecretRefreshJitterKey,
+		Help:      "The delay added to the next scheduled refresh of the External Secret",
+	}, ctrlmetrics.NonConditionMetricLabelNames)
+
+	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration,
+		externalSecretNextRefresh, externalSecretRefreshJitter)
 
 	counterVecMetrics = map[string]*prometheus.CounterVec{
 		SyncCallsKey:      syncCallsTotal,
@@ -83,6 +100,18 @@ func SetUpMetrics() {
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
 		ExternalSecretStatusConditionKey:   externalSecretCondition,
 		ExternalSecretReconcileDurationKey: externalSecretReconcileDuration,
+		ExternalSecretNextRefreshKey:       externalSecretNextRefresh,
+		ExternalSecretRefreshJitterKey:     externalSecretRefreshJitter,
+	}
+}
+
+// RemoveSchedule removes the schedule metrics of a deleted External Secret.
+func RemoveSchedule(name, namespace string) {
+	labels := prometheus.Labels{"name": name, "namespace": namespace}
+	for _, key := range []string{ExternalSecretNextRefreshKey, ExternalSecretRefreshJitterKey} {
+		if gauge := GetGaugeVec(key); gauge != nil {
+			gauge.DeletePartialMatch(labels)
+		}
 	}
 }
 
@@ -22,7 +22,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"maps"
+	"math/rand/v2"
 	"slices"
 	"strings"
 	"time"
@@ -54,6 +56,7 @@ import (
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	// Metrics.
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esindex"
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
 	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
@@ -75,6 +78,9 @@ const (
 	msgSynced       = "secret synced"
 	msgSyncedRetain = "secret retained due to DeletionPolicy=Retain"
 
+	// condition messages for "SecretPartiallySynced" reason.
+	msgPartiallySynced = "secret synced, %d key(s) kept their last-known value: %s"
+
 	// condition messages for "SecretDeleted" reason.
 	msgDeleted = "secret deleted due to DeletionPolicy=Delete"
 
@@ -97,6 +103,7 @@ const (
 	logErrorPatchSecret          = "unable to patch Secret"
 	logErrorSecretCacheNotSynced = "controller caches for Secret are not in sync"
 	logErrorUnmanagedStore       = "unable to determine if store is managed"
+	logStoreFailed               = "store failed, trying fallback store"
 
 	// error formats.
 	errConvert               = "error applying conversion strategy %s to keys: %w"
@@ -112,6 +119,9 @@ const (
 	errUpdateNotFound        = "unable to update secret %s: not found"
 	errDeleteCreatePolicy    = "unable to delete secret %s: creationPolicy=%s is not Owner"
 	errSecretCachesNotSynced = "controller caches for secret %s are not in sync"
+	errStoreFailed           = "store %s/%s: %w"
+	errStoreMissing          = "store %s/%s: secret does not exist in fallback store"
+	errPartialTemplate       = "errorPolicy=Partial can not be used with target.template: %w"
 
 	// event messages.
 	eventCreated                  = "secret created"
@@ -120,6 +130,8 @@ const (
 	eventDeletedOrphaned          = "secret deleted because it was orphaned"
 	eventMissingProviderSecret    = "secret does not exist at provider using spec.dataFrom[%d]"
 	eventMissingProviderSecretKey = "secret does not exist at provider using spec.dataFrom[%d] (key=%s)"
+	eventStoreFallback            = "store %s/%s failed, data served by fallback store %s/%s"
+	eventPartiallySynced          = "keys could not be fetched and kept their last-known value: %s"
 
 	// cacheSyncRetryDelay is used when partial and full secret caches are temporarily out of sync.
 	cacheSyncRetryDelay = 200 * time.Millisecond
@@ -153,7 +165,17 @@ type Reconciler struct {
 	EnableFloodGate                    bool
 	EnableGeneratorState               bool
 	AllowGenericTargets                bool
-	recorder                           record.EventRecorder
+	// RefreshJitter is the default jitter of the periodic refreshes, it is overridden by spec.refreshJitter.
+	RefreshJitter esv1.RefreshJitter
+	// GeneratorRenewalPercent is the percentage of the remaining lifetime of generated values with an expiry
+	// after which they are regenerated. Set to 0 to only regenerate them on the refresh interval.
+	GeneratorRenewalPercent int32
+	// HistoryEncryptionKey encrypts the revisions of the target Secrets, see spec.target.history.
+	// Without a key, revisions are only recorded if the ExternalSecret allows plaintext revisions.
+	HistoryEncryptionKey []byte
+	// RemoteRefIndex maps remote refs to the ExternalSecrets reading them, it is maintained during Reconcile.
+	RemoteRefIndex *esindex.Index
+	recorder       record.EventRecorder
 
 	// informerManager manages dynamic informers for generic targets
 	informerManager InformerManager
@@ -190,6 +212,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 					Namespace: req.Namespace,
 				},
 			}, *conditionSynced)
+			esmetrics.RemoveSchedule(req.Name, req.Namespace)
+			r.RemoteRefIndex.Delete(req.NamespacedName)
 
 			return ctrl.Result{}, nil
 		}
@@ -199,17 +223,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, err
 	}
 
+	// keep track of the remote refs read by the ExternalSecret.
+	r.RemoteRefIndex.Update(externalSecret)
+
 	// Handle deletion with finalizer
 	if !externalSecret.GetDeletionTimestamp().IsZero() {
+		r.RemoteRefIndex.Delete(req.NamespacedName)
+
 		// Always attempt cleanup to handle edge case where finalizer might be removed externally
 		if err := r.cleanupManagedSecrets(ctx, log, externalSecret); err != nil {
 			log.Error(err, "failed to cleanup managed secrets")
 			return ctrl.Result{}, err
 		}
 
-		// Release informer for generic targets
-		if isGenericTarget(externalSecret) && r.informerManager != nil {
-			gvk := getTargetGVK(externalSecret)
+		// Release informers for generic targets
+		for _, target := range allTargets(externalSecret) {
+			if !isGenericTarget(target) || r.informerManager == nil {
+				continue
+			}
+			gvk := getTargetGVK(target)
 			esName := types.NamespacedName{Name: externalSecret.Name, Namespace: externalSecret.Namespace}
 			if err := r.informerManager.ReleaseInformer(ctx, gvk, esName); err != nil {
 				log.Error(err, "failed to release informer for generic target",
@@ -284,6 +316,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return r.reconcileGenericTarget(ctx, externalSecret, log, start, resourceLabels, syncCallsError)
 	}
 
+	// in dry-run mode, the target secret is rendered but never written
+	if isDryRun(externalSecret) {
+		return r.reconcileDryRun(ctx, externalSecret, log, start, resourceLabels, syncCallsError)
+	}
+
 	// the target secret name defaults to the ExternalSecret name, if not explicitly set
 	secretName := externalSecret.Spec.Target.Name
 	if secretName == "" {
@@ -361,7 +398,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	//     - it exists
 	//     - it has the correct "managed" label
 	//     - it has the correct "data-hash" annotation
-	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret, externalSecret) {
+	// 5. the additional targets are valid
+	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret, externalSecret) && r.additionalTargetsValid(ctx, log, externalSecret) {
 		log.V(1).Info("skipping refresh")
 		return r.getRequeueResult(externalSecret), nil
 	}
@@ -405,11 +443,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		}
 	}()
 
-	// retrieve the provider secret data.
-	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
+	// the preview is only kept while in dry-run mode.
+	externalSecret.Status.Preview = nil
+
+	// if the target is pinned to a previous revision, we use the data of that revision
+	// instead of the provider secret data.
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	pinnedRevision, err := getRollbackRevision(externalSecret)
 	if err != nil {
-		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-		return ctrl.Result{}, err
+		r.markAsFailed(msgErrorRollback, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, nil
+	}
+
+	var dataMap map[string][]byte
+	if pinnedRevision > 0 {
+		dataMap, err = r.getRevisionData(ctx, externalSecret, pinnedRevision)
+		if err != nil {
+			r.markAsFailed(msgErrorRollback, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
+		}
+	} else {
+		// retrieve the provider secret data.
+		dataMap, err = r.GetProviderSecretData(ctx, externalSecret)
+		if err != nil {
+			r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
+		}
+
+		// the additional targets are rendered from the same provider data,
+		// a pinned revision only applies to the primary target.
+		err = r.reconcileAdditionalTargets(ctx, log, externalSecret, dataMap)
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				log.V(1).Info("conflict while updating targets, will requeue")
+				return ctrl.Result{Requeue: true}, nil
+			}
+			r.markAsFailed(msgErrorUpdateTargets, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
+		}
 	}
 
 	// if no data was found we can delete the secret if needed.
@@ -449,7 +520,117 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	}
 
 	// mutationFunc is a function which can be applied to a secret to make it match the desired state.
+	// the data of a pinned revision has already been rendered, so it is applied as-is.
+	baseMutationFunc := r.secretMutationFunc(ctx, externalSecret, dataMap)
+	if pinnedRevision > 0 {
+		baseMutationFunc = r.secretMutationFunc(ctx, pinnedExternalSecret(externalSecret), dataMap)
+	}
+
+	// keep track of the rendered data, so it can be recorded in the history.
+	var renderedData map[string][]byte
 	mutationFunc := func(secret *v1.Secret) error {
+		if err := baseMutationFunc(secret); err != nil {
+			return err
+		}
+		renderedData = secret.Data
+		return nil
+	}
+
+	switch externalSecret.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyNone:
+		log.V(1).Info("secret creation skipped due to CreationPolicy=None")
+		err = nil
+	case esv1.CreatePolicyMerge:
+		// update the secret, if it exists
+		if existingSecret.UID != "" {
+			err = r.updateSecret(ctx, existingSecret, mutationFunc, externalSecret, secretName)
+		} else {
+			// if the secret does not exist, we wait until the next refresh interval
+			// rather than returning an error which would requeue immediately
+			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretMissing, msgMissing)
+			return r.getRequeueResult(externalSecret), nil
+		}
+	case esv1.CreatePolicyOrphan:
+		// create the secret, if it does not exist
+		if existingSecret.UID == "" {
+			err = r.createSecret(ctx, mutationFunc, externalSecret, secretName)
+		} else {
+			// if the secret exists, we should update it
+			err = r.updateSecret(ctx, existingSecret, mutationFunc, externalSecret, secretName)
+		}
+	case esv1.CreatePolicyOwner:
+		// we may have orphaned secrets to clean up,
+		// for example, if the target secret name was changed
+		err = r.deleteOrphanedSecrets(ctx, externalSecret, targetSecretNames(externalSecret)...)
+		if err != nil {
+			r.markAsFailed(msgErrorDeleteOrphaned, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
+		}
+
+		// create the secret, if it does not exist
+		if existingSecret.UID == "" {
+			err = r.createSecret(ctx, mutationFunc, externalSecret, secretName)
+		} else {
+			// if the secret exists, we should update it
+			err = r.updateSecret(ctx, existingSecret, mutationFunc, externalSecret, secretName)
+		}
+	}
+	if err != nil {
+		// if we got an update conflict, we should requeue immediately
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("conflict while updating secret, will requeue")
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		// detect errors indicating that we failed to set ourselves as the owner of the secret
+		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+		if errors.Is(err, ErrSecretSetCtrlRef) {
+			r.markAsFailed(msgErrorBecomeOwner, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, nil
+		}
+
+		// detect errors indicating that the secret has another ExternalSecret as owner
+		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+		if errors.Is(err, ErrSecretIsOwned) {
+			r.markAsFailed(msgErrorIsOwned, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, nil
+		}
+
+		// detect errors indicating that the secret is immutable
+		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+		if errors.Is(err, ErrSecretImmutable) {
+			r.markAsFailed(msgErrorUpdateImmutable, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, nil
+		}
+
+		r.markAsFailed(msgErrorUpdateSecret, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+
+	// record the rendered data in the history of the target secret.
+	err = r.updateHistory(ctx, externalSecret, renderedData, pinnedRevision)
+	if err != nil {
+		r.markAsFailed(msgErrorUpdateHistory, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+	if pinnedRevision > 0 {
+		r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonRolledBack, eventRolledBack, pinnedRevision)
+	}
+
+	// with ErrorPolicy=Partial, the keys that could not be fetched are reported.
+	if failed := failedKeys(externalSecret); externalSecret.Spec.ErrorPolicy == esv1.ErrorPolicyPartial && len(failed) > 0 {
+		r.recorder.Eventf(externalSecret, v1.EventTypeWarning, esv1.ReasonPartiallySynced, eventPartiallySynced, strings.Join(failed, ", "))
+		r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretPartiallySynced, fmt.Sprintf(msgPartiallySynced, len(failed), strings.Join(failed, ", ")))
+		return r.getRequeueResult(externalSecret), nil
+	}
+
+	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
+	return r.getRequeueResult(externalSecret), nil
+}
+
+// secretMutationFunc returns a function which can be applied to a secret to make it match the desired state.
+func (r *Reconciler) secretMutationFunc(ctx context.Context, externalSecret *esv1.ExternalSecret, dataMap map[string][]byte) func(secret *v1.Secret) error {
+	return func(secret *v1.Secret) error {
 		// get information about the current owner of the secret
 		//  - we ignore the API version as it can change over time
 		//  - we ignore the UID for consistency with the SetControllerReference function
@@ -471,7 +652,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 
 		// if the CreationPolicy is Owner, we should set ourselves as the owner of the secret
 		if externalSecret.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner {
-			err = controllerutil.SetControllerReference(externalSecret, secret, r.Scheme)
+			err := controllerutil.SetControllerReference(externalSecret, secret, r.Scheme)
 			if err != nil {
 				return fmt.Errorf("%w: %w", ErrSecretSetCtrlRef, err)
 			}
@@ -480,7 +661,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		// if the creation policy is not Owner, we should remove ourselves as the owner
 		// this could happen if the creation policy was changed after the secret was created
 		if externalSecret.Spec.Target.CreationPolicy != esv1.CreatePolicyOwner && ownerIsCurrentES {
-			err = controllerutil.RemoveControllerReference(externalSecret, secret, r.Scheme)
+			err := controllerutil.RemoveControllerReference(externalSecret, secret, r.Scheme)
 			if err != nil {
 				return fmt.Errorf("%w: %w", ErrSecretRemoveCtrlRef, err)
 			}
@@ -541,80 +722,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 
 		return nil
 	}
-
-	switch externalSecret.Spec.Target.CreationPolicy {
-	case esv1.CreatePolicyNone:
-		log.V(1).Info("secret creation skipped due to CreationPolicy=None")
-		err = nil
-	case esv1.CreatePolicyMerge:
-		// update the secret, if it exists
-		if existingSecret.UID != "" {
-			err = r.updateSecret(ctx, existingSecret, mutationFunc, externalSecret, secretName)
-		} else {
-			// if the secret does not exist, we wait until the next refresh interval
-			// rather than returning an error which would requeue immediately
-			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretMissing, msgMissing)
-			return r.getRequeueResult(externalSecret), nil
-		}
-	case esv1.CreatePolicyOrphan:
-		// create the secret, if it does not exist
-		if existingSecret.UID == "" {
-			err = r.createSecret(ctx, mutationFunc, externalSecret, secretName)
-		} else {
-			// if the secret exists, we should update it
-			err = r.updateSecret(ctx, existingSecret, mutationFunc, externalSecret, secretName)
-		}
-	case esv1.CreatePolicyOwner:
-		// we may have orphaned secrets to clean up,
-		// for example, if the target secret name was changed
-		err = r.deleteOrphanedSecrets(ctx, externalSecret, secretName)
-		if err != nil {
-			r.markAsFailed(msgErrorDeleteOrphaned, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-			return ctrl.Result{}, err
-		}
-
-		// create the secret, if it does not exist
-		if existingSecret.UID == "" {
-			err = r.createSecret(ctx, mutationFunc, externalSecret, secretName)
-		} else {
-			// if the secret exists, we should update it
-			err = r.updateSecret(ctx, existingSecret, mutationFunc, externalSecret, secretName)
-		}
-	}
-	if err != nil {
-		// if we got an update conflict, we should requeue immediately
-		if apierrors.IsConflict(err) {
-			log.V(1).Info("conflict while updating secret, will requeue")
-			return ctrl.Result{Requeue: true}, nil
-		}
-
-		// detect errors indicating that we failed to set ourselves as the owner of the secret
-		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
-		if errors.Is(err, ErrSecretSetCtrlRef) {
-			r.markAsFailed(msgErrorBecomeOwner, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-			return ctrl.Result{}, nil
-		}
-
-		// detect errors indicating that the secret has another ExternalSecret as owner
-		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
-		if errors.Is(err, ErrSecretIsOwned) {
-			r.markAsFailed(msgErrorIsOwned, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-			return ctrl.Result{}, nil
-		}
-
-		// detect errors indicating that the secret is immutable
-		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
-		if errors.Is(err, ErrSecretImmutable) {
-			r.markAsFailed(msgErrorUpdateImmutable, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-			return ctrl.Result{}, nil
-		}
-
-		r.markAsFailed(msgErrorUpdateSecret, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-		return ctrl.Result{}, err
-	}
-
-	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
-	return r.getRequeueResult(externalSecret), nil
 }
 
 // reconcileGenericTarget handles reconciliation for generic targets (ConfigMaps, Custom Resources).
@@ -644,7 +751,10 @@ func (r *Reconciler) reconcileGenericTarget(
 		return ctrl.Result{}, err
 	}
 
-	if !shouldRefresh(externalSecret) && valid {
+	// in dry-run mode, the target is rendered but never written
+	dryRun := isDryRun(externalSecret)
+
+	if !shouldRefresh(externalSecret) && ((valid && r.additionalTargetsValid(ctx, log, externalSecret)) || dryRun) {
 		log.V(1).Info("skipping refresh of generic target")
 		return r.getRequeueResult(externalSecret), nil
 	}
@@ -655,6 +765,39 @@ func (r *Reconciler) reconcileGenericTarget(
 		return ctrl.Result{}, err
 	}
 
+	if !dryRun {
+		// the preview is only kept while in dry-run mode.
+		externalSecret.Status.Preview = nil
+	} else if len(dataMap) == 0 && externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyMerge {
+		// the resource would either be deleted or retained.
+		var rendered *unstructured.Unstructured
+		if externalSecret.Spec.Target.DeletionPolicy == esv1.DeletionPolicyRetain {
+			rendered = existing
+		}
+		err = previewGenericTarget(externalSecret, existing, rendered)
+		if err == nil {
+			err = r.previewAdditionalTargets(ctx, log, externalSecret, dataMap)
+		}
+		if err != nil {
+			r.markAsFailed(msgErrorPreview, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonResourceSyncedError)
+			return ctrl.Result{}, err
+		}
+		r.markAsDone(externalSecret, start, log, esv1.ConditionReasonResourcePreviewed, msgPreviewed)
+		return r.getRequeueResult(externalSecret), nil
+	}
+
+	if !dryRun {
+		// the additional targets are rendered from the same provider data.
+		if err := r.reconcileAdditionalTargets(ctx, log, externalSecret, dataMap); err != nil {
+			if apierrors.IsConflict(err) {
+				log.V(1).Info("conflict while updating targets, will requeue")
+				return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+			}
+			r.markAsFailed(msgErrorUpdateTargets, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonResourceSyncedError)
+			return ctrl.Result{}, err
+		}
+	}
+
 	if len(dataMap) == 0 {
 		switch externalSecret.Spec.Target.DeletionPolicy {
 		case esv1.DeletionPolicyDelete:
@@ -696,6 +839,24 @@ func (r *Reconciler) reconcileGenericTarget(
 		return ctrl.Result{}, err
 	}
 
+	if dryRun {
+		// the resource is only written if the creation policy allows it.
+		if externalSecret.Spec.Target.CreationPolicy == esv1.CreatePolicyNone ||
+			(externalSecret.Spec.Target.CreationPolicy == esv1.CreatePolicyMerge && existing == nil) {
+			obj = existing
+		}
+		err = previewGenericTarget(externalSecret, existing, obj)
+		if err == nil {
+			err = r.previewAdditionalTargets(ctx, log, externalSecret, dataMap)
+		}
+		if err != nil {
+			r.markAsFailed(msgErrorPreview, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonResourceSyncedError)
+			return ctrl.Result{}, err
+		}
+		r.markAsDone(externalSecret, start, log, esv1.ConditionReasonResourcePreviewed, msgPreviewed)
+		return r.getRequeueResult(externalSecret), nil
+	}
+
 	// handle creation policies
 	switch externalSecret.Spec.Target.CreationPolicy {
 	case esv1.CreatePolicyNone:
@@ -764,11 +925,15 @@ func (r *Reconciler) getRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.
 		return ctrl.Result{}
 	}
 
+	// spread the refreshes across the interval, the delay only defers the requeue,
+	// so shouldRefresh still considers the ExternalSecret due once the interval elapsed.
+	jitter := r.refreshJitter(externalSecret, refreshInterval)
+
 	// if the last refresh time is not set, requeue after the refresh interval
 	// note, this should not happen, as we only call this function on ExternalSecrets
 	// that have been reconciled at least once
 	if externalSecret.Status.RefreshTime.IsZero() {
-		return ctrl.Result{RequeueAfter: refreshInterval}
+		return scheduleRefresh(externalSecret, refreshInterval+jitter, jitter)
 	}
 
 	timeSinceLastRefresh := time.Since(externalSecret.Status.RefreshTime.Time)
@@ -782,19 +947,94 @@ func (r *Reconciler) getRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.
 
 	// if there is time remaining, requeue after the remaining time
 	if timeSinceLastRefresh < refreshInterval {
-		return ctrl.Result{RequeueAfter: refreshInterval - timeSinceLastRefresh}
+		return scheduleRefresh(externalSecret, refreshInterval-timeSinceLastRefresh+jitter, jitter)
 	}
 
 	// otherwise, requeue immediately
 	return ctrl.Result{Requeue: true}
 }
 
+// scheduleRefresh requeues the ExternalSecret for its next refresh.
+// The refresh is brought forward if generated values are due for renewal before.
+func scheduleRefresh(externalSecret *esv1.ExternalSecret, requeueAfter, jitter time.Duration) ctrl.Result {
+	if renewal := externalSecret.Status.GeneratorRenewalTime; renewal != nil {
+		if untilRenewal := time.Until(renewal.Time); untilRenewal < requeueAfter {
+			if untilRenewal <= 0 {
+				return ctrl.Result{Requeue: true}
+			}
+			requeueAfter, jitter = untilRenewal, 0
+		}
+	}
+	recordSchedule(externalSecret, time.Now().Add(requeueAfter), jitter)
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
+
+// generatorRenewalTime returns the time at which values generated at the given time are regenerated,
+// after GeneratorRenewalPercent of their remaining lifetime has passed.
+// It returns the zero time if the values do not expire or the renewal is disabled.
+func (r *Reconciler) generatorRenewalTime(generatedAt, expiresAt time.Time) time.Time {
+	if expiresAt.IsZero() || r.GeneratorRenewalPercent <= 0 {
+		return time.Time{}
+	}
+	lifetime := max(expiresAt.Sub(generatedAt), 0)
+	return generatedAt.Add(lifetime * time.Duration(min(r.GeneratorRenewalPercent, 100)) / 100)
+}
+
+// earliest returns the earlier of two times, ignoring zero times.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// refreshJitter returns the delay of the next refresh of an ExternalSecret.
+// With RefreshJitterModeHash, the delay is derived from the UID of the ExternalSecret,
+// so every ExternalSecret keeps its slot within the interval.
+// With RefreshJitterModeRandom, the delay is recorded in the status and reused until the next refresh.
+func (r *Reconciler) refreshJitter(externalSecret *esv1.ExternalSecret, refreshInterval time.Duration) time.Duration {
+	jitter := r.RefreshJitter
+	if externalSecret.Spec.RefreshJitter != nil {
+		jitter = *externalSecret.Spec.RefreshJitter
+	}
+	window := refreshInterval * time.Duration(min(max(jitter.Percent, 0), 100)) / 100
+	if window <= 0 {
+		return 0
+	}
+	if jitter.Mode == esv1.RefreshJitterModeHash {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(externalSecret.UID))
+		_, _ = h.Write([]byte(externalSecret.Namespace + "/" + externalSecret.Name))
+		return time.Duration(h.Sum64() % uint64(window))
+	}
+	// the recorded delay is rolled again if it no longer fits the window, e.g. after the interval was shortened.
+	if recorded := externalSecret.Status.RefreshJitter; recorded != nil && recorded.Duration >= 0 && recorded.Duration < window {
+		return recorded.Duration
+	}
+	delay := rand.N(window)
+	externalSecret.Status.RefreshJitter = &metav1.Duration{Duration: delay}
+	return delay
+}
+
+// recordSchedule records the next scheduled refresh of an ExternalSecret in the metrics.
+func recordSchedule(externalSecret *esv1.ExternalSecret, next time.Time, jitter time.Duration) {
+	resourceLabels := ctrlmetrics.RefineNonConditionMetricLabels(map[string]string{"name": externalSecret.Name, "namespace": externalSecret.Namespace})
+	if gauge := esmetrics.GetGaugeVec(esmetrics.ExternalSecretNextRefreshKey); gauge != nil {
+		gauge.With(resourceLabels).Set(float64(next.Unix()))
+	}
+	if gauge := esmetrics.GetGaugeVec(esmetrics.ExternalSecretRefreshJitterKey); gauge != nil {
+		gauge.With(resourceLabels).Set(jitter.Seconds())
+	}
+}
+
 func (r *Reconciler) markAsDone(externalSecret *esv1.ExternalSecret, start time.Time, log logr.Logger, reason, msg string) {
 	oldReadyCondition := esv1.GetExternalSecretCondition(externalSecret.Status, esv1.ExternalSecretReady)
 	newReadyCondition := NewExternalSecretCondition(esv1.ExternalSecretReady, v1.ConditionTrue, reason, msg)
 	SetExternalSecretCondition(externalSecret, *newReadyCondition)
 
 	externalSecret.Status.RefreshTime = metav1.NewTime(start)
+	// a new delay is chosen for the next refresh.
+	externalSecret.Status.RefreshJitter = nil
 	externalSecret.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(externalSecret.ObjectMeta)
 
 	// if the status or reason has changed, log at the appropriate verbosity level
@@ -817,6 +1057,15 @@ func (r *Reconciler) markAsFailed(msg string, err error, externalSecret *esv1.Ex
 }
 
 func (r *Reconciler) cleanupManagedSecrets(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret) error {
+	for _, target := range allTargets(externalSecret) {
+		if err := r.cleanupManagedTarget(ctx, log, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) cleanupManagedTarget(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret) error {
 	// Only delete resources if DeletionPolicy is Delete
 	if externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyDelete {
 		log.V(1).Info("skipping resource deletion due to DeletionPolicy", "policy", externalSecret.Spec.Target.DeletionPolicy)
@@ -854,7 +1103,7 @@ func (r *Reconciler) cleanupManagedSecrets(ctx context.Context, log logr.Logger,
 	return nil
 }
 
-func (r *Reconciler) deleteOrphanedSecrets(ctx context.Context, externalSecret *esv1.ExternalSecret, secretName string) error {
+func (r *Reconciler) deleteOrphanedSecrets(ctx context.Context, externalSecret *esv1.ExternalSecret, secretNames ...string) error {
 	ownerLabel := esutils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name))
 
 	// we use a PartialObjectMetadataList to avoid loading the full secret objects
@@ -871,9 +1120,9 @@ func (r *Reconciler) deleteOrphanedSecrets(ctx context.Context, externalSecret *
 		return err
 	}
 
-	// delete all secrets that are not the target secret
+	// delete all secrets that are not a target secret
 	for _, secretPartial := range secretListPartial.Items {
-		if secretPartial.GetName() != secretName {
+		if !slices.Contains(secretNames, secretPartial.GetName()) {
 			err := r.Delete(ctx, &secretPartial)
 			if err != nil && !apierrors.IsNotFound(err) {
 				return err
@@ -1159,6 +1408,11 @@ func shouldRefreshPeriodic(es *esv1.ExternalSecret) bool {
 		return true
 	}
 
+	// if generated values are due for renewal, we should refresh
+	if es.Status.GeneratorRenewalTime != nil && !es.Status.GeneratorRenewalTime.After(time.Now()) {
+		return true
+	}
+
 	// if the last refresh time + refresh interval is before now, we should refresh
 	return es.Status.RefreshTime.Add(es.Spec.RefreshInterval.Duration).Before(time.Now())
 }
@@ -1242,16 +1496,9 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 	// this lets us quickly find all ExternalSecrets which target a specific Secret
 	if err := mgr.GetFieldIndexer().IndexField(ctx, &esv1.ExternalSecret{}, indexESTargetSecretNameField, func(obj client.Object) []string {
 		es := obj.(*esv1.ExternalSecret)
-		// Don't index generic targets here (they use indexESTargetResourceField)
-		if isGenericTarget(es) {
-			return nil
-		}
-		// if the target name is set, use that as the index
-		if es.Spec.Target.Name != "" {
-			return []string{es.Spec.Target.Name}
-		}
-		// otherwise, use the ExternalSecret name
-		return []string{es.Name}
+		// Don't index generic targets here (they use indexESTargetResourceField),
+		// the target name defaults to the ExternalSecret name
+		return targetSecretNames(es)
 	}); err != nil {
 		return err
 	}
@@ -1260,18 +1507,25 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 	// this lets us quickly find all ExternalSecrets which target a specific generic resource
 	if err := mgr.GetFieldIndexer().IndexField(ctx, &esv1.ExternalSecret{}, indexESTargetResourceField, func(obj client.Object) []string {
 		es := obj.(*esv1.ExternalSecret)
-		if !r.AllowGenericTargets || !isGenericTarget(es) {
+		if !r.AllowGenericTargets {
 			return nil
 		}
 
-		gvk := getTargetGVK(es)
-		targetName := getTargetName(es)
 		// Index format: "group/version/kind/name"
-		return []string{fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Version, gvk.Kind, targetName)}
+		return targetResourceKeys(es)
 	}); err != nil {
 		return err
 	}
 
+	// index ExternalSecrets based on the stores and remote keys they read,
+	// this lets other controllers and the refresh receiver find the ExternalSecrets affected by a change of a remote key.
+	if r.RemoteRefIndex == nil {
+		r.RemoteRefIndex = esindex.New()
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &esv1.ExternalSecret{}, esindex.IndexStoreKeyField, esindex.IndexStoreKeys); err != nil {
+		return err
+	}
+
 	// predicate function to ignore secret events unless they have the "managed" label
 	secretHasESLabel := predicate.NewPredicateFuncs(func(object client.Object) bool {
 		value, hasLabel := object.GetLabels()[esv1.LabelManaged]
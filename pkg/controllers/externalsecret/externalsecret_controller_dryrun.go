@@ -0,0 +1,332 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	// condition messages for "SecretPreviewed" and "ResourcePreviewed" reason.
+	msgPreviewed = "target rendered in dry-run mode, changes were not applied"
+
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorPreview = "could not render target in dry-run mode"
+)
+
+// isDryRun returns true if the ExternalSecret should render its target without writing it.
+func isDryRun(es *esv1.ExternalSecret) bool {
+	return es.Spec.DryRun || es.Annotations[esv1.AnnotationDryRun] == "true"
+}
+
+// reconcileDryRun fetches the provider data and renders the target secret,
+// but never creates, updates or deletes it.
+// The difference between the rendered and the existing secret is recorded in the status.
+func (r *Reconciler) reconcileDryRun(
+	ctx context.Context,
+	externalSecret *esv1.ExternalSecret,
+	log logr.Logger,
+	start time.Time,
+	resourceLabels map[string]string,
+	syncCallsError *prometheus.CounterVec,
+) (result ctrl.Result, err error) {
+	if !shouldRefresh(externalSecret) {
+		log.V(1).Info("skipping refresh of dry-run")
+		return r.getRequeueResult(externalSecret), nil
+	}
+
+	// update the status of the ExternalSecret when this function returns, if needed
+	currentStatus := *externalSecret.Status.DeepCopy()
+	defer func() {
+		if equality.Semantic.DeepEqual(currentStatus, externalSecret.Status) {
+			return
+		}
+
+		updateErr := r.Status().Update(ctx, externalSecret)
+		if updateErr != nil && !apierrors.IsConflict(updateErr) {
+			log.Error(updateErr, logErrorUpdateESStatus)
+		}
+	}()
+
+	secretName := externalSecret.Spec.Target.Name
+	if secretName == "" {
+		secretName = externalSecret.Name
+	}
+
+	// the target may not be managed by us yet, so it might be missing from the managed secrets cache.
+	// we read it from the API server, as we must not label it in dry-run mode.
+	secretReader := r.APIReader
+	if secretReader == nil {
+		secretReader = r.SecretClient
+	}
+	existingSecret := &v1.Secret{}
+	err = secretReader.Get(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, existingSecret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+		syncCallsError.With(resourceLabels).Inc()
+		return ctrl.Result{}, err
+	}
+
+	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
+	if err != nil {
+		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+
+	err = r.previewSecret(ctx, externalSecret, existingSecret, secretName, dataMap)
+	if err == nil {
+		err = r.previewAdditionalTargets(ctx, log, externalSecret, dataMap)
+	}
+	if err != nil {
+		r.markAsFailed(msgErrorPreview, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+
+	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretPreviewed, msgPreviewed)
+	return r.getRequeueResult(externalSecret), nil
+}
+
+// previewSecret renders the target secret the same way the creation and deletion policies would,
+// and records the difference to the existing secret in the status.
+func (r *Reconciler) previewSecret(ctx context.Context, externalSecret *esv1.ExternalSecret, existingSecret *v1.Secret, secretName string, dataMap map[string][]byte) error {
+	exists := existingSecret.UID != ""
+	var current map[string][]byte
+	if exists {
+		current = existingSecret.Data
+	}
+
+	target := externalSecret.Spec.Target
+	switch {
+	// the secret would be deleted.
+	case len(dataMap) == 0 && target.DeletionPolicy == esv1.DeletionPolicyDelete:
+		externalSecret.Status.Preview = newPreview(current, exists, nil, false)
+		return nil
+	// the secret would be kept as-is.
+	case len(dataMap) == 0 && target.DeletionPolicy == esv1.DeletionPolicyRetain,
+		target.CreationPolicy == esv1.CreatePolicyNone,
+		target.CreationPolicy == esv1.CreatePolicyMerge && !exists:
+		externalSecret.Status.Preview = newPreview(current, exists, current, exists)
+		return nil
+	}
+
+	rendered := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secretName,
+			Namespace:   externalSecret.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Data: make(map[string][]byte),
+	}
+	if exists {
+		rendered = existingSecret.DeepCopy()
+	}
+	if err := r.secretMutationFunc(ctx, externalSecret, dataMap)(rendered); err != nil {
+		return fmt.Errorf(errMutate, secretName, err)
+	}
+
+	externalSecret.Status.Preview = newPreview(current, exists, rendered.Data, true)
+	return nil
+}
+
+// previewAdditionalTargets renders every entry of spec.targets like reconcileAdditionalTargets would,
+// and records their differences next to the preview of the primary target.
+func (r *Reconciler) previewAdditionalTargets(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, dataMap map[string][]byte) error {
+	if externalSecret.Status.Preview == nil {
+		return nil
+	}
+
+	var previews []esv1.ExternalSecretTargetPreview
+	var errs []error
+	for _, target := range additionalTargets(externalSecret) {
+		var err error
+		if isGenericTarget(target) {
+			err = r.previewAdditionalResource(ctx, log, target, dataMap)
+		} else {
+			err = r.previewAdditionalSecret(ctx, target, dataMap)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errTarget, getTargetName(target), err))
+			continue
+		}
+		preview := target.Status.Preview
+		previews = append(previews, esv1.ExternalSecretTargetPreview{
+			Kind:         targetStatus(target).Kind,
+			Name:         getTargetName(target),
+			Added:        preview.Added,
+			Removed:      preview.Removed,
+			Changed:      preview.Changed,
+			RenderedHash: preview.RenderedHash,
+			TargetHash:   preview.TargetHash,
+		})
+	}
+	externalSecret.Status.Preview.Targets = previews
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) previewAdditionalSecret(ctx context.Context, target *esv1.ExternalSecret, dataMap map[string][]byte) error {
+	secretName := getTargetName(target)
+	secretReader := r.APIReader
+	if secretReader == nil {
+		secretReader = r.SecretClient
+	}
+	existingSecret := &v1.Secret{}
+	err := secretReader.Get(ctx, client.ObjectKey{Name: secretName, Namespace: target.Namespace}, existingSecret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return r.previewSecret(ctx, target, existingSecret, secretName, dataMap)
+}
+
+func (r *Reconciler) previewAdditionalResource(ctx context.Context, log logr.Logger, target *esv1.ExternalSecret, dataMap map[string][]byte) error {
+	if err := r.validateGenericTarget(log, target); err != nil {
+		return err
+	}
+
+	existing, err := r.getGenericResource(ctx, log, target)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// the resource would either be deleted or retained.
+	if len(dataMap) == 0 && target.Spec.Target.DeletionPolicy != esv1.DeletionPolicyMerge {
+		var rendered *unstructured.Unstructured
+		if target.Spec.Target.DeletionPolicy == esv1.DeletionPolicyRetain {
+			rendered = existing
+		}
+		return previewGenericTarget(target, existing, rendered)
+	}
+
+	var baseObj *unstructured.Unstructured
+	if target.Spec.Target.CreationPolicy == esv1.CreatePolicyMerge && existing != nil {
+		baseObj = existing
+	}
+	obj, err := r.applyTemplateToManifest(ctx, target, dataMap, baseObj)
+	if err != nil {
+		return err
+	}
+	// the resource is only written if the creation policy allows it.
+	if target.Spec.Target.CreationPolicy == esv1.CreatePolicyNone ||
+		(target.Spec.Target.CreationPolicy == esv1.CreatePolicyMerge && existing == nil) {
+		obj = existing
+	}
+	return previewGenericTarget(target, existing, obj)
+}
+
+// previewGenericTarget records the difference between the rendered and the existing generic target in the status.
+// Nested fields are compared by their path, e.g. `data.key` or `spec.template.name`.
+func previewGenericTarget(externalSecret *esv1.ExternalSecret, existing, rendered *unstructured.Unstructured) error {
+	var current, desired map[string][]byte
+	var err error
+	if existing != nil {
+		current, err = flattenManifest(existing.Object)
+		if err != nil {
+			return err
+		}
+	}
+	if rendered != nil {
+		desired, err = flattenManifest(rendered.Object)
+		if err != nil {
+			return err
+		}
+	}
+	externalSecret.Status.Preview = newPreview(current, existing != nil, desired, rendered != nil)
+	return nil
+}
+
+// newPreview compares the keys of the current and the rendered data,
+// only hashes of the data are recorded.
+func newPreview(current map[string][]byte, currentExists bool, rendered map[string][]byte, renderedExists bool) *esv1.ExternalSecretPreview {
+	preview := &esv1.ExternalSecretPreview{
+		RenderTime: metav1.Now(),
+	}
+	for _, key := range slices.Sorted(maps.Keys(rendered)) {
+		currentValue, ok := current[key]
+		switch {
+		case !ok:
+			preview.Added = append(preview.Added, key)
+		case !bytes.Equal(currentValue, rendered[key]):
+			preview.Changed = append(preview.Changed, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := rendered[key]; !ok {
+			preview.Removed = append(preview.Removed, key)
+		}
+	}
+	if currentExists {
+		preview.TargetHash = esutils.ObjectHash(current)
+	}
+	if renderedExists {
+		preview.RenderedHash = esutils.ObjectHash(rendered)
+	}
+	return preview
+}
+
+// flattenManifest returns the leaf values of a manifest by their path,
+// ignoring the type information, metadata and status of the object.
+func flattenManifest(obj map[string]any) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	for key, value := range obj {
+		switch key {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		if err := flattenValue(key, value, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func flattenValue(path string, value any, out map[string][]byte) error {
+	if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+		for key, v := range nested {
+			if err := flattenValue(strings.Join([]string{path, key}, "."), v, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("unable to encode field %s: %w", path, err)
+	}
+	out[path] = encoded
+	return nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+func TestIsDryRun(t *testing.T) {
+	es := &esv1.ExternalSecret{}
+	assert.False(t, isDryRun(es))
+
+	es.Spec.DryRun = true
+	assert.True(t, isDryRun(es))
+
+	es.Spec.DryRun = false
+	es.Annotations = map[string]string{esv1.AnnotationDryRun: "true"}
+	assert.True(t, isDryRun(es))
+}
+
+func TestNewPreview(t *testing.T) {
+	current := map[string][]byte{
+		"kept":    []byte("same"),
+		"changed": []byte("old"),
+		"removed": []byte("gone"),
+	}
+	rendered := map[string][]byte{
+		"kept":    []byte("same"),
+		"changed": []byte("new"),
+		"added":   []byte("fresh"),
+	}
+
+	preview := newPreview(current, true, rendered, true)
+	assert.Equal(t, []string{"added"}, preview.Added)
+	assert.Equal(t, []string{"changed"}, preview.Changed)
+	assert.Equal(t, []string{"removed"}, preview.Removed)
+	assert.Equal(t, esutils.ObjectHash(current), preview.TargetHash)
+	assert.Equal(t, esutils.ObjectHash(rendered), preview.RenderedHash)
+
+	// a deleted target removes all keys.
+	preview = newPreview(current, true, nil, false)
+	assert.Empty(t, preview.Added)
+	assert.Equal(t, []string{"changed", "kept", "removed"}, preview.Removed)
+	assert.Empty(t, preview.RenderedHash)
+
+	// a missing target adds all keys.
+	preview = newPreview(nil, false, rendered, true)
+	assert.Equal(t, []string{"added", "changed", "kept"}, preview.Added)
+	assert.Empty(t, preview.TargetHash)
+}
+
+func TestPreviewSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	r := &Reconciler{
+		Client: fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+		Log:    logr.Discard(),
+		Scheme: scheme,
+	}
+
+	existing := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "target",
+			Namespace: "default",
+			UID:       "1234",
+		},
+		Data: map[string][]byte{
+			"foo": []byte("old"),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		target       esv1.ExternalSecretTarget
+		existing     *v1.Secret
+		dataMap      map[string][]byte
+		wantAdded    []string
+		wantChanged  []string
+		wantRemoved  []string
+		wantRendered bool
+	}{
+		{
+			name:         "existing secret is updated",
+			target:       esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner},
+			existing:     existing,
+			dataMap:      map[string][]byte{"foo": []byte("new"), "bar": []byte("bar")},
+			wantAdded:    []string{"bar"},
+			wantChanged:  []string{"foo"},
+			wantRendered: true,
+		},
+		{
+			name:         "missing secret is created",
+			target:       esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner},
+			existing:     &v1.Secret{},
+			dataMap:      map[string][]byte{"foo": []byte("new")},
+			wantAdded:    []string{"foo"},
+			wantRendered: true,
+		},
+		{
+			name:        "secret is deleted without data",
+			target:      esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner, DeletionPolicy: esv1.DeletionPolicyDelete},
+			existing:    existing,
+			wantRemoved: []string{"foo"},
+		},
+		{
+			name:         "secret is retained without data",
+			target:       esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner, DeletionPolicy: esv1.DeletionPolicyRetain},
+			existing:     existing,
+			wantRendered: true,
+		},
+		{
+			name:     "missing secret is not created with CreationPolicy=Merge",
+			target:   esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyMerge},
+			existing: &v1.Secret{},
+			dataMap:  map[string][]byte{"foo": []byte("new")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+				Spec: esv1.ExternalSecretSpec{
+					Target: tt.target,
+					DryRun: true,
+				},
+			}
+			existingCopy := tt.existing.DeepCopy()
+
+			require.NoError(t, r.previewSecret(context.Background(), es, tt.existing, "target", tt.dataMap))
+			require.NotNil(t, es.Status.Preview)
+			assert.Equal(t, tt.wantAdded, es.Status.Preview.Added)
+			assert.Equal(t, tt.wantChanged, es.Status.Preview.Changed)
+			assert.Equal(t, tt.wantRemoved, es.Status.Preview.Removed)
+			assert.Equal(t, tt.wantRendered, es.Status.Preview.RenderedHash != "")
+			// the existing secret must never be mutated.
+			assert.Equal(t, existingCopy, tt.existing)
+		})
+	}
+}
+
+func TestPreviewAdditionalTargets(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "copy", Namespace: "default", UID: "1234"},
+		Data:       map[string][]byte{"foo": []byte("old")},
+	}).Build()
+	r := &Reconciler{
+		Client:       fakeClient,
+		SecretClient: fakeClient,
+		Log:          logr.Discard(),
+		Scheme:       scheme,
+	}
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			DryRun: true,
+			Targets: []esv1.ExternalSecretTarget{
+				{Name: "copy", CreationPolicy: esv1.CreatePolicyOwner},
+				{Name: "new", CreationPolicy: esv1.CreatePolicyOwner},
+			},
+		},
+		Status: esv1.ExternalSecretStatus{Preview: &esv1.ExternalSecretPreview{Added: []string{"foo"}}},
+	}
+
+	require.NoError(t, r.previewAdditionalTargets(ctx, logr.Discard(), es, map[string][]byte{"foo": []byte("new")}))
+	require.Len(t, es.Status.Preview.Targets, 2)
+	assert.Equal(t, "Secret", es.Status.Preview.Targets[0].Kind)
+	assert.Equal(t, "copy", es.Status.Preview.Targets[0].Name)
+	assert.Equal(t, []string{"foo"}, es.Status.Preview.Targets[0].Changed)
+	assert.NotEmpty(t, es.Status.Preview.Targets[0].TargetHash)
+	assert.Equal(t, "new", es.Status.Preview.Targets[1].Name)
+	assert.Equal(t, []string{"foo"}, es.Status.Preview.Targets[1].Added)
+	assert.Empty(t, es.Status.Preview.Targets[1].TargetHash)
+	// the preview of the primary target is kept.
+	assert.Equal(t, []string{"foo"}, es.Status.Preview.Added)
+
+	// the targets are never written.
+	var copied v1.Secret
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "copy", Namespace: "default"}, &copied))
+	assert.Equal(t, []byte("old"), copied.Data["foo"])
+	err := fakeClient.Get(ctx, client.ObjectKey{Name: "new", Namespace: "default"}, &v1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestPreviewGenericTarget(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "target"},
+		"data": map[string]any{
+			"foo": "old",
+			"baz": "same",
+		},
+	}}
+	rendered := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "target", "labels": map[string]any{"a": "b"}},
+		"data": map[string]any{
+			"foo": "new",
+			"baz": "same",
+			"bar": "added",
+		},
+	}}
+
+	es := &esv1.ExternalSecret{}
+	require.NoError(t, previewGenericTarget(es, existing, rendered))
+	assert.Equal(t, []string{"data.bar"}, es.Status.Preview.Added)
+	assert.Equal(t, []string{"data.foo"}, es.Status.Preview.Changed)
+	assert.Empty(t, es.Status.Preview.Removed)
+
+	require.NoError(t, previewGenericTarget(es, existing, nil))
+	assert.Equal(t, []string{"data.baz", "data.foo"}, es.Status.Preview.Removed)
+	assert.Empty(t, es.Status.Preview.RenderedHash)
+}
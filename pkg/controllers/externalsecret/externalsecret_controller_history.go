@@ -0,0 +1,378 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"cmp"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorRollback      = "could not roll back secret"
+	msgErrorUpdateHistory = "could not update secret history"
+
+	// event messages.
+	eventRolledBack = "secret pinned to revision %d"
+
+	// error formats.
+	errInvalidRollbackRevision = "invalid rollback revision %q: must be a positive integer"
+	errRevisionNotFound        = "revision %d not found in the history"
+	errHistoryPlaintext        = "no history encryption key is configured, set history.allowPlaintext to store plaintext revisions"
+	errHistoryKeyMismatch      = "revision %d is encrypted with another key"
+	errHistoryDecrypt          = "unable to decrypt revision %d: %w"
+
+	// historySecretNameMaxLength is the maximum length of a Secret name.
+	historySecretNameMaxLength = 253
+	// historyEncryptedDataKey is the key of the encrypted data in a history Secret.
+	historyEncryptedDataKey = "data"
+	// HistoryEncryptionKeySize is the size of the AES-256 key which encrypts the history.
+	HistoryEncryptionKeySize = 32
+)
+
+// getRollbackRevision returns the revision the target Secret is pinned to, or 0 if it is not pinned.
+func getRollbackRevision(es *esv1.ExternalSecret) (int64, error) {
+	value, ok := es.Annotations[esv1.AnnotationRollbackRevision]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || revision <= 0 {
+		return 0, fmt.Errorf(errInvalidRollbackRevision, value)
+	}
+	return revision, nil
+}
+
+// pinnedExternalSecret returns a copy of the ExternalSecret that applies the data of a revision as-is.
+// The data of a revision has already been rendered, so only the metadata of the template is applied.
+func pinnedExternalSecret(es *esv1.ExternalSecret) *esv1.ExternalSecret {
+	pinned := es.DeepCopy()
+	if pinned.Spec.Target.Template != nil {
+		pinned.Spec.Target.Template.Data = nil
+		pinned.Spec.Target.Template.TemplateFrom = nil
+	}
+	return pinned
+}
+
+// getRevisionData returns the rendered data of a revision of the target Secret.
+func (r *Reconciler) getRevisionData(ctx context.Context, es *esv1.ExternalSecret, revision int64) (map[string][]byte, error) {
+	revisions, err := r.listRevisions(ctx, es)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if historyRevision(&revisions[i]) != revision {
+			continue
+		}
+		var secret v1.Secret
+		if err := r.Get(ctx, client.ObjectKeyFromObject(&revisions[i]), &secret); err != nil {
+			return nil, err
+		}
+		keyID, encrypted := secret.Annotations[esv1.AnnotationHistoryEncryptionKey]
+		if !encrypted {
+			return secret.Data, nil
+		}
+		if len(r.HistoryEncryptionKey) == 0 || keyID != historyKeyID(r.HistoryEncryptionKey) {
+			return nil, fmt.Errorf(errHistoryKeyMismatch, revision)
+		}
+		data, err := decryptRevision(r.HistoryEncryptionKey, secret.Data[historyEncryptedDataKey])
+		if err != nil {
+			return nil, fmt.Errorf(errHistoryDecrypt, revision, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf(errRevisionNotFound, revision)
+}
+
+// updateHistory records the rendered data as a new revision, if it differs from the latest revision,
+// removes revisions exceeding the history limit and updates the status.
+// While the target is pinned, no new revision is recorded.
+// If the history is disabled, all revisions are removed.
+func (r *Reconciler) updateHistory(ctx context.Context, es *esv1.ExternalSecret, rendered map[string][]byte, pinnedRevision int64) error {
+	history := es.Spec.Target.History
+	if history == nil {
+		// only look for leftovers if the history was enabled before.
+		if es.Status.History == nil {
+			return nil
+		}
+		if err := r.deleteRevisions(ctx, es); err != nil {
+			return err
+		}
+		es.Status.History = nil
+		return nil
+	}
+
+	revisions, err := r.listRevisions(ctx, es)
+	if err != nil {
+		return err
+	}
+
+	var latest *metav1.PartialObjectMetadata
+	if len(revisions) > 0 {
+		latest = &revisions[len(revisions)-1]
+	}
+	currentRevision := pinnedRevision
+	if pinnedRevision == 0 && rendered != nil {
+		hash := esutils.ObjectHash(rendered)
+		if latest == nil || latest.Annotations[esv1.AnnotationDataHash] != hash {
+			latestRevision := int64(0)
+			if latest != nil {
+				latestRevision = historyRevision(latest)
+			}
+			secret, err := r.createRevision(ctx, es, latestRevision+1, rendered, hash)
+			if err != nil {
+				return err
+			}
+			revisions = append(revisions, *secret)
+		}
+		currentRevision = historyRevision(&revisions[len(revisions)-1])
+	}
+
+	// remove the oldest revisions exceeding the limit, but never the pinned one.
+	limit := max(int(history.Limit), 1)
+	for len(revisions) > limit {
+		idx := 0
+		if historyRevision(&revisions[idx]) == pinnedRevision {
+			idx = 1
+		}
+		if err := r.Delete(ctx, &revisions[idx]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		revisions = slices.Delete(revisions, idx, idx+1)
+	}
+
+	status := &esv1.ExternalSecretHistoryStatus{
+		CurrentRevision: currentRevision,
+		PinnedRevision:  pinnedRevision,
+	}
+	for i := range revisions {
+		status.Revisions = append(status.Revisions, esv1.ExternalSecretRevision{
+			Revision:          historyRevision(&revisions[i]),
+			SecretName:        revisions[i].Name,
+			DataHash:          revisions[i].Annotations[esv1.AnnotationDataHash],
+			CreationTimestamp: revisions[i].CreationTimestamp,
+		})
+	}
+	es.Status.History = status
+	return nil
+}
+
+// createRevision stores the rendered data as a new revision.
+// The data is encrypted if the controller has a history encryption key, plaintext revisions require an explicit opt-in.
+// The revisions are listed from the cache, which may lag behind. If the revision already exists, it is
+// taken as is if it holds the same data, or the next revision is created otherwise.
+// If the name of the revision is taken by an unrelated Secret, a name is generated instead.
+func (r *Reconciler) createRevision(ctx context.Context, es *esv1.ExternalSecret, revision int64, data map[string][]byte, hash string) (*metav1.PartialObjectMetadata, error) {
+	name := historySecretName(es.Name, revision)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: es.Namespace,
+			Labels: map[string]string{
+				esv1.LabelHistoryOf:       historyOwnerValue(es),
+				esv1.LabelHistoryRevision: strconv.FormatInt(revision, 10),
+			},
+			Annotations: map[string]string{
+				esv1.AnnotationDataHash: hash,
+			},
+		},
+		Immutable: new(true),
+		Type:      v1.SecretTypeOpaque,
+		Data:      data,
+	}
+	switch {
+	case len(r.HistoryEncryptionKey) > 0:
+		encrypted, err := encryptRevision(r.HistoryEncryptionKey, data)
+		if err != nil {
+			return nil, err
+		}
+		secret.Annotations[esv1.AnnotationHistoryEncryptionKey] = historyKeyID(r.HistoryEncryptionKey)
+		secret.Data = map[string][]byte{historyEncryptedDataKey: encrypted}
+	case !es.Spec.Target.History.AllowPlaintext:
+		return nil, errors.New(errHistoryPlaintext)
+	}
+	// the history is garbage collected together with the ExternalSecret.
+	if err := controllerutil.SetOwnerReference(es, secret, r.Scheme); err != nil {
+		return nil, err
+	}
+	err := r.Create(ctx, secret, client.FieldOwner(fqdnFor(es.Name)))
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := r.getRevisionMetadata(ctx, es.Namespace, name)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if ownsRevision(es, existing) && historyRevision(existing) == revision {
+			if existing.Annotations[esv1.AnnotationDataHash] == hash {
+				return existing, nil
+			}
+			return r.createRevision(ctx, es, revision+1, data, hash)
+		}
+		secret.Name = ""
+		secret.GenerateName = name + "-"
+		err = r.Create(ctx, secret, client.FieldOwner(fqdnFor(es.Name)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}, ObjectMeta: secret.ObjectMeta}, nil
+}
+
+// getRevisionMetadata reads the metadata of a Secret from the API server, bypassing the cache.
+func (r *Reconciler) getRevisionMetadata(ctx context.Context, namespace, name string) (*metav1.PartialObjectMetadata, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Secret"))
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// ownsRevision returns true if the Secret is a revision of the history of the ExternalSecret.
+func ownsRevision(es *esv1.ExternalSecret, secret client.Object) bool {
+	if secret.GetLabels()[esv1.LabelHistoryOf] != historyOwnerValue(es) {
+		return false
+	}
+	return slices.ContainsFunc(secret.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.UID == es.UID
+	})
+}
+
+// listRevisions returns the metadata of the history Secrets of the ExternalSecret, oldest first.
+// The metadata of all Secrets is cached due to WatchesMetadata() in SetupWithManager(),
+// so the history is read from the cache without loading the data of the revisions.
+func (r *Reconciler) listRevisions(ctx context.Context, es *esv1.ExternalSecret) ([]metav1.PartialObjectMetadata, error) {
+	secrets := &metav1.PartialObjectMetadataList{}
+	secrets.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("SecretList"))
+	err := r.List(ctx, secrets, client.InNamespace(es.Namespace), client.MatchingLabels{
+		esv1.LabelHistoryOf: historyOwnerValue(es),
+	})
+	if err != nil {
+		return nil, err
+	}
+	revisions := secrets.Items
+	slices.SortFunc(revisions, func(a, b metav1.PartialObjectMetadata) int {
+		return cmp.Compare(historyRevision(&a), historyRevision(&b))
+	})
+	return revisions, nil
+}
+
+// deleteRevisions removes all history Secrets of the ExternalSecret.
+func (r *Reconciler) deleteRevisions(ctx context.Context, es *esv1.ExternalSecret) error {
+	revisions, err := r.listRevisions(ctx, es)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range revisions {
+		if err := r.Delete(ctx, &revisions[i]); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func historyRevision(secret client.Object) int64 {
+	revision, _ := strconv.ParseInt(secret.GetLabels()[esv1.LabelHistoryRevision], 10, 64)
+	return revision
+}
+
+// historyKeyID identifies the history encryption key, without revealing it.
+func historyKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// encryptRevision encrypts the data of a revision with AES-256-GCM, the nonce is prepended to the ciphertext.
+func encryptRevision(key []byte, data map[string][]byte) ([]byte, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newHistoryAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptRevision(key, ciphertext []byte) (map[string][]byte, error) {
+	aead, err := newHistoryAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string][]byte
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func newHistoryAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func historyOwnerValue(es *esv1.ExternalSecret) string {
+	return esutils.ObjectHash(fmt.Sprintf("%v/%v", es.Namespace, es.Name))
+}
+
+func historySecretName(esName string, revision int64) string {
+	suffix := fmt.Sprintf("-history-%d", revision)
+	if len(esName)+len(suffix) > historySecretNameMaxLength {
+		esName = strings.TrimRight(esName[:historySecretNameMaxLength-len(suffix)], ".-")
+	}
+	return esName + suffix
+}
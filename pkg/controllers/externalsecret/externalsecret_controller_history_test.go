@@ -0,0 +1,225 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestGetRollbackRevision(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   *string
+		want    int64
+		wantErr bool
+	}{
+		{name: "not pinned"},
+		{name: "empty value", value: new("")},
+		{name: "pinned", value: new("3"), want: 3},
+		{name: "negative revision", value: new("-1"), wantErr: true},
+		{name: "invalid revision", value: new("latest"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1.ExternalSecret{}
+			if tt.value != nil {
+				es.Annotations = map[string]string{esv1.AnnotationRollbackRevision: *tt.value}
+			}
+			got, err := getRollbackRevision(es)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistorySecretName(t *testing.T) {
+	assert.Equal(t, "my-es-history-2", historySecretName("my-es", 2))
+
+	name := historySecretName(strings.Repeat("a", 253), 10)
+	assert.Len(t, name, 253)
+	assert.True(t, strings.HasSuffix(name, "-history-10"))
+}
+
+func TestUpdateHistory(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	r := &Reconciler{
+		Client: fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+		Log:    logr.Discard(),
+		Scheme: scheme,
+	}
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "1234"},
+		Spec: esv1.ExternalSecretSpec{
+			Target: esv1.ExternalSecretTarget{
+				History: &esv1.ExternalSecretHistory{Limit: 2, AllowPlaintext: true},
+			},
+		},
+	}
+
+	// every distinct rendered version is recorded once.
+	require.NoError(t, r.updateHistory(ctx, es, map[string][]byte{"key": []byte("v1")}, 0))
+	require.NoError(t, r.updateHistory(ctx, es, map[string][]byte{"key": []byte("v1")}, 0))
+	require.NoError(t, r.updateHistory(ctx, es, map[string][]byte{"key": []byte("v2")}, 0))
+	require.NotNil(t, es.Status.History)
+	assert.Equal(t, int64(2), es.Status.History.CurrentRevision)
+	require.Len(t, es.Status.History.Revisions, 2)
+	assert.Equal(t, "es-history-1", es.Status.History.Revisions[0].SecretName)
+
+	// the oldest revision is removed once the limit is exceeded.
+	require.NoError(t, r.updateHistory(ctx, es, map[string][]byte{"key": []byte("v3")}, 0))
+	revisions, err := r.listRevisions(ctx, es)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, int64(2), historyRevision(&revisions[0]))
+	assert.Equal(t, int64(3), historyRevision(&revisions[1]))
+	assert.Equal(t, es.UID, revisions[0].OwnerReferences[0].UID)
+	var revision v1.Secret
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(&revisions[0]), &revision))
+	assert.True(t, *revision.Immutable)
+
+	// pinned revisions can be read back and no new revision is recorded.
+	data, err := r.getRevisionData(ctx, es, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"key": []byte("v2")}, data)
+	require.NoError(t, r.updateHistory(ctx, es, data, 2))
+	assert.Equal(t, int64(2), es.Status.History.CurrentRevision)
+	assert.Equal(t, int64(2), es.Status.History.PinnedRevision)
+	assert.Len(t, es.Status.History.Revisions, 2)
+
+	_, err = r.getRevisionData(ctx, es, 1)
+	assert.ErrorContains(t, err, "revision 1 not found")
+
+	// disabling the history removes all revisions.
+	es.Spec.Target.History = nil
+	require.NoError(t, r.updateHistory(ctx, es, data, 0))
+	assert.Nil(t, es.Status.History)
+	var secrets v1.SecretList
+	require.NoError(t, r.List(ctx, &secrets))
+	assert.Empty(t, secrets.Items)
+}
+
+func TestCreateRevisionConflict(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	unrelated := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "es-history-1", Namespace: "default"}}
+	r := &Reconciler{
+		Client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(unrelated).Build(),
+		Log:    logr.Discard(),
+		Scheme: scheme,
+	}
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "1234"},
+		Spec: esv1.ExternalSecretSpec{
+			Target: esv1.ExternalSecretTarget{
+				History: &esv1.ExternalSecretHistory{Limit: 5, AllowPlaintext: true},
+			},
+		},
+	}
+
+	// an unrelated Secret with the name of the revision does not fail the sync.
+	require.NoError(t, r.updateHistory(ctx, es, map[string][]byte{"key": []byte("v1")}, 0))
+	require.Len(t, es.Status.History.Revisions, 1)
+	assert.Equal(t, int64(1), es.Status.History.Revisions[0].Revision)
+	assert.True(t, strings.HasPrefix(es.Status.History.Revisions[0].SecretName, "es-history-1-"))
+
+	// a revision missing from a lagging cache is taken as is if it holds the same data.
+	v2 := map[string][]byte{"key": []byte("v2")}
+	created, err := r.createRevision(ctx, es, 2, v2, "hash-v2")
+	require.NoError(t, err)
+	existing, err := r.createRevision(ctx, es, 2, v2, "hash-v2")
+	require.NoError(t, err)
+	assert.Equal(t, created.Name, existing.Name)
+
+	// and the next revision is created if it holds other data.
+	next, err := r.createRevision(ctx, es, 2, map[string][]byte{"key": []byte("v3")}, "hash-v3")
+	require.NoError(t, err)
+	assert.Equal(t, "es-history-3", next.Name)
+	assert.Equal(t, int64(3), historyRevision(next))
+
+	revisions, err := r.listRevisions(ctx, es)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 3)
+}
+
+func TestUpdateHistoryEncryption(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+	key := []byte(strings.Repeat("k", HistoryEncryptionKeySize))
+	r := &Reconciler{
+		Client:               kube,
+		Log:                  logr.Discard(),
+		Scheme:               scheme,
+		HistoryEncryptionKey: key,
+	}
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "1234"},
+		Spec: esv1.ExternalSecretSpec{
+			Target: esv1.ExternalSecretTarget{
+				History: &esv1.ExternalSecretHistory{Limit: 2},
+			},
+		},
+	}
+	data := map[string][]byte{"key": []byte("s3cr3t")}
+
+	// the revision does not hold the plaintext data.
+	require.NoError(t, r.updateHistory(ctx, es, data, 0))
+	var revision v1.Secret
+	require.NoError(t, kube.Get(ctx, client.ObjectKey{Namespace: "default", Name: "es-history-1"}, &revision))
+	assert.Equal(t, historyKeyID(key), revision.Annotations[esv1.AnnotationHistoryEncryptionKey])
+	require.Len(t, revision.Data, 1)
+	assert.NotContains(t, string(revision.Data[historyEncryptedDataKey]), "s3cr3t")
+
+	got, err := r.getRevisionData(ctx, es, 1)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	// revisions of another key can not be read.
+	r.HistoryEncryptionKey = []byte(strings.Repeat("o", HistoryEncryptionKeySize))
+	_, err = r.getRevisionData(ctx, es, 1)
+	assert.EqualError(t, err, "revision 1 is encrypted with another key")
+
+	// without a key, plaintext revisions require the opt-in.
+	r.HistoryEncryptionKey = nil
+	err = r.updateHistory(ctx, es, map[string][]byte{"key": []byte("v2")}, 0)
+	assert.EqualError(t, err, errHistoryPlaintext)
+}
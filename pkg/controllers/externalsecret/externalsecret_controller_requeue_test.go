@@ -0,0 +1,130 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+func newRequeueES(uid string, jitter *esv1.RefreshJitter) *esv1.ExternalSecret {
+	return &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: uid, Namespace: "default", UID: types.UID(uid)},
+		Spec: esv1.ExternalSecretSpec{
+			RefreshInterval: &metav1.Duration{Duration: time.Hour},
+			RefreshJitter:   jitter,
+		},
+		Status: esv1.ExternalSecretStatus{
+			RefreshTime: metav1.NewTime(time.Now()),
+		},
+	}
+}
+
+func TestGetRequeueResultJitter(t *testing.T) {
+	r := &Reconciler{}
+
+	// without jitter, the ExternalSecret is requeued after the refresh interval.
+	result := r.getRequeueResult(newRequeueES("a", nil))
+	assert.InDelta(t, time.Hour, result.RequeueAfter, float64(time.Second))
+
+	// the random jitter stays within the window.
+	for range 100 {
+		result = r.getRequeueResult(newRequeueES("a", &esv1.RefreshJitter{Percent: 10, Mode: esv1.RefreshJitterModeRandom}))
+		assert.GreaterOrEqual(t, result.RequeueAfter, time.Hour-time.Second)
+		assert.Less(t, result.RequeueAfter, time.Hour+6*time.Minute)
+	}
+
+	// the random jitter is recorded and reused until the next refresh.
+	random := newRequeueES("a", &esv1.RefreshJitter{Percent: 10, Mode: esv1.RefreshJitterModeRandom})
+	first := r.refreshJitter(random, time.Hour)
+	assert.Equal(t, &metav1.Duration{Duration: first}, random.Status.RefreshJitter)
+	for range 10 {
+		assert.Equal(t, first, r.refreshJitter(random, time.Hour))
+	}
+	// a recorded jitter outside of the window is rolled again.
+	random.Status.RefreshJitter = &metav1.Duration{Duration: time.Hour}
+	assert.Less(t, r.refreshJitter(random, time.Hour), 6*time.Minute)
+	r.markAsDone(random, time.Now(), logr.Discard(), esv1.ConditionReasonSecretSynced, msgSynced)
+	assert.Nil(t, random.Status.RefreshJitter)
+
+		// the hash jitter is stable for an ExternalSecret, and spreads different ExternalSecrets.
+	hash := &esv1.RefreshJitter{Percent: 50, Mode: esv1.RefreshJitterModeHash}
+	es := newRequeueES("a", hash)
+	assert.Equal(t, r.refreshJitter(es, time.Hour), r.refreshJitter(es, time.Hour))
+	slots := make(map[time.Duration]struct{})
+	for i := range 20 {
+		jitter := r.refreshJitter(newRequeueES(fmt.Sprintf("es-%d", i), hash), time.Hour)
+		assert.GreaterOrEqual(t, jitter, time.Duration(0))
+		assert.Less(t, jitter, 30*time.Minute)
+		slots[jitter] = struct{}{}
+	}
+	assert.Greater(t, len(slots), 1)
+
+	// the global jitter applies unless it is overridden by the ExternalSecret.
+	r.RefreshJitter = esv1.RefreshJitter{Percent: 50, Mode: esv1.RefreshJitterModeHash}
+	assert.Equal(t, r.refreshJitter(newRequeueES("a", hash), time.Hour), r.refreshJitter(newRequeueES("a", nil), time.Hour))
+	assert.Zero(t, r.refreshJitter(newRequeueES("a", &esv1.RefreshJitter{Percent: 0}), time.Hour))
+
+	// a refresh interval of 0 is never requeued.
+	es = newRequeueES("a", nil)
+	es.Spec.RefreshInterval = &metav1.Duration{}
+	assert.Zero(t, r.getRequeueResult(es).RequeueAfter)
+}
+
+func TestGetRequeueResultGeneratorRenewal(t *testing.T) {
+	r := &Reconciler{GeneratorRenewalPercent: 75}
+
+	// the renewal is scheduled after the configured share of the lifetime.
+	now := time.Now()
+	assert.Equal(t, now.Add(45*time.Minute), r.generatorRenewalTime(now, now.Add(time.Hour)))
+	assert.Zero(t, r.generatorRenewalTime(now, time.Time{}))
+	assert.Equal(t, now, r.generatorRenewalTime(now, now.Add(-time.Minute)))
+	assert.Zero(t, (&Reconciler{}).generatorRenewalTime(now, now.Add(time.Hour)))
+
+	// the earliest renewal of all generators wins.
+	assert.Equal(t, now, earliest(time.Time{}, now))
+	assert.Equal(t, now, earliest(now, time.Time{}))
+	assert.Equal(t, now, earliest(now.Add(time.Minute), now))
+
+	// a renewal before the refresh interval brings the requeue forward.
+	es := newRequeueES("a", nil)
+	es.Status.GeneratorRenewalTime = &metav1.Time{Time: time.Now().Add(10 * time.Minute)}
+	result := r.getRequeueResult(es)
+	assert.InDelta(t, 10*time.Minute, result.RequeueAfter, float64(time.Second))
+
+	// a renewal after the refresh interval does not delay the refresh.
+	es.Status.GeneratorRenewalTime = &metav1.Time{Time: time.Now().Add(2 * time.Hour)}
+	result = r.getRequeueResult(es)
+	assert.InDelta(t, time.Hour, result.RequeueAfter, float64(time.Second))
+
+	// a due renewal is requeued immediately and refreshes the ExternalSecret.
+	es.Status.GeneratorRenewalTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	assert.True(t, r.getRequeueResult(es).Requeue)
+	es.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(es.ObjectMeta)
+	assert.True(t, shouldRefresh(es))
+	es.Status.GeneratorRenewalTime = nil
+	assert.False(t, shouldRefresh(es))
+}
@@ -24,9 +24,13 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
@@ -61,7 +65,8 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 			// A generator is expected to always generate a secret.
 			// If it doesn't, it should return an error.
 			// If the error is NoSecretErr, we should commit the generator state.
-			if err != nil && !errors.Is(err, esv1.NoSecretErr) {
+			// In dry-run mode, generated values are never used, so they are rolled back as well.
+			if (err != nil && !errors.Is(err, esv1.NoSecretErr)) || isDryRun(externalSecret) {
 				if rollBackErr := genState.Rollback(); rollBackErr != nil {
 					r.Log.Error(rollBackErr, "error rolling back generator state")
 				}
@@ -75,6 +80,19 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 			}
 		}()
 	}
+	// the stores that served the data are recorded from scratch on every sync,
+	// but we keep the previous record if the data could not be fetched.
+	previousStores := externalSecret.Status.Stores
+	externalSecret.Status.Stores = nil
+	defer func() {
+		if err != nil && !errors.Is(err, esv1.NoSecretErr) {
+			externalSecret.Status.Stores = previousStores
+		}
+	}()
+
+	// the values of generators with an expiry are regenerated before the earliest of them expires.
+	var renewalTime time.Time
+
 	providerData = make(map[string][]byte)
 	for i, remoteRef := range externalSecret.Spec.DataFrom {
 		var secretMap map[string][]byte
@@ -90,10 +108,12 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 				err = fmt.Errorf("error processing spec.dataFrom[%d].extract, err: %w", i, err)
 			}
 		} else if remoteRef.SourceRef != nil && remoteRef.SourceRef.GeneratorRef != nil {
-			secretMap, err = r.handleGenerateSecrets(ctx, externalSecret.Namespace, remoteRef, i, genState)
+			var expiresAt time.Time
+			secretMap, expiresAt, err = r.handleGenerateSecrets(ctx, externalSecret.Namespace, remoteRef, i, genState)
 			if err != nil {
 				err = fmt.Errorf("error processing spec.dataFrom[%d].sourceRef.generatorRef, err: %w", i, err)
 			}
+			renewalTime = earliest(renewalTime, r.generatorRenewalTime(time.Now(), expiresAt))
 		}
 
 		if errors.Is(err, esv1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyRetain {
@@ -107,45 +127,218 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 		providerData = esutils.MergeByteMap(providerData, secretMap)
 	}
 
+	// the sync state of every key is recorded, keeping the last successful sync time of the keys that failed.
+	previousKeys := make(map[string]esv1.ExternalSecretKeyStatus, len(externalSecret.Status.Keys))
+	for _, key := range externalSecret.Status.Keys {
+		previousKeys[key.SecretKey] = key
+	}
+	keys := make([]esv1.ExternalSecretKeyStatus, 0, len(externalSecret.Spec.Data))
+	defer func() {
+		if len(keys) == 0 {
+			keys = nil
+		}
+		externalSecret.Status.Keys = keys
+	}()
+
+	var lastKnown *lastKnownValues
+	var keyErrs []error
 	for i, secretRef := range externalSecret.Spec.Data {
-		err := r.handleSecretData(ctx, externalSecret, secretRef, providerData, mgr)
+		servedBy, err := r.handleSecretData(ctx, externalSecret, secretRef, providerData, mgr)
+		keys = append(keys, newKeyStatus(previousKeys[secretRef.SecretKey], secretRef, servedBy, err))
 		if errors.Is(err, esv1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyRetain {
 			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonMissingProviderSecret, eventMissingProviderSecretKey, i, secretRef.RemoteRef.Key)
 			continue
 		}
-		if err != nil {
-			return nil, fmt.Errorf("error processing spec.data[%d] (key: %s), err: %w", i, secretRef.RemoteRef.Key, err)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("error processing spec.data[%d] (key: %s), err: %w", i, secretRef.RemoteRef.Key, err)
+		if externalSecret.Spec.ErrorPolicy != esv1.ErrorPolicyPartial {
+			// keep the previous state of the keys that were not processed.
+			for _, next := range externalSecret.Spec.Data[i+1:] {
+				if key, ok := previousKeys[next.SecretKey]; ok {
+					keys = append(keys, key)
+				}
+			}
+			return nil, err
+		}
+
+		// keep the last-known value of the key that failed.
+		if externalSecret.Spec.Target.Template != nil {
+			return nil, fmt.Errorf(errPartialTemplate, err)
+		}
+		keyErrs = append(keyErrs, err)
+		if lastKnown == nil {
+			lastKnown, err = r.getLastKnownValues(ctx, externalSecret)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if value, ok := lastKnown.data[secretRef.SecretKey]; ok {
+			providerData[secretRef.SecretKey] = value
 		}
 	}
 
+	// with ErrorPolicy=Partial, the sync only fails if no data could be resolved at all.
+	if len(keyErrs) > 0 && len(providerData) == 0 {
+		return nil, errors.Join(keyErrs...)
+	}
+
+	externalSecret.Status.GeneratorRenewalTime = nil
+	if !renewalTime.IsZero() {
+		externalSecret.Status.GeneratorRenewalTime = &metav1.Time{Time: renewalTime}
+	}
 	return providerData, nil
 }
 
-func (r *Reconciler) handleSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, secretRef esv1.ExternalSecretData, providerData map[string][]byte, cmgr *secretstore.Manager) error {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, toStoreGenSourceRef(secretRef.SourceRef))
+// lastKnownValues holds the data of the target Secret before the sync.
+type lastKnownValues struct {
+	data map[string][]byte
+}
+
+// getLastKnownValues returns the data of the current target Secret.
+// Generic targets are not supported, as their data can not be mapped to keys.
+// Templated targets are rejected before, as their data is keyed by the template output.
+func (r *Reconciler) getLastKnownValues(ctx context.Context, externalSecret *esv1.ExternalSecret) (*lastKnownValues, error) {
+	if isGenericTarget(externalSecret) {
+		return &lastKnownValues{}, nil
+	}
+	secretName := externalSecret.Spec.Target.Name
+	if secretName == "" {
+		secretName = externalSecret.Name
+	}
+	secretClient := r.SecretClient
+	if secretClient == nil {
+		secretClient = r.Client
+	}
+	var secret v1.Secret
+	err := secretClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: externalSecret.Namespace}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	return &lastKnownValues{data: secret.Data}, nil
+}
+
+// newKeyStatus returns the sync state of a key of spec.data.
+func newKeyStatus(previous esv1.ExternalSecretKeyStatus, secretRef esv1.ExternalSecretData, servedBy esv1.NamedStoreRef, err error) esv1.ExternalSecretKeyStatus {
+	status := esv1.ExternalSecretKeyStatus{
+		SecretKey:    secretRef.SecretKey,
+		RemoteKey:    secretRef.RemoteRef.Key,
+		LastSyncTime: previous.LastSyncTime,
+	}
+	if servedBy.Name != "" {
+		status.Store = &servedBy
+	}
 	if err != nil {
-		return err
+		status.Error = err.Error()
+	} else {
+		status.LastSyncTime = metav1.Now()
+	}
+	return status
+}
+
+// failedKeys returns the keys of spec.data that could not be fetched during the last sync.
+func failedKeys(externalSecret *esv1.ExternalSecret) []string {
+	var keys []string
+	for _, key := range externalSecret.Status.Keys {
+		if key.Error != "" {
+			keys = append(keys, key.SecretKey)
+		}
 	}
+	return keys
+}
 
+func (r *Reconciler) handleSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, secretRef esv1.ExternalSecretData, providerData map[string][]byte, cmgr *secretstore.Manager) (esv1.NamedStoreRef, error) {
 	// get a single secret from the store
-	secretData, err := client.GetSecret(ctx, secretRef.RemoteRef)
-	if err != nil {
+	var secretData []byte
+	servedBy, err := r.getFromStores(ctx, externalSecret, cmgr, toStoreGenSourceRef(secretRef.SourceRef), func(client esv1.SecretsClient) error {
+		var err error
+		secretData, err = client.GetSecret(ctx, secretRef.RemoteRef)
 		return err
+	})
+	if err != nil {
+		return servedBy, err
 	}
 
 	// decode the secret if needed
 	secretData, err = esutils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
 	if err != nil {
-		return fmt.Errorf(errDecode, secretRef.RemoteRef.DecodingStrategy, err)
+		return servedBy, fmt.Errorf(errDecode, secretRef.RemoteRef.DecodingStrategy, err)
 	}
 	if err := validateFetchedSecretValue(secretRef.RemoteRef.NullBytePolicy, secretRef.SecretKey, secretData); err != nil {
-		return err
+		return servedBy, err
 	}
 
 	// store the secret data
 	providerData[secretRef.SecretKey] = secretData
 
-	return nil
+	return servedBy, nil
+}
+
+// getFromStores calls fn with a client of the store referenced by the ExternalSecret,
+// while sourceRef.SecretStoreRef takes precedence over spec.secretStoreRef.
+// If the store can not be used or fn fails with an error other than esv1.NoSecretErr,
+// the fallback stores of that reference are tried in order.
+// A store that failed is remembered by the manager and skipped for the rest of the reconcile,
+// so that an outage of the primary store does not delay every key.
+// The store that eventually served the data is returned and recorded in the status.
+func (r *Reconciler) getFromStores(ctx context.Context, externalSecret *esv1.ExternalSecret, cmgr *secretstore.Manager, sourceRef *esv1.StoreGeneratorSourceRef, fn func(esv1.SecretsClient) error) (esv1.NamedStoreRef, error) {
+	storeRef := externalSecret.Spec.SecretStoreRef
+	if sourceRef != nil && sourceRef.SecretStoreRef != nil {
+		storeRef = *sourceRef.SecretStoreRef
+	}
+	primary := toNamedStoreRef(storeRef.Name, storeRef.Kind)
+	candidates := append([]esv1.NamedStoreRef{primary}, storeRef.FallbackStoreRefs...)
+
+	var errs []error
+	for _, candidate := range candidates {
+		candidate = toNamedStoreRef(candidate.Name, candidate.Kind)
+		ref := esv1.SecretStoreRef{Name: candidate.Name, Kind: candidate.Kind}
+		if err := cmgr.Failed(ref, externalSecret.Namespace); err != nil && len(storeRef.FallbackStoreRefs) > 0 {
+			errs = append(errs, fmt.Errorf(errStoreFailed, candidate.Kind, candidate.Name, err))
+			continue
+		}
+		client, err := cmgr.Get(ctx, ref, externalSecret.Namespace, nil)
+		if err == nil {
+			err = fn(client)
+		}
+		if err == nil {
+			if candidate != primary {
+				r.recorder.Eventf(externalSecret, v1.EventTypeWarning, esv1.ReasonStoreFallback, eventStoreFallback, primary.Kind, primary.Name, candidate.Kind, candidate.Name)
+			}
+			setStoreStatus(externalSecret, primary, candidate)
+			return candidate, nil
+		}
+		// a missing secret is a valid answer of the primary store and must not be served by another store.
+		if candidate == primary && (errors.Is(err, esv1.NoSecretErr) || len(storeRef.FallbackStoreRefs) == 0) {
+			return candidate, err
+		}
+		r.Log.V(1).Info(logStoreFailed, "kind", candidate.Kind, "name", candidate.Name, "error", err.Error())
+		if errors.Is(err, esv1.NoSecretErr) {
+			// the primary store failed, so a fallback store which does not know the secret (yet)
+			// must not be mistaken for a deleted secret, e.g. with deletionPolicy Delete.
+			errs = append(errs, fmt.Errorf(errStoreMissing, candidate.Kind, candidate.Name))
+			continue
+		}
+		cmgr.MarkFailed(ref, externalSecret.Namespace, err)
+		errs = append(errs, fmt.Errorf(errStoreFailed, candidate.Kind, candidate.Name, err))
+	}
+	return primary, errors.Join(errs...)
+}
+
+func toNamedStoreRef(name, kind string) esv1.NamedStoreRef {
+	if kind == "" {
+		kind = esv1.SecretStoreKind
+	}
+	return esv1.NamedStoreRef{Name: name, Kind: kind}
+}
+
+func setStoreStatus(externalSecret *esv1.ExternalSecret, storeRef, servedBy esv1.NamedStoreRef) {
+	status := esv1.ExternalSecretStoreStatus{StoreRef: storeRef, ServedBy: servedBy}
+	if slices.Contains(externalSecret.Status.Stores, status) {
+		return
+	}
+	externalSecret.Status.Stores = append(externalSecret.Status.Stores, status)
 }
 
 func toStoreGenSourceRef(ref *esv1.StoreSourceRef) *esv1.StoreGeneratorSourceRef {
@@ -163,21 +356,21 @@ func (r *Reconciler) handleGenerateSecrets(
 	remoteRef esv1.ExternalSecretDataFromRemoteRef,
 	i int,
 	generatorState *statemanager.Manager,
-) (map[string][]byte, error) {
+) (map[string][]byte, time.Time, error) {
 	impl, generatorResource, err := resolvers.GeneratorRef(ctx, r.Client, r.Scheme, namespace, remoteRef.SourceRef.GeneratorRef)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 	var latestState *genv1alpha1.GeneratorState
 	if generatorState != nil {
 		latestState, err = generatorState.GetLatestState(generatorStateKey(i))
 		if err != nil {
-			return nil, fmt.Errorf("unable to get latest state: %w", err)
+			return nil, time.Time{}, fmt.Errorf("unable to get latest state: %w", err)
 		}
 	}
-	secretMap, newState, err := impl.Generate(ctx, generatorResource, r.Client, namespace)
+	secretMap, newState, expiresAt, err := genv1alpha1.GenerateWithExpiry(ctx, impl, generatorResource, r.Client, namespace)
 	if err != nil {
-		return nil, fmt.Errorf(errGenerate, err)
+		return nil, time.Time{}, fmt.Errorf(errGenerate, err)
 	}
 	if latestState != nil {
 		if generatorState != nil {
@@ -185,21 +378,21 @@ func (r *Reconciler) handleGenerateSecrets(
 		}
 	}
 	if generatorState != nil {
-		generatorState.EnqueueSetLatest(ctx, generatorStateKey(i), namespace, generatorResource, impl, newState)
+		generatorState.EnqueueSetLatest(ctx, generatorStateKey(i), namespace, generatorResource, impl, newState, expiresAt)
 	}
 	// rewrite the keys if needed
 	secretMap, err = esutils.RewriteMap(remoteRef.Rewrite, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errRewrite, err)
+		return nil, time.Time{}, fmt.Errorf(errRewrite, err)
 	}
 
 	// validate the keys
 	err = esutils.ValidateKeys(r.Log, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errInvalidKeys, err)
+		return nil, time.Time{}, fmt.Errorf(errInvalidKeys, err)
 	}
 
-	return secretMap, err
+	return secretMap, expiresAt, err
 }
 
 // We're using the index of the generator as the key for the generator state
@@ -217,13 +410,13 @@ func (r *Reconciler) handleExtractSecrets(
 	genState *statemanager.Manager,
 	i int,
 ) (map[string][]byte, error) {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, remoteRef.SourceRef)
-	if err != nil {
-		return nil, err
-	}
-
 	// get multiple secrets from the store
-	secretMap, err := client.GetSecretMap(ctx, *remoteRef.Extract)
+	var secretMap map[string][]byte
+	_, err := r.getFromStores(ctx, externalSecret, cmgr, remoteRef.SourceRef, func(client esv1.SecretsClient) error {
+		var err error
+		secretMap, err = client.GetSecretMap(ctx, *remoteRef.Extract)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -268,15 +461,18 @@ func (r *Reconciler) handleFindAllSecrets(
 	genState *statemanager.Manager,
 	i int,
 ) (map[string][]byte, error) {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, remoteRef.SourceRef)
-	if err != nil {
-		return nil, err
-	}
-
 	// get all secrets from the store that match the selector
-	secretMap, err := client.GetAllSecrets(ctx, *remoteRef.Find)
+	var secretMap map[string][]byte
+	_, err := r.getFromStores(ctx, externalSecret, cmgr, remoteRef.SourceRef, func(client esv1.SecretsClient) error {
+		var err error
+		secretMap, err = client.GetAllSecrets(ctx, *remoteRef.Find)
+		if err != nil {
+			return fmt.Errorf("error getting all secrets: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error getting all secrets: %w", err)
+		return nil, err
 	}
 
 	// rewrite the keys if needed
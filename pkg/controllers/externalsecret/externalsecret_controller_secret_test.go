@@ -0,0 +1,338 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+const (
+	failoverNamespace = "default"
+	primaryStoreName  = "primary"
+	fallbackStoreName = "fallback"
+)
+
+func newFailoverStore(name string) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: failoverNamespace,
+		},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				AWS: &esv1.AWSProvider{
+					Service: esv1.AWSServiceSecretsManager,
+				},
+			},
+		},
+	}
+}
+
+func TestGetProviderSecretDataFailover(t *testing.T) {
+	primaryRef := esv1.NamedStoreRef{Name: primaryStoreName, Kind: esv1.SecretStoreKind}
+	fallbackRef := esv1.NamedStoreRef{Name: fallbackStoreName, Kind: esv1.SecretStoreKind}
+
+	tests := []struct {
+		name          string
+		primaryErr    error
+		primaryNewErr error
+		fallbackErr   error
+		fallbacks     []esv1.NamedStoreRef
+		wantData      map[string][]byte
+		wantErr       error
+		wantStores    []esv1.ExternalSecretStoreStatus
+		wantEvents    int
+	}{
+		{
+			name:       "primary store serves the data",
+			fallbacks:  []esv1.NamedStoreRef{{Name: fallbackStoreName}},
+			wantData:   map[string][]byte{"key": []byte("primary")},
+			wantStores: []esv1.ExternalSecretStoreStatus{{StoreRef: primaryRef, ServedBy: primaryRef}},
+		},
+		{
+			name:       "provider error falls back to the next store",
+			primaryErr: errors.New("provider unavailable"),
+			fallbacks:  []esv1.NamedStoreRef{{Name: fallbackStoreName}},
+			wantData:   map[string][]byte{"key": []byte("fallback")},
+			wantStores: []esv1.ExternalSecretStoreStatus{{StoreRef: primaryRef, ServedBy: fallbackRef}},
+			wantEvents: 1,
+		},
+		{
+			name:          "unusable store falls back to the next store",
+			primaryNewErr: errors.New("invalid credentials"),
+			fallbacks:     []esv1.NamedStoreRef{{Name: "does-not-exist"}, {Name: fallbackStoreName}},
+			wantData:      map[string][]byte{"key": []byte("fallback")},
+			wantStores:    []esv1.ExternalSecretStoreStatus{{StoreRef: primaryRef, ServedBy: fallbackRef}},
+			wantEvents:    1,
+		},
+		{
+			name:       "missing secret does not fall back",
+			primaryErr: esv1.NoSecretErr,
+			fallbacks:  []esv1.NamedStoreRef{{Name: fallbackStoreName}},
+			wantData:   map[string][]byte{},
+			wantEvents: 1,
+		},
+		{
+			name:        "missing secret in a fallback store is not accepted after a primary failure",
+			primaryErr:  errors.New("provider unavailable"),
+			fallbackErr: esv1.NoSecretErr,
+			fallbacks:   []esv1.NamedStoreRef{{Name: fallbackStoreName}},
+			wantErr:     errors.New("secret does not exist in fallback store"),
+		},
+		{
+			name:       "error without fallback stores is returned as is",
+			primaryErr: errors.New("provider unavailable"),
+			wantErr:    errors.New("provider unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := fake.New().WithGetSecret([]byte("primary"), tt.primaryErr)
+			fallback := fake.New().WithGetSecret([]byte("fallback"), tt.fallbackErr)
+			fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+				if store.GetName() == primaryStoreName {
+					return primary, tt.primaryNewErr
+				}
+				return fallback, nil
+			})
+			t.Cleanup(fakeProvider.Reset)
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			require.NoError(t, esv1.AddToScheme(scheme))
+			kube := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newFailoverStore(primaryStoreName), newFailoverStore(fallbackStoreName)).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:   kube,
+				Log:      logr.Discard(),
+				Scheme:   scheme,
+				recorder: recorder,
+			}
+			es := &esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: failoverNamespace},
+				Spec: esv1.ExternalSecretSpec{
+					SecretStoreRef: esv1.SecretStoreRef{
+						Name:              primaryStoreName,
+						FallbackStoreRefs: tt.fallbacks,
+					},
+					Data: []esv1.ExternalSecretData{{
+						SecretKey: "key",
+						RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "remote"},
+					}},
+				},
+			}
+
+			data, err := r.GetProviderSecretData(context.Background(), es)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				assert.NotErrorIs(t, err, esv1.NoSecretErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantData, data)
+			assert.Equal(t, tt.wantStores, es.Status.Stores)
+			assert.Len(t, recorder.Events, tt.wantEvents)
+		})
+	}
+}
+
+func TestGetProviderSecretDataFailoverSkipsFailedStore(t *testing.T) {
+	primary := fake.New().WithGetSecret(nil, errors.New("provider unavailable"))
+	fallback := fake.New().WithGetSecret([]byte("fallback"), nil)
+	newClients := map[string]int{}
+	fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		newClients[store.GetName()]++
+		if store.GetName() == primaryStoreName {
+			return primary, nil
+		}
+		return fallback, nil
+	})
+	t.Cleanup(fakeProvider.Reset)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newFailoverStore(primaryStoreName), newFailoverStore(fallbackStoreName)).
+		Build()
+
+	r := &Reconciler{
+		Client:   kube,
+		Log:      logr.Discard(),
+		Scheme:   scheme,
+		recorder: record.NewFakeRecorder(10),
+	}
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: failoverNamespace},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef: esv1.SecretStoreRef{
+				Name:              primaryStoreName,
+				FallbackStoreRefs: []esv1.NamedStoreRef{{Name: fallbackStoreName}},
+			},
+		},
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		es.Spec.Data = append(es.Spec.Data, esv1.ExternalSecretData{
+			SecretKey: key,
+			RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: key},
+		})
+	}
+
+	data, err := r.GetProviderSecretData(context.Background(), es)
+	require.NoError(t, err)
+	assert.Len(t, data, 3)
+	// the primary store is only tried once and the client of the fallback store is reused.
+	assert.Equal(t, map[string]int{primaryStoreName: 1, fallbackStoreName: 1}, newClients)
+}
+
+func TestGetProviderSecretDataPartial(t *testing.T) {
+	const brokenStoreName = "broken"
+	primaryRef := esv1.NamedStoreRef{Name: primaryStoreName, Kind: esv1.SecretStoreKind}
+	brokenRef := esv1.NamedStoreRef{Name: brokenStoreName, Kind: esv1.SecretStoreKind}
+	lastSync := metav1.NewTime(metav1.Now().Add(-time.Hour).Truncate(time.Second))
+
+	tests := []struct {
+		name        string
+		errorPolicy esv1.ExternalSecretErrorPolicy
+		template    *esv1.ExternalSecretTemplate
+		wantData    map[string][]byte
+		wantErr     string
+	}{
+		{
+			name:    "failing key fails the sync by default",
+			wantErr: "provider unavailable",
+		},
+		{
+			// the target holds the rendered template, which does not contain the last-known fetched values.
+			name:        "failing key fails the sync with ErrorPolicy=Partial and a template",
+			errorPolicy: esv1.ErrorPolicyPartial,
+			template:    &esv1.ExternalSecretTemplate{Data: map[string]string{"dsn": "{{ .ok }}:{{ .broken }}"}},
+			wantErr:     "errorPolicy=Partial can not be used with target.template",
+		},
+		{
+			name:        "failing key keeps its last-known value with ErrorPolicy=Partial",
+			errorPolicy: esv1.ErrorPolicyPartial,
+			wantData: map[string][]byte{
+				"ok":     []byte("value"),
+				"broken": []byte("last-known"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			working := fake.New().WithGetSecret([]byte("value"), nil)
+			broken := fake.New().WithGetSecret(nil, errors.New("provider unavailable"))
+			fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+				if store.GetName() == brokenStoreName {
+					return broken, nil
+				}
+				return working, nil
+			})
+			t.Cleanup(fakeProvider.Reset)
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			require.NoError(t, esv1.AddToScheme(scheme))
+			target := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: failoverNamespace},
+				Data: map[string][]byte{
+					"ok":     []byte("old"),
+					"broken": []byte("last-known"),
+				},
+			}
+			kube := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newFailoverStore(primaryStoreName), newFailoverStore(brokenStoreName), target).
+				Build()
+
+			r := &Reconciler{
+				Client:   kube,
+				Log:      logr.Discard(),
+				Scheme:   scheme,
+				recorder: record.NewFakeRecorder(10),
+			}
+			es := &esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: failoverNamespace},
+				Spec: esv1.ExternalSecretSpec{
+					ErrorPolicy:    tt.errorPolicy,
+					Target:         esv1.ExternalSecretTarget{Template: tt.template},
+					SecretStoreRef: esv1.SecretStoreRef{Name: primaryStoreName},
+					Data: []esv1.ExternalSecretData{
+						{
+							SecretKey: "ok",
+							RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "remote-ok"},
+						},
+						{
+							SecretKey: "broken",
+							RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "remote-broken"},
+							SourceRef: &esv1.StoreSourceRef{SecretStoreRef: esv1.SecretStoreRef{Name: brokenStoreName}},
+						},
+					},
+				},
+				Status: esv1.ExternalSecretStatus{
+					Keys: []esv1.ExternalSecretKeyStatus{{SecretKey: "broken", RemoteKey: "remote-broken", LastSyncTime: lastSync}},
+				},
+			}
+
+			data, err := r.GetProviderSecretData(context.Background(), es)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantData, data)
+
+			require.Len(t, es.Status.Keys, 2)
+			assert.Equal(t, "ok", es.Status.Keys[0].SecretKey)
+			assert.Equal(t, &primaryRef, es.Status.Keys[0].Store)
+			assert.Empty(t, es.Status.Keys[0].Error)
+			assert.False(t, es.Status.Keys[0].LastSyncTime.IsZero())
+
+			assert.Equal(t, "broken", es.Status.Keys[1].SecretKey)
+			assert.Equal(t, "remote-broken", es.Status.Keys[1].RemoteKey)
+			assert.Equal(t, &brokenRef, es.Status.Keys[1].Store)
+			assert.Contains(t, es.Status.Keys[1].Error, "provider unavailable")
+			assert.Equal(t, lastSync, es.Status.Keys[1].LastSyncTime)
+			assert.Equal(t, []string{"broken"}, failedKeys(es))
+		})
+	}
+}
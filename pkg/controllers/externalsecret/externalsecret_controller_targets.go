@@ -0,0 +1,398 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+const (
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorUpdateTargets = "could not update additional targets"
+
+	// error formats.
+	errTarget        = "targets[%s]: %w"
+	errRemovedTarget = "removed target %s %s: %w"
+)
+
+// additionalTargets returns a copy of the ExternalSecret for every entry of spec.targets,
+// with spec.target replaced by that entry, so they can be handled like the primary target.
+func additionalTargets(es *esv1.ExternalSecret) []*esv1.ExternalSecret {
+	views := make([]*esv1.ExternalSecret, 0, len(es.Spec.Targets))
+	for _, target := range es.Spec.Targets {
+		view := es.DeepCopy()
+		view.Spec.Target = *target.DeepCopy()
+		view.Spec.Target.History = nil
+		view.Spec.Targets = nil
+		views = append(views, view)
+	}
+	return views
+}
+
+// allTargets returns the ExternalSecret itself followed by the views of its additional targets.
+func allTargets(es *esv1.ExternalSecret) []*esv1.ExternalSecret {
+	return append([]*esv1.ExternalSecret{es}, additionalTargets(es)...)
+}
+
+// targetSecretNames returns the names of all Secrets targeted by the ExternalSecret.
+func targetSecretNames(es *esv1.ExternalSecret) []string {
+	var names []string
+	for _, target := range allTargets(es) {
+		if !isGenericTarget(target) {
+			names = append(names, getTargetName(target))
+		}
+	}
+	return names
+}
+
+// targetResourceKeys returns the "group/version/kind/name" keys of all generic resources targeted by the ExternalSecret.
+func targetResourceKeys(es *esv1.ExternalSecret) []string {
+	var keys []string
+	for _, target := range allTargets(es) {
+		if isGenericTarget(target) {
+			gvk := getTargetGVK(target)
+			keys = append(keys, fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Version, gvk.Kind, getTargetName(target)))
+		}
+	}
+	return keys
+}
+
+// targetStatus returns the status entry identifying the resource of a target.
+func targetStatus(target *esv1.ExternalSecret) esv1.ExternalSecretTargetStatus {
+	gvk := v1.SchemeGroupVersion.WithKind("Secret")
+	if isGenericTarget(target) {
+		gvk = getTargetGVK(target)
+	}
+	return esv1.ExternalSecretTargetStatus{
+		APIVersion:     gvk.GroupVersion().String(),
+		Kind:           gvk.Kind,
+		Name:           getTargetName(target),
+		CreationPolicy: target.Spec.Target.CreationPolicy,
+	}
+}
+
+// targetStatusKey identifies a resource independent of the creation policy it was provisioned with.
+func targetStatusKey(target esv1.ExternalSecretTargetStatus) string {
+	return fmt.Sprintf("%s/%s/%s", target.APIVersion, target.Kind, target.Name)
+}
+
+// cleanupRemovedTargets deletes the resources of the entries which were removed from spec.targets,
+// and records the current entries in the status.
+// Entries which could not be cleaned up are kept in the status, so they are retried on the next reconcile.
+func (r *Reconciler) cleanupRemovedTargets(ctx context.Context, log logr.Logger, es *esv1.ExternalSecret) error {
+	// the primary target may take over a resource of a removed entry.
+	inUse := make(map[string]bool)
+	inUseGVKs := make(map[schema.GroupVersionKind]bool)
+	for _, target := range allTargets(es) {
+		inUse[targetStatusKey(targetStatus(target))] = true
+		if isGenericTarget(target) {
+			inUseGVKs[getTargetGVK(target)] = true
+		}
+	}
+
+	current := make([]esv1.ExternalSecretTargetStatus, 0, len(es.Spec.Targets))
+	for _, target := range additionalTargets(es) {
+		current = append(current, targetStatus(target))
+	}
+
+	var errs []error
+	for _, removed := range es.Status.Targets {
+		if inUse[targetStatusKey(removed)] {
+			continue
+		}
+		if err := r.cleanupRemovedTarget(ctx, log, es, removed); err != nil {
+			errs = append(errs, fmt.Errorf(errRemovedTarget, removed.Kind, removed.Name, err))
+			current = append(current, removed)
+			continue
+		}
+
+		gvk := schema.FromAPIVersionAndKind(removed.APIVersion, removed.Kind)
+		if r.informerManager == nil || inUseGVKs[gvk] || gvk == v1.SchemeGroupVersion.WithKind("Secret") {
+			continue
+		}
+		esName := types.NamespacedName{Name: es.Name, Namespace: es.Namespace}
+		if err := r.informerManager.ReleaseInformer(ctx, gvk, esName); err != nil {
+			log.Error(err, "failed to release informer for removed target",
+				"group", gvk.Group,
+				"version", gvk.Version,
+				"kind", gvk.Kind)
+		}
+	}
+
+	if len(current) == 0 {
+		current = nil
+	}
+	es.Status.Targets = current
+	return errors.Join(errs...)
+}
+
+// cleanupRemovedTarget deletes the resource of a removed entry of spec.targets,
+// if it was created with creationPolicy=Owner and is still controlled by the ExternalSecret.
+func (r *Reconciler) cleanupRemovedTarget(ctx context.Context, log logr.Logger, es *esv1.ExternalSecret, removed esv1.ExternalSecretTargetStatus) error {
+	if removed.CreationPolicy != esv1.CreatePolicyOwner {
+		return nil
+	}
+
+	gvk := schema.FromAPIVersionAndKind(removed.APIVersion, removed.Kind)
+	key := client.ObjectKey{Name: removed.Name, Namespace: es.Namespace}
+	var obj client.Object
+	if gvk == v1.SchemeGroupVersion.WithKind("Secret") {
+		// secrets are read from the metadata cache.
+		partial := &metav1.PartialObjectMetadata{}
+		partial.SetGroupVersionKind(gvk)
+		obj = partial
+	} else {
+		resource := &unstructured.Unstructured{}
+		resource.SetGroupVersionKind(gvk)
+		obj = resource
+	}
+	if err := r.Get(ctx, key, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(obj, es) {
+		log.V(1).Info("removed target is not controlled by the ExternalSecret, skipping deletion", "kind", removed.Kind, "name", removed.Name)
+		return nil
+	}
+
+	log.Info("deleting removed target", "gvk", gvk.String(), "name", removed.Name)
+	if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	r.recorder.Event(es, v1.EventTypeNormal, esv1.ReasonDeleted, fmt.Sprintf("Deleted removed target %s %s", removed.Kind, removed.Name))
+	return nil
+}
+
+// additionalTargetsValid returns true if all additional targets exist and are up-to-date,
+// following the same rules as the primary target.
+func (r *Reconciler) additionalTargetsValid(ctx context.Context, log logr.Logger, es *esv1.ExternalSecret) bool {
+	// entries were added to or removed from spec.targets.
+	if len(es.Status.Targets) != len(es.Spec.Targets) {
+		return false
+	}
+	for i, target := range additionalTargets(es) {
+		if es.Status.Targets[i] != targetStatus(target) {
+			return false
+		}
+		if isGenericTarget(target) {
+			existing, err := r.getGenericResource(ctx, log, target)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return false
+			}
+			if valid, err := isGenericTargetValid(existing, target); err != nil || !valid {
+				return false
+			}
+			continue
+		}
+
+		existing := &v1.Secret{}
+		err := r.SecretClient.Get(ctx, client.ObjectKey{Name: getTargetName(target), Namespace: es.Namespace}, existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false
+		}
+		if !isSecretValid(existing, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// reconcileAdditionalTargets renders the provider data into every entry of spec.targets.
+// All targets are reconciled, the errors are joined.
+func (r *Reconciler) reconcileAdditionalTargets(ctx context.Context, log logr.Logger, es *esv1.ExternalSecret, dataMap map[string][]byte) error {
+	var errs []error
+	if err := r.cleanupRemovedTargets(ctx, log, es); err != nil {
+		errs = append(errs, err)
+	}
+	for _, target := range additionalTargets(es) {
+		var err error
+		if isGenericTarget(target) {
+			err = r.reconcileAdditionalResource(ctx, log, target, dataMap)
+		} else {
+			err = r.reconcileAdditionalSecret(ctx, log, target, dataMap)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errTarget, getTargetName(target), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) reconcileAdditionalSecret(ctx context.Context, log logr.Logger, target *esv1.ExternalSecret, dataMap map[string][]byte) error {
+	secretName := getTargetName(target)
+	existingSecret, err := r.getTargetSecret(ctx, target, secretName)
+	if err != nil {
+		return err
+	}
+
+	creationPolicy := target.Spec.Target.CreationPolicy
+	if len(dataMap) == 0 {
+		switch target.Spec.Target.DeletionPolicy {
+		case esv1.DeletionPolicyDelete:
+			if creationPolicy != esv1.CreatePolicyOwner {
+				return fmt.Errorf(errDeleteCreatePolicy, secretName, creationPolicy)
+			}
+			if existingSecret.UID != "" {
+				if err := r.Delete(ctx, existingSecret); err != nil && !apierrors.IsNotFound(err) {
+					return err
+				}
+				r.recorder.Event(target, v1.EventTypeNormal, esv1.ReasonDeleted, eventDeleted)
+			}
+			return nil
+		case esv1.DeletionPolicyRetain:
+			return nil
+		case esv1.DeletionPolicyMerge:
+		}
+	}
+
+	mutationFunc := r.secretMutationFunc(ctx, target, dataMap)
+	switch creationPolicy {
+	case esv1.CreatePolicyNone:
+		log.V(1).Info("secret creation skipped due to CreationPolicy=None", "secretName", secretName)
+	case esv1.CreatePolicyMerge:
+		if existingSecret.UID == "" {
+			log.V(1).Info("secret will not be created due to CreationPolicy=Merge", "secretName", secretName)
+			return nil
+		}
+		return r.updateSecret(ctx, existingSecret, mutationFunc, target, secretName)
+	case esv1.CreatePolicyOrphan, esv1.CreatePolicyOwner:
+		if existingSecret.UID == "" {
+			return r.createSecret(ctx, mutationFunc, target, secretName)
+		}
+		return r.updateSecret(ctx, existingSecret, mutationFunc, target, secretName)
+	}
+	return nil
+}
+
+// getTargetSecret fetches an existing Secret of an additional target.
+// Unlike the primary target, a Secret which is not yet managed is read from the API server,
+// rather than labeling it and waiting for the managed secrets cache.
+func (r *Reconciler) getTargetSecret(ctx context.Context, target *esv1.ExternalSecret, secretName string) (*v1.Secret, error) {
+	key := client.ObjectKey{Name: secretName, Namespace: target.Namespace}
+	secretPartial := &metav1.PartialObjectMetadata{}
+	secretPartial.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Secret"))
+	if err := r.Get(ctx, key, secretPartial); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	existingSecret := &v1.Secret{}
+	if secretPartial.UID != "" && secretPartial.Labels[esv1.LabelManaged] != esv1.LabelManagedValue {
+		secretReader := r.APIReader
+		if secretReader == nil {
+			secretReader = r.SecretClient
+		}
+		if err := secretReader.Get(ctx, key, existingSecret); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return existingSecret, nil
+	}
+
+	if err := r.SecretClient.Get(ctx, key, existingSecret); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	existingSecret, cacheNotSynced, err := r.resolveSecretCacheMismatch(ctx, key, secretPartial, existingSecret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if cacheNotSynced {
+		return nil, fmt.Errorf(errSecretCachesNotSynced, secretName)
+	}
+	return existingSecret, nil
+}
+
+func (r *Reconciler) reconcileAdditionalResource(ctx context.Context, log logr.Logger, target *esv1.ExternalSecret, dataMap map[string][]byte) error {
+	if err := r.validateGenericTarget(log, target); err != nil {
+		return err
+	}
+
+	existing, err := r.getGenericResource(ctx, log, target)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	creationPolicy := target.Spec.Target.CreationPolicy
+	if len(dataMap) == 0 {
+		switch target.Spec.Target.DeletionPolicy {
+		case esv1.DeletionPolicyDelete:
+			if creationPolicy != esv1.CreatePolicyOwner {
+				return fmt.Errorf("unable to delete resource: creationPolicy=%s is not Owner", creationPolicy)
+			}
+			return r.deleteGenericResource(ctx, log, target)
+		case esv1.DeletionPolicyRetain:
+			return nil
+		case esv1.DeletionPolicyMerge:
+		}
+	}
+
+	var baseObj *unstructured.Unstructured
+	if creationPolicy == esv1.CreatePolicyMerge && existing != nil {
+		baseObj = existing
+	}
+	obj, err := r.applyTemplateToManifest(ctx, target, dataMap, baseObj)
+	if err != nil {
+		return err
+	}
+
+	switch creationPolicy {
+	case esv1.CreatePolicyNone:
+		log.V(1).Info("resource creation skipped due to CreationPolicy=None", "name", getTargetName(target))
+		return nil
+	case esv1.CreatePolicyMerge:
+		if existing == nil || existing.GetUID() == "" {
+			log.V(1).Info("resource will not be created due to CreationPolicy=Merge", "name", getTargetName(target))
+			return nil
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		obj.SetUID(existing.GetUID())
+		err = r.updateGenericResource(ctx, log, target, obj)
+	case esv1.CreatePolicyOrphan, esv1.CreatePolicyOwner:
+		if existing != nil {
+			obj.SetResourceVersion(existing.GetResourceVersion())
+			obj.SetUID(existing.GetUID())
+			err = r.updateGenericResource(ctx, log, target, obj)
+		} else {
+			err = r.createGenericResource(ctx, log, target, obj)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.informerManager != nil {
+		gvk := getTargetGVK(target)
+		esName := types.NamespacedName{Name: target.Name, Namespace: target.Namespace}
+		if _, err := r.informerManager.EnsureInformer(ctx, gvk, esName); err != nil {
+			log.Error(err, "failed to register informer for generic target, drift detection may not work",
+				"group", gvk.Group,
+				"version", gvk.Version,
+				"kind", gvk.Kind)
+		}
+	}
+	return nil
+}
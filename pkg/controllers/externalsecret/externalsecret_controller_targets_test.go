@@ -0,0 +1,227 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestTargetNames(t *testing.T) {
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			Targets: []esv1.ExternalSecretTarget{
+				{Name: "copy"},
+				{Name: "config", Manifest: &esv1.ManifestReference{APIVersion: "v1", Kind: "ConfigMap"}},
+			},
+		},
+	}
+	assert.Equal(t, []string{"es", "copy"}, targetSecretNames(es))
+	assert.Equal(t, []string{"/v1/ConfigMap/config"}, targetResourceKeys(es))
+
+	views := additionalTargets(es)
+	require.Len(t, views, 2)
+	assert.Equal(t, "copy", views[0].Spec.Target.Name)
+	assert.Empty(t, views[0].Spec.Targets)
+	assert.Empty(t, es.Spec.Target.Name)
+}
+
+func TestReconcileAdditionalTargets(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	unmanaged := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "merge", Namespace: "default", UID: "merge"},
+		Data:       map[string][]byte{"other": []byte("kept")},
+	}
+	// the fake client does not set the UID of created objects.
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(unmanaged).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			obj.SetUID(types.UID(obj.GetName()))
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	r := &Reconciler{
+		Client:       fakeClient,
+		SecretClient: fakeClient,
+		Log:          logr.Discard(),
+		Scheme:       scheme,
+		recorder:     record.NewFakeRecorder(10),
+	}
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "1234"},
+		Spec: esv1.ExternalSecretSpec{
+			Targets: []esv1.ExternalSecretTarget{
+				{Name: "owner", CreationPolicy: esv1.CreatePolicyOwner, DeletionPolicy: esv1.DeletionPolicyDelete},
+				{Name: "merge", CreationPolicy: esv1.CreatePolicyMerge, DeletionPolicy: esv1.DeletionPolicyRetain},
+				{Name: "missing", CreationPolicy: esv1.CreatePolicyMerge},
+			},
+		},
+	}
+	data := map[string][]byte{"key": []byte("value")}
+
+	require.NoError(t, r.reconcileAdditionalTargets(ctx, logr.Discard(), es, data))
+
+	// like the primary target, a missing target with CreationPolicy=Merge is refreshed.
+	assert.False(t, r.additionalTargetsValid(ctx, logr.Discard(), es))
+	assert.True(t, r.additionalTargetsValid(ctx, logr.Discard(), &esv1.ExternalSecret{
+		ObjectMeta: es.ObjectMeta,
+		Spec:       esv1.ExternalSecretSpec{Targets: es.Spec.Targets[:2]},
+		Status:     esv1.ExternalSecretStatus{Targets: es.Status.Targets[:2]},
+	}))
+	// entries which were not provisioned yet are reconciled.
+	assert.False(t, r.additionalTargetsValid(ctx, logr.Discard(), &esv1.ExternalSecret{
+		ObjectMeta: es.ObjectMeta,
+		Spec:       esv1.ExternalSecretSpec{Targets: es.Spec.Targets[:2]},
+	}))
+
+	// targets with CreationPolicy=Owner are created and owned by the ExternalSecret.
+	var owned v1.Secret
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "owner", Namespace: "default"}, &owned))
+	assert.Equal(t, data, owned.Data)
+	assert.Equal(t, esv1.LabelManagedValue, owned.Labels[esv1.LabelManaged])
+	assert.True(t, metav1.IsControlledBy(&owned, es))
+
+	// targets with CreationPolicy=Merge are only updated if they exist.
+	var merged v1.Secret
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "merge", Namespace: "default"}, &merged))
+	assert.Equal(t, []byte("value"), merged.Data["key"])
+	assert.Equal(t, []byte("kept"), merged.Data["other"])
+	err := fakeClient.Get(ctx, client.ObjectKey{Name: "missing", Namespace: "default"}, &v1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// the additional targets are not orphaned by the primary target.
+	require.NoError(t, r.deleteOrphanedSecrets(ctx, es, targetSecretNames(es)...))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "owner", Namespace: "default"}, &owned))
+	require.NoError(t, r.deleteOrphanedSecrets(ctx, es, "es"))
+	err = fakeClient.Get(ctx, client.ObjectKey{Name: "owner", Namespace: "default"}, &owned)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// without provider data, the deletion policy of every target applies.
+	require.NoError(t, r.reconcileAdditionalTargets(ctx, logr.Discard(), es, data))
+	require.NoError(t, r.reconcileAdditionalTargets(ctx, logr.Discard(), es, nil))
+	err = fakeClient.Get(ctx, client.ObjectKey{Name: "owner", Namespace: "default"}, &owned)
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "merge", Namespace: "default"}, &merged))
+
+	// errors name the target they occurred for.
+	es.Spec.Targets = []esv1.ExternalSecretTarget{{Name: "invalid", CreationPolicy: esv1.CreatePolicyMerge, DeletionPolicy: esv1.DeletionPolicyDelete}}
+	err = r.reconcileAdditionalTargets(ctx, logr.Discard(), es, nil)
+	assert.ErrorContains(t, err, "targets[invalid]: unable to delete secret invalid: creationPolicy=Merge is not Owner")
+}
+
+// fakeInformerManager records the released informers.
+type fakeInformerManager struct {
+	InformerManager
+	released []schema.GroupVersionKind
+}
+
+func (m *fakeInformerManager) ReleaseInformer(_ context.Context, gvk schema.GroupVersionKind, _ types.NamespacedName) error {
+	m.released = append(m.released, gvk)
+	return nil
+}
+
+func TestCleanupRemovedTargets(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "1234"},
+		Spec: esv1.ExternalSecretSpec{
+			Targets: []esv1.ExternalSecretTarget{{Name: "kept", CreationPolicy: esv1.CreatePolicyOwner}},
+		},
+		Status: esv1.ExternalSecretStatus{
+			Targets: []esv1.ExternalSecretTargetStatus{
+				{APIVersion: "v1", Kind: "Secret", Name: "kept", CreationPolicy: esv1.CreatePolicyOwner},
+				{APIVersion: "v1", Kind: "Secret", Name: "owned", CreationPolicy: esv1.CreatePolicyOwner},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "config", CreationPolicy: esv1.CreatePolicyOwner},
+				{APIVersion: "v1", Kind: "Secret", Name: "foreign", CreationPolicy: esv1.CreatePolicyOwner},
+				{APIVersion: "v1", Kind: "Secret", Name: "orphan", CreationPolicy: esv1.CreatePolicyOrphan},
+				{APIVersion: "v1", Kind: "Secret", Name: "failing", CreationPolicy: esv1.CreatePolicyOwner},
+				{APIVersion: "v1", Kind: "Secret", Name: "gone", CreationPolicy: esv1.CreatePolicyOwner},
+			},
+		},
+	}
+	owned := func(obj client.Object) client.Object {
+		obj.SetNamespace("default")
+		obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(es, esv1.SchemeGroupVersion.WithKind(esv1.ExtSecretKind))})
+		return obj
+	}
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		owned(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kept"}}),
+		owned(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "owned"}}),
+		owned(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}}),
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "default"}},
+		owned(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "orphan"}}),
+		owned(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "failing"}}),
+	).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if obj.GetName() == "failing" {
+				return apierrors.NewInternalError(assert.AnError)
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+	}).Build()
+	informers := &fakeInformerManager{}
+	r := &Reconciler{
+		Client:          fakeClient,
+		SecretClient:    fakeClient,
+		Log:             logr.Discard(),
+		Scheme:          scheme,
+		recorder:        record.NewFakeRecorder(10),
+		informerManager: informers,
+	}
+
+	err := r.cleanupRemovedTargets(ctx, logr.Discard(), es)
+	assert.ErrorContains(t, err, "removed target Secret failing")
+
+	// only removed targets owned by the ExternalSecret are deleted.
+	for name, deleted := range map[string]bool{"kept": false, "owned": true, "foreign": false, "orphan": false, "failing": false} {
+		err := fakeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, &v1.Secret{})
+		assert.Equal(t, deleted, apierrors.IsNotFound(err), name)
+	}
+	err = fakeClient.Get(ctx, client.ObjectKey{Name: "config", Namespace: "default"}, &v1.ConfigMap{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, []schema.GroupVersionKind{v1.SchemeGroupVersion.WithKind("ConfigMap")}, informers.released)
+
+	// failed removals are kept, so they are retried.
+	assert.Equal(t, []esv1.ExternalSecretTargetStatus{
+		{APIVersion: "v1", Kind: "Secret", Name: "kept", CreationPolicy: esv1.CreatePolicyOwner},
+		{APIVersion: "v1", Kind: "Secret", Name: "failing", CreationPolicy: esv1.CreatePolicyOwner},
+	}, es.Status.Targets)
+	assert.False(t, r.additionalTargetsValid(ctx, logr.Discard(), es))
+}
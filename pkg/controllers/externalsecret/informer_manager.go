@@ -61,6 +61,9 @@ type InformerManager interface {
 	SetQueue(queue workqueue.TypedRateLimitingInterface[ctrl.Request]) error
 }
 
+// RequestMapper maps a changed resource to the reconcile requests of the objects using it.
+type RequestMapper func(ctx context.Context, gvk schema.GroupVersionKind, obj metav1.Object) []ctrl.Request
+
 // informerEntry tracks an informer and the ExternalSecrets using it.
 type informerEntry struct {
 	informer runtimecache.Informer
@@ -79,16 +82,26 @@ type DefaultInformerManager struct {
 	informers      map[string]*informerEntry // key: GVK string
 	queue          workqueue.TypedRateLimitingInterface[ctrl.Request]
 	managerContext context.Context
+	// mapper maps changed resources to reconcile requests,
+	// it defaults to the ExternalSecrets targeting the resource.
+	mapper RequestMapper
 }
 
 // NewInformerManager creates a new InformerManager.
 func NewInformerManager(ctx context.Context, cache runtimecache.Cache, client client.Client, log logr.Logger) InformerManager {
+	return NewInformerManagerWithMapper(ctx, cache, client, log, nil)
+}
+
+// NewInformerManagerWithMapper creates a new InformerManager which enqueues the requests returned by mapper
+// when a watched resource changes, so other controllers can reuse the informer lifecycle management.
+func NewInformerManagerWithMapper(ctx context.Context, cache runtimecache.Cache, client client.Client, log logr.Logger, mapper RequestMapper) InformerManager {
 	return &DefaultInformerManager{
 		managerContext: ctx,
 		cache:          cache,
 		client:         client,
 		log:            log,
 		informers:      make(map[string]*informerEntry),
+		mapper:         mapper,
 	}
 }
 
@@ -122,10 +135,14 @@ func (m *DefaultInformerManager) EnsureInformer(ctx context.Context, gvk schema.
 	}
 
 	// Add event handler to the informer that enqueues reconcile requests
+	mapper := m.mapper
+	if mapper == nil {
+		mapper = m.targetingExternalSecrets
+	}
 	_, err = informer.AddEventHandler(&enqueueHandler{
 		managerContext: m.managerContext,
 		gvk:            gvk,
-		client:         m.client,
+		mapper:         mapper,
 		queue:          m.queue,
 		log:            m.log,
 	})
@@ -148,12 +165,12 @@ func (m *DefaultInformerManager) EnsureInformer(ctx context.Context, gvk schema.
 	return true, nil
 }
 
-// enqueueHandler is an event handler that enqueues reconcile requests for ExternalSecrets
-// that target the changed resource.
+// enqueueHandler is an event handler that enqueues the reconcile requests
+// returned by its mapper for the changed resource.
 type enqueueHandler struct {
 	managerContext context.Context
 	gvk            schema.GroupVersionKind
-	client         client.Client
+	mapper         RequestMapper
 	queue          workqueue.TypedRateLimitingInterface[ctrl.Request]
 	log            logr.Logger
 }
@@ -178,48 +195,55 @@ func (h *enqueueHandler) enqueue(obj any) {
 		return
 	}
 
+	for _, req := range h.mapper(h.managerContext, h.gvk, meta) {
+		h.queue.Add(req)
+
+		h.log.V(1).Info("enqueued reconcile request due to resource change",
+			"request", req.NamespacedName,
+			"gvk", h.gvk.String(),
+			"resource", meta.GetName())
+	}
+}
+
+// targetingExternalSecrets returns the reconcile requests for the ExternalSecrets that target the resource.
+func (m *DefaultInformerManager) targetingExternalSecrets(ctx context.Context, gvk schema.GroupVersionKind, meta metav1.Object) []ctrl.Request {
 	// Only process resources with the managed label
 	labels := meta.GetLabels()
 	if labels == nil {
-		return
+		return nil
 	}
 
 	value, hasLabel := labels[esv1.LabelManaged]
 	if !hasLabel || value != esv1.LabelManagedValue {
-		return
+		return nil
 	}
 
 	// Find ExternalSecrets that target this resource
 	externalSecretsList := &esv1.ExternalSecretList{}
-	indexValue := fmt.Sprintf("%s/%s/%s/%s", h.gvk.Group, h.gvk.Version, h.gvk.Kind, meta.GetName())
+	indexValue := fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Version, gvk.Kind, meta.GetName())
 	listOps := &client.ListOptions{
 		FieldSelector: fields.OneTermEqualSelector(indexESTargetResourceField, indexValue),
 		Namespace:     meta.GetNamespace(),
 	}
 
-	if err := h.client.List(h.managerContext, externalSecretsList, listOps); err != nil {
-		h.log.Error(err, "failed to list ExternalSecrets for resource",
-			"gvk", h.gvk.String(),
+	if err := m.client.List(ctx, externalSecretsList, listOps); err != nil {
+		m.log.Error(err, "failed to list ExternalSecrets for resource",
+			"gvk", gvk.String(),
 			"name", meta.GetName(),
 			"namespace", meta.GetNamespace())
-		return
+		return nil
 	}
 
-	// Enqueue reconcile requests for each ExternalSecret
+	requests := make([]ctrl.Request, 0, len(externalSecretsList.Items))
 	for i := range externalSecretsList.Items {
-		req := ctrl.Request{
+		requests = append(requests, ctrl.Request{
 			NamespacedName: types.NamespacedName{
 				Name:      externalSecretsList.Items[i].GetName(),
 				Namespace: externalSecretsList.Items[i].GetNamespace(),
 			},
-		}
-		h.queue.Add(req)
-
-		h.log.V(1).Info("enqueued reconcile request due to resource change",
-			"externalSecret", req.NamespacedName,
-			"targetGVK", h.gvk.String(),
-			"targetResource", meta.GetName())
+		})
 	}
+	return requests
 }
 
 // ReleaseInformer unregisters the ExternalSecret from using this GVK.
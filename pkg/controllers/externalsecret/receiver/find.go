@@ -0,0 +1,44 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"regexp"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// matchesFind returns true if a remote key may be found by one of the dataFrom.find of an ExternalSecret.
+// Only the name can be matched, as the tags and paths of a remote key are not part of a notification.
+func matchesFind(es *esv1.ExternalSecret, key string) bool {
+	for _, dataFrom := range es.Spec.DataFrom {
+		if dataFrom.Find == nil || (dataFrom.SourceRef != nil && dataFrom.SourceRef.GeneratorRef != nil) {
+			continue
+		}
+		if dataFrom.Find.Name == nil || dataFrom.Find.Name.RegExp == "" {
+			return true
+		}
+		re, err := regexp.Compile(dataFrom.Find.Name.RegExp)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	snsTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	snsTypeNotification             = "Notification"
+
+	eventGridValidationEvent = "Microsoft.EventGrid.SubscriptionValidationEvent"
+
+	errDecode             = "unable to decode %s notification: %w"
+	errInvalidSubscribe   = "invalid SNS SubscribeURL %q"
+	errConfirmSubscribe   = "unable to confirm SNS subscription: %w"
+	errConfirmSubscribeSC = "unable to confirm SNS subscription: unexpected status code %d"
+)
+
+// GenericNotification is the schema of notifications sent to the generic endpoint.
+type GenericNotification struct {
+	// Keys are the remote keys that changed.
+	Keys []string `json:"keys"`
+}
+
+func parseGeneric(_ context.Context, _ *http.Request, body []byte) ([]string, any, error) {
+	var notification GenericNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, nil, fmt.Errorf(errDecode, SourceGeneric, err)
+	}
+	return notification.Keys, nil, nil
+}
+
+// snsMessage is an Amazon SNS HTTP(S) message.
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// eventBridgeEvent is an Amazon EventBridge event of Secrets Manager (through CloudTrail) or Parameter Store.
+type eventBridgeEvent struct {
+	Source string `json:"source"`
+	Detail struct {
+		// Parameter Store Change.
+		Name string `json:"name"`
+		// AWS API Call via CloudTrail.
+		RequestParameters struct {
+			SecretID string `json:"secretId"`
+			Name     string `json:"name"`
+		} `json:"requestParameters"`
+		ResponseElements struct {
+			ARN string `json:"arn"`
+		} `json:"responseElements"`
+	} `json:"detail"`
+}
+
+// parseSNS parses EventBridge events delivered through SNS, with or without raw message delivery.
+// Subscriptions are confirmed automatically.
+func (r *Receiver) parseSNS(ctx context.Context, _ *http.Request, body []byte) ([]string, any, error) {
+	var msg snsMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, nil, fmt.Errorf(errDecode, SourceAWS, err)
+	}
+	switch msg.Type {
+	case snsTypeSubscriptionConfirmation:
+		if err := r.confirmSubscription(ctx, msg.SubscribeURL); err != nil {
+			return nil, nil, err
+		}
+		return nil, struct{}{}, nil
+	case snsTypeNotification:
+		body = []byte(msg.Message)
+	case "":
+		// raw message delivery
+	default:
+		return nil, nil, nil
+	}
+
+	var event eventBridgeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, nil, fmt.Errorf(errDecode, SourceAWS, err)
+	}
+	var keys []string
+	for _, id := range []string{event.Detail.Name, event.Detail.RequestParameters.SecretID, event.Detail.RequestParameters.Name, event.Detail.ResponseElements.ARN} {
+		if id == "" {
+			continue
+		}
+		keys = append(keys, id)
+		// secrets may be referenced by name or by ARN.
+		if name, ok := secretNameFromARN(id); ok {
+			keys = append(keys, name)
+		}
+	}
+	return keys, nil, nil
+}
+
+// secretNameFromARN returns the name of a Secrets Manager secret from its ARN,
+// removing the random suffix Secrets Manager appends to it.
+func secretNameFromARN(arn string) (string, bool) {
+	_, name, ok := strings.Cut(arn, ":secret:")
+	if !ok || !strings.HasPrefix(arn, "arn:") {
+		return "", false
+	}
+	if idx := strings.LastIndex(name, "-"); idx > 0 && len(name)-idx == 7 {
+		name = name[:idx]
+	}
+	return name, true
+}
+
+// confirmSubscription confirms an SNS subscription, only URLs of SNS are followed.
+func (r *Receiver) confirmSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasPrefix(u.Hostname(), "sns.") ||
+		!(strings.HasSuffix(u.Hostname(), ".amazonaws.com") || strings.HasSuffix(u.Hostname(), ".amazonaws.com.cn")) {
+		return fmt.Errorf(errInvalidSubscribe, subscribeURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf(errConfirmSubscribe, err)
+	}
+	resp, err := r.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf(errConfirmSubscribe, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(errConfirmSubscribeSC, resp.StatusCode)
+	}
+	r.Log.Info("confirmed SNS subscription", "host", u.Hostname())
+	return nil
+}
+
+// pubSubPush is a Google Cloud Pub/Sub push message.
+type pubSubPush struct {
+	Message struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"message"`
+}
+
+// parsePubSub parses Secret Manager notifications delivered by a Pub/Sub push subscription.
+func parsePubSub(_ context.Context, _ *http.Request, body []byte) ([]string, any, error) {
+	var push pubSubPush
+	if err := json.Unmarshal(body, &push); err != nil {
+		return nil, nil, fmt.Errorf(errDecode, SourceGCP, err)
+	}
+	// secretId has the format projects/<project>/secrets/<name>.
+	secretID := push.Message.Attributes["secretId"]
+	if secretID == "" {
+		return nil, nil, nil
+	}
+	keys := []string{secretID}
+	if _, name, ok := strings.Cut(secretID, "/secrets/"); ok {
+		keys = append(keys, name)
+	}
+	return keys, nil, nil
+}
+
+// eventGridEvent is an Azure Event Grid event, in the Event Grid or CloudEvents schema.
+type eventGridEvent struct {
+	EventType string `json:"eventType"`
+	Type      string `json:"type"`
+	Subject   string `json:"subject"`
+	Data      struct {
+		ValidationCode string `json:"validationCode"`
+		ObjectType     string `json:"ObjectType"`
+		ObjectName     string `json:"ObjectName"`
+	} `json:"data"`
+}
+
+// parseEventGrid parses Key Vault events delivered by Event Grid.
+// Subscriptions using the Event Grid schema are validated by replying with the validation code.
+func parseEventGrid(_ context.Context, _ *http.Request, body []byte) ([]string, any, error) {
+	var events []eventGridEvent
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '{' {
+		var event eventGridEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, nil, fmt.Errorf(errDecode, SourceAzure, err)
+		}
+		events = append(events, event)
+	} else if err := json.Unmarshal(body, &events); err != nil {
+		return nil, nil, fmt.Errorf(errDecode, SourceAzure, err)
+	}
+
+	var keys []string
+	for _, event := range events {
+		if event.EventType == eventGridValidationEvent {
+			return nil, map[string]string{"validationResponse": event.Data.ValidationCode}, nil
+		}
+		name := event.Data.ObjectName
+		if name == "" {
+			name = event.Subject
+		}
+		if name == "" {
+			continue
+		}
+		keys = append(keys, name)
+		// the Key Vault provider references keys and certificates with a prefix.
+		switch event.Data.ObjectType {
+		case "Secret":
+			keys = append(keys, "secret/"+name)
+		case "Key":
+			keys = append(keys, "key/"+name)
+		case "Certificate":
+			keys = append(keys, "cert/"+name)
+		}
+	}
+	return keys, nil, nil
+}
+
+// handleCloudEventsValidation answers the abuse protection handshake of CloudEvents webhooks.
+func handleCloudEventsValidation(w http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("WebHook-Request-Origin")
+	if origin == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("WebHook-Allowed-Origin", origin)
+	w.WriteHeader(http.StatusOK)
+}
+
+// vaultEvent is a HashiCorp Vault event notification.
+type vaultEvent struct {
+	Data struct {
+		Event struct {
+			Metadata struct {
+				Path     string `json:"path"`
+				DataPath string `json:"data_path"`
+			} `json:"metadata"`
+		} `json:"event"`
+		PluginInfo struct {
+			MountPath string `json:"mount_path"`
+		} `json:"plugin_info"`
+	} `json:"data"`
+}
+
+// parseVault parses Vault event notifications, e.g. forwarded from `vault events subscribe`.
+func parseVault(_ context.Context, _ *http.Request, body []byte) ([]string, any, error) {
+	var event vaultEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, nil, fmt.Errorf(errDecode, SourceVault, err)
+	}
+	path := event.Data.Event.Metadata.DataPath
+	if path == "" {
+		path = event.Data.Event.Metadata.Path
+	}
+	if path == "" {
+		return nil, nil, errors.New("vault event does not contain a path")
+	}
+
+	// secrets are referenced relative to the mount of the store, or with the mount as prefix.
+	mount := event.Data.PluginInfo.MountPath
+	key := strings.TrimPrefix(path, mount)
+	if key != path {
+		// kv-v2 paths contain the data/ or metadata/ segment.
+		for _, segment := range []string{"data/", "metadata/"} {
+			if trimmed, ok := strings.CutPrefix(key, segment); ok {
+				key = trimmed
+				break
+			}
+		}
+	}
+	keys := []string{key}
+	if mount != "" && key != path {
+		keys = append(keys, mount+key)
+	}
+	return keys, nil, nil
+}
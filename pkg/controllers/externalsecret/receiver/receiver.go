@@ -0,0 +1,303 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package receiver implements an HTTP endpoint which receives change notifications of providers
+// and refreshes the ExternalSecrets referencing the changed secrets.
+package receiver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esindex"
+)
+
+const (
+	// PathPrefix is the path prefix of the notification endpoints, followed by the source of the notification.
+	PathPrefix = "/refresh/"
+
+	// SourceAWS accepts Amazon EventBridge events delivered through Amazon SNS.
+	SourceAWS = "aws"
+	// SourceGCP accepts Google Cloud Pub/Sub push messages of Secret Manager notifications.
+	SourceGCP = "gcp"
+	// SourceAzure accepts Azure Event Grid events of Key Vault, in the Event Grid or CloudEvents schema.
+	SourceAzure = "azure"
+	// SourceVault accepts HashiCorp Vault event notifications.
+	SourceVault = "vault"
+	// SourceGeneric accepts a list of remote keys, see GenericNotification.
+	SourceGeneric = "generic"
+
+	// maxBodySize is the maximum size of a notification.
+	maxBodySize = 1 << 20
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+
+	errNoToken       = "the refresh receiver requires a token"
+	errUnknownSource = "unknown notification source %q"
+	errStoreQuery    = "the namespace of a SecretStore is required"
+	errReadBody      = "unable to read notification: %w"
+	errListES        = "unable to list ExternalSecrets for key %q: %w"
+	errRefreshES     = "unable to refresh ExternalSecret %s: %w"
+)
+
+// parseFunc extracts the remote keys that changed from a notification.
+// If a reply is returned, it is sent to the sender instead of refreshing ExternalSecrets,
+// this is used for the subscription handshakes of the providers.
+type parseFunc func(ctx context.Context, req *http.Request, body []byte) (keys []string, reply any, err error)
+
+// Receiver serves the notification endpoints.
+// It refreshes the ExternalSecrets referencing a changed remote key
+// by setting the esv1.AnnotationRefreshRequested annotation, which triggers a reconcile on any replica of the controller.
+type Receiver struct {
+	Client client.Client
+	Log    logr.Logger
+	// Addr is the address the receiver binds to.
+	Addr string
+	// Token authenticates the senders of notifications.
+	Token string
+	// HTTPClient is used to confirm subscriptions, it defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// SetupWithManager adds the receiver to the manager.
+// ExternalSecrets are looked up with esindex.IndexStoreKeyField, which is registered by the ExternalSecret controller.
+func (r *Receiver) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Token == "" {
+		return errors.New(errNoToken)
+	}
+	return mgr.Add(r)
+}
+
+// NeedLeaderElection returns false, as every replica of the controller can refresh ExternalSecrets.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the notification endpoints until the context is canceled.
+func (r *Receiver) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              r.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		r.Log.Info("starting refresh receiver", "addr", r.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// ServeHTTP handles a notification.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	source, ok := strings.CutPrefix(req.URL.Path, PathPrefix)
+	parse, known := r.parser(source)
+	if !ok || !known {
+		http.Error(w, fmt.Sprintf(errUnknownSource, source), http.StatusNotFound)
+		return
+	}
+	// Event Grid validates CloudEvents webhooks with an OPTIONS request before the subscription is created.
+	if source == SourceAzure && req.Method == http.MethodOptions {
+		handleCloudEventsValidation(w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.authenticate(req) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, fmt.Errorf(errReadBody, err).Error(), http.StatusBadRequest)
+		return
+	}
+	store, err := storeFromQuery(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := req.Context()
+	log := r.Log.WithValues("source", source)
+	keys, reply, err := parse(ctx, req, body)
+	if err != nil {
+		log.Error(err, "unable to parse notification")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if reply != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(reply); err != nil {
+			log.Error(err, "unable to write reply")
+		}
+		return
+	}
+
+	refreshed, err := r.Refresh(ctx, store, keys)
+	if err != nil {
+		log.Error(err, "unable to refresh ExternalSecrets", "keys", keys)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	log.V(1).Info("refreshed ExternalSecrets", "keys", keys, "externalSecrets", refreshed)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authenticate checks the token of the request.
+// The token can be sent as bearer token, as basic auth password or as `token` query parameter,
+// as not every provider is able to set custom headers.
+func (r *Receiver) authenticate(req *http.Request) bool {
+	var token string
+	if value, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		token = value
+	} else if _, password, ok := req.BasicAuth(); ok {
+		token = password
+	} else {
+		token = req.URL.Query().Get("token")
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(r.Token)) == 1
+}
+
+// storeFromQuery returns the store a notification is scoped to, set with the query parameters kind, namespace and store.
+// Without the store parameter, the notification applies to the remote keys of every store.
+func storeFromQuery(req *http.Request) (esindex.StoreRef, error) {
+	params := req.URL.Query()
+	if params.Get("store") == "" {
+		return esindex.StoreRef{}, nil
+	}
+	if params.Get("kind") == esv1.ClusterSecretStoreKind {
+		return esindex.StoreRef{Kind: esv1.ClusterSecretStoreKind, Name: params.Get("store")}, nil
+	}
+	if params.Get("namespace") == "" {
+		return esindex.StoreRef{}, errors.New(errStoreQuery)
+	}
+	return esindex.StoreRef{Kind: esv1.SecretStoreKind, Namespace: params.Get("namespace"), Name: params.Get("store")}, nil
+}
+
+// Refresh requests a refresh of all ExternalSecrets referencing one of the remote keys of a store,
+// and returns the refreshed ExternalSecrets. A zero store matches the remote keys of every store.
+func (r *Receiver) Refresh(ctx context.Context, store esindex.StoreRef, keys []string) ([]types.NamespacedName, error) {
+	targets := make(map[types.NamespacedName]struct{})
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		list, err := esindex.ListByKey(ctx, r.Client, store, key)
+		if err != nil {
+			return nil, fmt.Errorf(errListES, key, err)
+		}
+		for i := range list {
+			targets[client.ObjectKeyFromObject(&list[i])] = struct{}{}
+		}
+	}
+
+	// ExternalSecrets using dataFrom.find can not be indexed by key, they are matched one by one.
+	findList, err := esindex.ListByKey(ctx, r.Client, store, esindex.AnyKey)
+	if err != nil {
+		return nil, fmt.Errorf(errListES, esindex.AnyKey, err)
+	}
+	for i := range findList {
+		for _, key := range keys {
+			if key != "" && matchesFind(&findList[i], key) {
+				targets[client.ObjectKeyFromObject(&findList[i])] = struct{}{}
+				break
+			}
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	refreshed := make([]types.NamespacedName, 0, len(targets))
+	var errs []error
+	for target := range targets {
+		if err := r.requestRefresh(ctx, target, now); err != nil {
+			errs = append(errs, fmt.Errorf(errRefreshES, target, err))
+			continue
+		}
+		refreshed = append(refreshed, target)
+	}
+	return refreshed, errors.Join(errs...)
+}
+
+// requestRefresh sets the esv1.AnnotationRefreshRequested annotation of an ExternalSecret,
+// which changes its metadata and thus makes the controller refresh it.
+func (r *Receiver) requestRefresh(ctx context.Context, target types.NamespacedName, now string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				esv1.AnnotationRefreshRequested: now,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	es := &esv1.ExternalSecret{}
+	es.SetName(target.Name)
+	es.SetNamespace(target.Namespace)
+	return client.IgnoreNotFound(r.Client.Patch(ctx, es, client.RawPatch(types.MergePatchType, patch)))
+}
+
+func (r *Receiver) parser(source string) (parseFunc, bool) {
+	switch source {
+	case SourceAWS:
+		return r.parseSNS, true
+	case SourceGCP:
+		return parsePubSub, true
+	case SourceAzure:
+		return parseEventGrid, true
+	case SourceVault:
+		return parseVault, true
+	case SourceGeneric:
+		return parseGeneric, true
+	default:
+		return nil, false
+	}
+}
+
+func (r *Receiver) httpClient() *http.Client {
+	if r.HTTPClient != nil {
+		return r.HTTPClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,230 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esindex"
+)
+
+const testToken = "secret-token"
+
+func TestParsers(t *testing.T) {
+	r := &Receiver{Log: logr.Discard()}
+	tests := []struct {
+		name      string
+		source    string
+		body      string
+		wantKeys  []string
+		wantReply any
+		wantErr   bool
+	}{
+		{
+			name:     "generic",
+			source:   SourceGeneric,
+			body:     `{"keys": ["a", "b"]}`,
+			wantKeys: []string{"a", "b"},
+		},
+		{
+			name:    "generic invalid",
+			source:  SourceGeneric,
+			body:    `not json`,
+			wantErr: true,
+		},
+		{
+			name:   "sns notification of secrets manager",
+			source: SourceAWS,
+			body: `{"Type": "Notification", "Message": ` +
+				`"{\"source\":\"aws.secretsmanager\",\"detail\":{\"requestParameters\":{\"secretId\":\"arn:aws:secretsmanager:eu-west-1:123456789012:secret:db/password-AbCdEf\"}}}"}`,
+			wantKeys: []string{"arn:aws:secretsmanager:eu-west-1:123456789012:secret:db/password-AbCdEf", "db/password"},
+		},
+		{
+			name:     "raw parameter store event",
+			source:   SourceAWS,
+			body:     `{"source": "aws.ssm", "detail": {"name": "/app/param", "operation": "Update"}}`,
+			wantKeys: []string{"/app/param"},
+		},
+		{
+			name:    "sns subscription of a foreign host",
+			source:  SourceAWS,
+			body:    `{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://example.com/?Action=ConfirmSubscription"}`,
+			wantErr: true,
+		},
+		{
+			name:     "pubsub push",
+			source:   SourceGCP,
+			body:     `{"message": {"attributes": {"eventType": "SECRET_VERSION_ADD", "secretId": "projects/my-project/secrets/db-password"}}}`,
+			wantKeys: []string{"projects/my-project/secrets/db-password", "db-password"},
+		},
+		{
+			name:     "event grid",
+			source:   SourceAzure,
+			body:     `[{"eventType": "Microsoft.KeyVault.SecretNewVersionCreated", "subject": "db-password", "data": {"ObjectType": "Secret", "ObjectName": "db-password"}}]`,
+			wantKeys: []string{"db-password", "secret/db-password"},
+		},
+		{
+			name:     "cloud event of event grid",
+			source:   SourceAzure,
+			body:     `{"type": "Microsoft.KeyVault.CertificateNewVersionCreated", "subject": "tls", "data": {"ObjectType": "Certificate", "ObjectName": "tls"}}`,
+			wantKeys: []string{"tls", "cert/tls"},
+		},
+		{
+			name:      "event grid subscription validation",
+			source:    SourceAzure,
+			body:      `[{"eventType": "Microsoft.EventGrid.SubscriptionValidationEvent", "data": {"validationCode": "1234"}}]`,
+			wantReply: map[string]string{"validationResponse": "1234"},
+		},
+		{
+			name:     "vault kv-v2",
+			source:   SourceVault,
+			body:     `{"data": {"event": {"metadata": {"data_path": "secret/data/app/db", "path": "secret/data/app/db"}}, "plugin_info": {"mount_path": "secret/"}}}`,
+			wantKeys: []string{"app/db", "secret/app/db"},
+		},
+		{
+			name:    "vault without path",
+			source:  SourceVault,
+			body:    `{"data": {}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse, ok := r.parser(tt.source)
+			require.True(t, ok)
+			keys, reply, err := parse(context.Background(), nil, []byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKeys, keys)
+			assert.Equal(t, tt.wantReply, reply)
+		})
+	}
+}
+
+func TestMatchesFind(t *testing.T) {
+	es := &esv1.ExternalSecret{
+		Spec: esv1.ExternalSecretSpec{
+			DataFrom: []esv1.ExternalSecretDataFromRemoteRef{
+				{Extract: &esv1.ExternalSecretDataRemoteRef{Key: "c"}},
+				{Find: &esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "^db-.*"}}},
+				{Find: &esv1.ExternalSecretFind{}, SourceRef: &esv1.StoreGeneratorSourceRef{GeneratorRef: &esv1.GeneratorRef{Name: "gen"}}},
+			},
+		},
+	}
+	assert.True(t, matchesFind(es, "db-password"))
+	assert.False(t, matchesFind(es, "api-key"))
+}
+
+func TestServeHTTP(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+
+	newES := func(name string, spec esv1.ExternalSecretSpec) *esv1.ExternalSecret {
+		if spec.SecretStoreRef.Name == "" {
+			spec.SecretStoreRef = esv1.SecretStoreRef{Name: "vault"}
+		}
+		return &esv1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Spec: spec}
+	}
+	byKey := newES("by-key", esv1.ExternalSecretSpec{
+		Data: []esv1.ExternalSecretData{{SecretKey: "password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db/password"}}},
+	})
+	byFind := newES("by-find", esv1.ExternalSecretSpec{
+		DataFrom: []esv1.ExternalSecretDataFromRemoteRef{{Find: &esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "^db/"}}}},
+	})
+	other := newES("other", esv1.ExternalSecretSpec{
+		Data: []esv1.ExternalSecretData{{SecretKey: "key", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "api/key"}}},
+	})
+	otherStore := newES("other-store", esv1.ExternalSecretSpec{
+		SecretStoreRef: esv1.SecretStoreRef{Name: "aws", Kind: esv1.ClusterSecretStoreKind},
+		Data:           []esv1.ExternalSecretData{{SecretKey: "password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db/password"}}},
+	})
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(byKey, byFind, other, otherStore).
+		WithIndex(&esv1.ExternalSecret{}, esindex.IndexStoreKeyField, esindex.IndexStoreKeys).
+		Build()
+	r := &Receiver{Client: kube, Log: logr.Discard(), Token: testToken}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		header     map[string]string
+		wantStatus int
+	}{
+		{name: "unknown source", method: http.MethodPost, path: "/refresh/unknown", token: testToken, wantStatus: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodGet, path: "/refresh/generic", token: testToken, wantStatus: http.StatusMethodNotAllowed},
+		{name: "missing token", method: http.MethodPost, path: "/refresh/generic", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, path: "/refresh/generic", token: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "token as query parameter", method: http.MethodPost, path: "/refresh/generic?token=" + testToken, wantStatus: http.StatusAccepted},
+		{name: "store without namespace", method: http.MethodPost, path: "/refresh/generic?store=vault", token: testToken, wantStatus: http.StatusBadRequest},
+		{name: "bearer token scoped to a store", method: http.MethodPost, path: "/refresh/generic?store=vault&namespace=default", token: testToken, wantStatus: http.StatusAccepted},
+		{
+			name:       "cloud events validation",
+			method:     http.MethodOptions,
+			path:       "/refresh/azure",
+			header:     map[string]string{"WebHook-Request-Origin": "eventgrid.azure.net"},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"keys": ["db/password"]}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+
+	// only the ExternalSecrets referencing the key are refreshed.
+	for name, want := range map[string]bool{"by-key": true, "by-find": true, "other": false, "other-store": true} {
+		var es esv1.ExternalSecret
+		require.NoError(t, kube.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &es))
+		_, refreshed := es.Annotations[esv1.AnnotationRefreshRequested]
+		assert.Equal(t, want, refreshed, name)
+	}
+
+	// a notification scoped to a store does not refresh the ExternalSecrets of other stores.
+	refreshed, err := r.Refresh(context.Background(), esindex.StoreRef{Kind: esv1.SecretStoreKind, Namespace: "default", Name: "vault"}, []string{"db/password"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []types.NamespacedName{{Namespace: "default", Name: "by-key"}, {Namespace: "default", Name: "by-find"}}, refreshed)
+}
@@ -103,15 +103,18 @@ func (r *Reconciler) handleFinalizer(ctx context.Context, generatorState *genv1a
 			return true, nil
 		}
 	} else if controllerutil.ContainsFinalizer(generatorState, generatorStateFinalizer) {
-		gen, err := r.getGenerator(generatorState.Spec.Resource.Raw)
-		if err != nil {
-			r.markAsFailed("could not get generator", err, generatorState)
-			return false, fmt.Errorf("could not get generator: %w", err)
-		}
+		// a GeneratorState without a state only records the expiry, there is nothing to clean up.
+		if generatorState.Spec.State != nil {
+			gen, err := r.getGenerator(generatorState.Spec.Resource.Raw)
+			if err != nil {
+				r.markAsFailed("could not get generator", err, generatorState)
+				return false, fmt.Errorf("could not get generator: %w", err)
+			}
 
-		if err := gen.Cleanup(ctx, generatorState.Spec.Resource, generatorState.Spec.State, r.Client, generatorState.Namespace); err != nil {
-			r.markAsFailed("could not cleanup generator state", err, generatorState)
-			return false, fmt.Errorf("could not cleanup generator state: %w", err)
+			if err := gen.Cleanup(ctx, generatorState.Spec.Resource, generatorState.Spec.State, r.Client, generatorState.Namespace); err != nil {
+				r.markAsFailed("could not cleanup generator state", err, generatorState)
+				return false, fmt.Errorf("could not cleanup generator state: %w", err)
+			}
 		}
 
 		controllerutil.RemoveFinalizer(generatorState, generatorStateFinalizer)
@@ -37,8 +37,13 @@ const (
 
 	// PushSecretStatusConditionKey is the key for the status condition metric.
 	PushSecretStatusConditionKey = "status_condition"
+
+	// PushSecretDriftDetectedKey is the key for the drift detection metric.
+	PushSecretDriftDetectedKey = "drift_detected_total"
 )
 
+var counterVecMetrics = map[string]*prometheus.CounterVec{}
+
 var gaugeVecMetrics = map[string]*prometheus.GaugeVec{}
 
 // SetUpMetrics is called at the root to set-up the metric logic using the
@@ -56,7 +61,17 @@ func SetUpMetrics() {
 		Help:      "The duration time to reconcile the Push Secret",
 	}, ctrlmetrics.NonConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(pushSecretReconcileDuration, pushSecretCondition)
+	pushSecretDriftDetected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: PushSecretSubsystem,
+		Name:      PushSecretDriftDetectedKey,
+		Help:      "Total number of secrets found to be changed in the provider since they were pushed",
+	}, ctrlmetrics.NonConditionMetricLabelNames)
+
+	metrics.Registry.MustRegister(pushSecretReconcileDuration, pushSecretCondition, pushSecretDriftDetected)
+
+	counterVecMetrics = map[string]*prometheus.CounterVec{
+		PushSecretDriftDetectedKey: pushSecretDriftDetected,
+	}
 
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
 		PushSecretStatusConditionKey:   pushSecretCondition,
@@ -128,6 +143,18 @@ func UpdatePushSecretCondition(ps *esapi.PushSecret, condition *esapi.PushSecret
 		})).Set(value)
 }
 
+// ObserveDrift counts the secrets of a PushSecret which were changed in the provider.
+func ObserveDrift(ps *esapi.PushSecret, drifted int) {
+	psInfo := map[string]string{"name": ps.Name, "namespace": ps.Namespace}
+	maps.Copy(psInfo, ps.Labels)
+	GetCounterVec(PushSecretDriftDetectedKey).With(ctrlmetrics.RefineNonConditionMetricLabels(psInfo)).Add(float64(drifted))
+}
+
+// GetCounterVec returns a CounterVec for the given metric key.
+func GetCounterVec(key string) *prometheus.CounterVec {
+	return counterVecMetrics[key]
+}
+
 // GetGaugeVec returns a GaugeVec for the given metric key.
 func GetGaugeVec(key string) *prometheus.GaugeVec {
 	return gaugeVecMetrics[key]
@@ -26,6 +26,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -33,8 +34,10 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -49,6 +52,7 @@ import (
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret/psmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
@@ -87,6 +91,14 @@ type Reconciler struct {
 	RestConfig      *rest.Config
 	RequeueInterval time.Duration
 	ControllerClass string
+	// AllowGenericSources allows pushing values from arbitrary resources.
+	AllowGenericSources bool
+
+	// informerManager watches the source ConfigMaps and resources, so changes trigger a push.
+	informerManager externalsecret.InformerManager
+	// sources tracks the GVK of the source resource of each PushSecret to release its informer.
+	sources   map[types.NamespacedName]schema.GroupVersionKind
+	sourcesMu sync.Mutex
 }
 
 // storeInfo holds the identifying attributes of a secret store for per-store processing.
@@ -117,6 +129,12 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		for storeKey := range ps.Status.SyncedPushSecrets {
 			storeKeys = append(storeKeys, storeKey)
 		}
+		// stores with pending deletions are still needed to delete the secrets
+		for _, deletion := range ps.Status.PendingDeletions {
+			if !slices.Contains(storeKeys, deletion.StoreRef) {
+				storeKeys = append(storeKeys, deletion.StoreRef)
+			}
+		}
 		return storeKeys
 	}); err != nil {
 		return err
@@ -130,9 +148,18 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
+	// Index PushSecrets by their source ConfigMap or resource, so changes of the source trigger a push
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &esapi.PushSecret{}, indexPSSourceResourceField, indexSourceResource); err != nil {
+		return err
+	}
+	if r.informerManager == nil {
+		r.informerManager = externalsecret.NewInformerManagerWithMapper(ctx, mgr.GetCache(), r.Client, r.Log.WithName("informer-manager"), r.pushSecretsForSource)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&esapi.PushSecret{}, builder.WithPredicates(pushSecretWatchPredicate())).
+		WatchesRawSource(r.informerManager.Source()).
 		Complete(r)
 }
 
@@ -191,6 +218,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	if err := r.Get(ctx, req.NamespacedName, &ps); err != nil {
 		if apierrors.IsNotFound(err) {
+			if err := r.releaseSource(ctx, req.NamespacedName); err != nil {
+				log.Error(err, "failed to release informer for source resource")
+			}
 			return ctrl.Result{}, nil
 		}
 
@@ -213,6 +243,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			log.Error(err, errPatchStatus)
 		}
 	}()
+	if err := r.trackSource(ctx, &ps); err != nil {
+		r.markAsFailed(err.Error(), &ps, nil)
+		return ctrl.Result{}, err
+	}
+
 	switch ps.Spec.DeletionPolicy {
 	case esapi.PushSecretDeletionPolicyDelete:
 		// finalizer logic. Only added if we should delete the secrets
@@ -231,6 +266,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				r.markAsFailed(msg, &ps, badState)
 				return ctrl.Result{}, err
 			}
+			// the finalizer is kept until the grace period of all pending deletions expired.
+			if len(ps.Status.PendingDeletions) > 0 {
+				r.setSecrets(&ps, badState)
+				return ctrl.Result{RequeueAfter: requeueForPendingDeletions(&ps, 0)}, nil
+			}
 			controllerutil.RemoveFinalizer(&ps, pushSecretFinalizer)
 			if err := r.Client.Update(ctx, &ps, &client.UpdateOptions{}); err != nil {
 				return ctrl.Result{}, fmt.Errorf("could not update finalizers: %w", err)
@@ -239,6 +279,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, nil
 		}
 	case esapi.PushSecretDeletionPolicyNone:
+		// secrets are no longer deleted, pending deletions are dropped.
+		ps.Status.PendingDeletions = nil
 		if controllerutil.ContainsFinalizer(&ps, pushSecretFinalizer) {
 			controllerutil.RemoveFinalizer(&ps, pushSecretFinalizer)
 			if err := r.Client.Update(ctx, &ps, &client.UpdateOptions{}); err != nil {
@@ -252,10 +294,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if !ps.Status.RefreshTime.IsZero() {
 		timeSinceLastRefresh = time.Since(ps.Status.RefreshTime.Time)
 	}
-	if !shouldRefresh(ps) {
+	// the source ConfigMap or resource is fetched once, a deleted source is handled by the push.
+	source, sourceErr := r.fetchSourceResource(ctx, &ps)
+	if errors.Is(sourceErr, errSourcesDisabled) {
+		r.markAsFailed(sourceErr.Error(), &ps, nil)
+		return ctrl.Result{}, nil // don't requeue as this is a configuration error that is not recoverable
+	}
+	if sourceErr != nil && !apierrors.IsNotFound(sourceErr) {
+		r.markAsFailed(errFailedGetSecret, &ps, nil)
+		return ctrl.Result{}, sourceErr
+	}
+	if !shouldRefresh(ps) && !pendingDeletionDue(&ps) && !sourceChanged(&ps, source) {
 		refreshInt = (ps.Spec.RefreshInterval.Duration - timeSinceLastRefresh) + 5*time.Second
 		log.V(1).Info("skipping refresh", "rv", ctrlutil.GetResourceVersion(ps.ObjectMeta), "nr", refreshInt.Seconds())
-		return ctrl.Result{RequeueAfter: refreshInt}, nil
+		return ctrl.Result{RequeueAfter: requeueForPendingDeletions(&ps, refreshInt)}, nil
 	}
 
 	if err := validateDataToStoreRefs(ps.Spec.DataTo, ps.Spec.SecretStoreRefs); err != nil {
@@ -263,13 +315,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
-	secrets, err := r.resolveSecrets(ctx, &ps)
+	var secrets []v1.Secret
+	err := sourceErr
+	if err == nil {
+		secrets, err = r.resolveSecrets(ctx, &ps, source)
+	}
 	if err != nil {
-		isSecretSelector := ps.Spec.Selector.Secret != nil && ps.Spec.Selector.Secret.Name != ""
+		_, _, isSourceResource := sourceResource(&ps)
+		isSecretSelector := (ps.Spec.Selector.Secret != nil && ps.Spec.Selector.Secret.Name != "") || isSourceResource
 		if apierrors.IsNotFound(err) && isSecretSelector &&
 			ps.Spec.DeletionPolicy == esapi.PushSecretDeletionPolicyDelete &&
-			len(ps.Status.SyncedPushSecrets) > 0 {
-			return ctrl.Result{}, r.handleSourceSecretDeleted(ctx, &ps, mgr)
+			(len(ps.Status.SyncedPushSecrets) > 0 || len(ps.Status.PendingDeletions) > 0) {
+			if err := r.handleSourceSecretDeleted(ctx, &ps, mgr); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: requeueForPendingDeletions(&ps, 0)}, nil
 		}
 		r.markAsFailed(errFailedGetSecret, &ps, nil)
 		return ctrl.Result{}, err
@@ -308,19 +368,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	allSyncedSecrets := make(esapi.SyncedPushSecretsMap)
+	drift := newDriftState(&ps)
+	tx := newPushTransaction(&ps)
 	for _, secret := range secrets {
 		if err := r.applyTemplate(ctx, &ps, &secret); err != nil {
 			return ctrl.Result{}, err
 		}
 
-		syncedSecrets, err := r.PushSecretToProviders(ctx, secretStores, ps, &secret, mgr)
+		syncedSecrets, err := r.PushSecretToProviders(ctx, secretStores, ps, &secret, mgr, drift, tx)
 		if err != nil {
+			if tx.enabled() {
+				return r.abortPush(ctx, &ps, tx, drift, err, mergeSecretState(syncedSecrets, allSyncedSecrets), refreshInt)
+			}
 			if errors.Is(err, locks.ErrConflict) {
 				log.Info("retry to acquire lock to update the secret later", "error", err)
 				return ctrl.Result{Requeue: true}, nil
 			}
 
+			// the hashes of the secrets pushed so far are kept, so they are not reported as drifted.
 			totalSecrets := mergeSecretState(syncedSecrets, ps.Status.SyncedPushSecrets)
+			ps.Status.SyncedPushSecretHashes = drift.syncedHashes(totalSecrets)
+
+			// drifted secrets are not retried before the next refresh.
+			if errors.Is(err, errDrifted) {
+				r.markAsDrifted(err.Error(), &ps, drift, totalSecrets)
+				return ctrl.Result{RequeueAfter: refreshInt}, nil
+			}
+
 			msg := fmt.Sprintf(errFailedSetSecret, err)
 			r.markAsFailed(msg, &ps, totalSecrets)
 
@@ -331,6 +405,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			badSyncState, err := r.DeleteSecretFromProviders(ctx, &ps, syncedSecrets, mgr)
 			if err != nil {
 				msg := fmt.Sprintf("Failed to Delete Secrets from Provider: %v", err)
+				ps.Status.SyncedPushSecretHashes = drift.syncedHashes(badSyncState)
 				r.markAsFailed(msg, &ps, badSyncState)
 				return ctrl.Result{}, err
 			}
@@ -341,9 +416,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		allSyncedSecrets = mergeSecretState(allSyncedSecrets, syncedSecrets)
 	}
 
-	r.markAsDone(&ps, allSyncedSecrets, start)
+	if _, _, ok := sourceResource(&ps); ok && len(secrets) > 0 {
+		ps.Status.SyncedSourceResourceVersion = secrets[0].ResourceVersion
+	}
+	r.markAsDone(&ps, allSyncedSecrets, drift, start)
 
-	return ctrl.Result{RequeueAfter: refreshInt}, nil
+	return ctrl.Result{RequeueAfter: requeueForPendingDeletions(&ps, refreshInt)}, nil
 }
 
 // handleSourceSecretDeleted cleans up provider secrets when source Secret is unavailable.
@@ -359,6 +437,7 @@ func (r *Reconciler) handleSourceSecretDeleted(ctx context.Context, ps *esapi.Pu
 	}
 
 	r.setSecrets(ps, esapi.SyncedPushSecretsMap{})
+	ps.Status.SyncedPushSecretHashes = nil
 	r.markAsSourceDeleted(ps)
 	return nil
 }
@@ -392,7 +471,14 @@ func (r *Reconciler) markAsSourceDeleted(ps *esapi.PushSecret) {
 	r.recorder.Event(ps, v1.EventTypeNormal, esapi.ReasonSourceDeleted, msg)
 }
 
-func (r *Reconciler) markAsDone(ps *esapi.PushSecret, secrets esapi.SyncedPushSecretsMap, start time.Time) {
+func (r *Reconciler) markAsDrifted(msg string, ps *esapi.PushSecret, drift *driftState, syncState esapi.SyncedPushSecretsMap) {
+	r.markDrift(ps, drift)
+	cond := NewPushSecretCondition(esapi.PushSecretReady, v1.ConditionFalse, esapi.ReasonDrifted, msg)
+	SetPushSecretCondition(ps, *cond)
+	r.setSecrets(ps, syncState)
+}
+
+func (r *Reconciler) markAsDone(ps *esapi.PushSecret, secrets esapi.SyncedPushSecretsMap, drift *driftState, start time.Time) {
 	msg := "PushSecret synced successfully"
 	if ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
 		msg += ". Existing secrets in providers unchanged."
@@ -400,6 +486,8 @@ func (r *Reconciler) markAsDone(ps *esapi.PushSecret, secrets esapi.SyncedPushSe
 	cond := NewPushSecretCondition(esapi.PushSecretReady, v1.ConditionTrue, esapi.ReasonSynced, msg)
 	SetPushSecretCondition(ps, *cond)
 	r.setSecrets(ps, secrets)
+	ps.Status.SyncedPushSecretHashes = drift.syncedHashes(secrets)
+	r.markDrift(ps, drift)
 	ps.Status.RefreshTime = metav1.NewTime(start)
 	ps.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(ps.ObjectMeta)
 	r.recorder.Event(ps, v1.EventTypeNormal, esapi.ReasonSynced, msg)
@@ -428,20 +516,37 @@ func mergeSecretState(newMap, old esapi.SyncedPushSecretsMap) esapi.SyncedPushSe
 // DeleteSecretFromProviders removes secrets from providers that are no longer needed.
 // It compares the existing synced secrets in the PushSecret status with the new desired state,
 // and deletes any secrets that are no longer present in the new state.
+// With a deletion grace period, the secrets are recorded as pending deletions instead,
+// they are deleted once the period expired and dropped if they are present in the new state again.
 func (r *Reconciler) DeleteSecretFromProviders(ctx context.Context, ps *esapi.PushSecret, newMap esapi.SyncedPushSecretsMap, mgr *secretstore.Manager) (esapi.SyncedPushSecretsMap, error) {
 	out := mergeSecretState(newMap, ps.Status.SyncedPushSecrets)
+	ps.Status.PendingDeletions = cancelPendingDeletions(ps.Status.PendingDeletions, newMap)
 	for storeName, oldData := range ps.Status.SyncedPushSecrets {
-		storeRef := esv1.SecretStoreRef{
-			Name: strings.Split(storeName, "/")[1],
-			Kind: strings.Split(storeName, "/")[0],
+		newData, ok := newMap[storeName]
+		if ps.Spec.DeletionGracePeriod != nil && ps.Spec.DeletionGracePeriod.Duration > 0 {
+			deleteAfter := metav1.NewTime(time.Now().Add(ps.Spec.DeletionGracePeriod.Duration))
+			for oldEntry, oldRef := range oldData {
+				if _, ok := newData[oldEntry]; !ok {
+					ps.Status.PendingDeletions = addPendingDeletion(ps.Status.PendingDeletions, esapi.PushSecretPendingDeletion{
+						StoreRef:    storeName,
+						Key:         oldEntry,
+						Data:        oldRef,
+						DeleteAfter: deleteAfter,
+					})
+					delete(out[storeName], oldEntry)
+				}
+			}
+			if !ok {
+				delete(out, storeName)
+			}
+			continue
 		}
-		client, err := mgr.Get(ctx, storeRef, ps.Namespace, nil)
+		client, err := mgr.Get(ctx, storeRefFromKey(storeName), ps.Namespace, nil)
 		if err != nil {
 			return out, fmt.Errorf("could not get secrets client for store %v: %w", storeName, err)
 		}
-		newData, ok := newMap[storeName]
 		if !ok {
-			err = r.DeleteAllSecretsFromStore(ctx, client, oldData)
+			err = r.DeleteAllSecretsFromStore(ctx, client, oldData, ps.Spec.DeletionMode)
 			if err != nil {
 				return out, err
 			}
@@ -451,7 +556,7 @@ func (r *Reconciler) DeleteSecretFromProviders(ctx context.Context, ps *esapi.Pu
 		for oldEntry, oldRef := range oldData {
 			_, ok := newData[oldEntry]
 			if !ok {
-				err = r.DeleteSecretFromStore(ctx, client, oldRef)
+				err = r.DeleteSecretFromStore(ctx, client, oldRef, ps.Spec.DeletionMode)
 				if err != nil {
 					return out, err
 				}
@@ -459,13 +564,13 @@ func (r *Reconciler) DeleteSecretFromProviders(ctx context.Context, ps *esapi.Pu
 			}
 		}
 	}
-	return out, nil
+	return out, r.deleteExpiredPendingDeletions(ctx, ps, mgr)
 }
 
 // DeleteAllSecretsFromStore removes all secrets from a given secret store.
-func (r *Reconciler) DeleteAllSecretsFromStore(ctx context.Context, client esv1.SecretsClient, data map[string]esapi.PushSecretData) error {
+func (r *Reconciler) DeleteAllSecretsFromStore(ctx context.Context, client esv1.SecretsClient, data map[string]esapi.PushSecretData, mode esapi.PushSecretDeletionMode) error {
 	for _, v := range data {
-		err := r.DeleteSecretFromStore(ctx, client, v)
+		err := r.DeleteSecretFromStore(ctx, client, v, mode)
 		if err != nil {
 			return err
 		}
@@ -474,10 +579,21 @@ func (r *Reconciler) DeleteAllSecretsFromStore(ctx context.Context, client esv1.
 }
 
 // DeleteSecretFromStore removes a specific secret from a given secret store.
-func (r *Reconciler) DeleteSecretFromStore(ctx context.Context, client esv1.SecretsClient, data esapi.PushSecretData) error {
+func (r *Reconciler) DeleteSecretFromStore(ctx context.Context, client esv1.SecretsClient, data esapi.PushSecretData, mode esapi.PushSecretDeletionMode) error {
+	if mode == esapi.PushSecretDeletionModeSoft {
+		return esv1.SoftDeleteSecret(ctx, client, data.Match.RemoteRef)
+	}
 	return client.DeleteSecret(ctx, data.Match.RemoteRef)
 }
 
+// storeRefFromKey returns the store reference of a key of the synced push secrets in the form <kind>/<name>.
+func storeRefFromKey(storeName string) esv1.SecretStoreRef {
+	return esv1.SecretStoreRef{
+		Name: strings.Split(storeName, "/")[1],
+		Kind: strings.Split(storeName, "/")[0],
+	}
+}
+
 // PushSecretToProviders pushes the secret data to the specified secret stores.
 // It iterates over each store and handles the push operation according to the
 // defined update policies and conversion strategies.
@@ -487,12 +603,14 @@ func (r *Reconciler) PushSecretToProviders(
 	ps esapi.PushSecret,
 	secret *v1.Secret,
 	mgr *secretstore.Manager,
+	drift *driftState,
+	tx *pushTransaction,
 ) (esapi.SyncedPushSecretsMap, error) {
 	out := make(esapi.SyncedPushSecretsMap)
 	var err error
 	for ref, store := range stores {
 		si := storeInfo{Name: store.GetName(), Kind: ref.Kind, Labels: store.GetLabels()}
-		out, err = r.handlePushSecretDataForStore(ctx, ps, secret, out, mgr, si)
+		out, err = r.handlePushSecretDataForStore(ctx, ps, secret, out, mgr, si, drift, tx)
 		if err != nil {
 			return out, err
 		}
@@ -507,6 +625,8 @@ func (r *Reconciler) handlePushSecretDataForStore(
 	out esapi.SyncedPushSecretsMap,
 	mgr *secretstore.Manager,
 	si storeInfo,
+	drift *driftState,
+	tx *pushTransaction,
 ) (esapi.SyncedPushSecretsMap, error) {
 	storeKey := fmt.Sprintf("%v/%v", si.Kind, si.Name)
 	out[storeKey] = make(map[string]esapi.PushSecretData)
@@ -538,7 +658,22 @@ func (r *Reconciler) handlePushSecretDataForStore(
 
 	originalStoreSecretData := storeSecret.Data
 
+	// with a drift mode, secrets changed in the provider since the last push are not overwritten.
+	if drift.enabled() {
+		drifted, err := drift.detect(ctx, secretClient, storeKey, allData)
+		if err != nil {
+			return out, err
+		}
+		if len(drifted) > 0 && drift.mode == esapi.PushSecretDriftModeFail {
+			return out, fmt.Errorf("%w: %s", errDrifted, strings.Join(drifted, ", "))
+		}
+	}
+
 	for _, data := range allData {
+		if drift.isDrifted(storeKey, statusRef(data)) {
+			out[storeKey][statusRef(data)] = data
+			continue
+		}
 		params := pushEntryParams{
 			data:         data,
 			updatePolicy: ps.Spec.UpdatePolicy,
@@ -546,10 +681,15 @@ func (r *Reconciler) handlePushSecretDataForStore(
 			dataOverride: bundleOverrides[statusRef(data)],
 			storeName:    si.Name,
 		}
+		w := tx.snapshot(ctx, secretClient, storeKey, data)
 		if err := r.pushSecretEntry(ctx, secretClient, storeSecret, params); err != nil {
 			return out, err
 		}
+		tx.record(w)
 		out[storeKey][statusRef(data)] = data
+		if drift.enabled() {
+			r.recordRemoteHash(ctx, drift, secretClient, storeKey, data)
+		}
 	}
 	return out, nil
 }
@@ -614,7 +754,9 @@ func secretKeyExists(key string, data map[string][]byte) bool {
 
 const defaultGeneratorStateKey = "__pushsecret"
 
-func (r *Reconciler) resolveSecrets(ctx context.Context, ps *esapi.PushSecret) ([]v1.Secret, error) {
+// resolveSecrets returns the Secrets to push.
+// The source ConfigMap or resource is passed in, as it was already fetched to detect changes.
+func (r *Reconciler) resolveSecrets(ctx context.Context, ps *esapi.PushSecret, source *unstructured.Unstructured) ([]v1.Secret, error) {
 	var err error
 	generatorState := statemanager.New(ctx, r.Client, r.Scheme, ps.Namespace, ps)
 	defer func() {
@@ -660,6 +802,14 @@ func (r *Reconciler) resolveSecrets(ctx context.Context, ps *esapi.PushSecret) (
 		}
 
 		return secretList.Items, err
+	case ps.Spec.Selector.ConfigMap != nil || ps.Spec.Selector.Resource != nil:
+		secret, err := r.resolveSourceResource(ps, source)
+		if err != nil {
+			return nil, err
+		}
+		generatorState.EnqueueFlagLatestStateForGC(defaultGeneratorStateKey)
+
+		return []v1.Secret{*secret}, nil
 	}
 
 	return nil, errors.New("no secret selector provided")
@@ -677,7 +827,7 @@ func (r *Reconciler) resolveSecretFromGenerator(ctx context.Context, namespace s
 			return nil, fmt.Errorf("unable to get latest state: %w", err)
 		}
 	}
-	secretMap, newState, err := gen.Generate(ctx, genResource, r.Client, namespace)
+	secretMap, newState, expiresAt, err := genv1alpha1.GenerateWithExpiry(ctx, gen, genResource, r.Client, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate: %w", err)
 	}
@@ -685,7 +835,7 @@ func (r *Reconciler) resolveSecretFromGenerator(ctx context.Context, namespace s
 		generatorState.EnqueueMoveStateToGC(defaultGeneratorStateKey)
 	}
 	if generatorState != nil {
-		generatorState.EnqueueSetLatest(ctx, defaultGeneratorStateKey, namespace, genResource, gen, newState)
+		generatorState.EnqueueSetLatest(ctx, defaultGeneratorStateKey, namespace, genResource, gen, newState, expiresAt)
 	}
 	return &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -946,7 +1096,7 @@ func (r *Reconciler) expandDataTo(secret *v1.Secret, dataToList []esapi.PushSecr
 	overallRemoteKeys := make(map[string]string)
 
 	for i, dataTo := range dataToList {
-		entries, keyMap, filteredData, err := r.expandSingleDataTo(secret, dataTo)
+		entries, keyMap, overrides, err := r.expandSingleDataTo(secret, dataTo)
 		if err != nil {
 			return nil, nil, fmt.Errorf("dataTo[%d]: %w", i, err)
 		}
@@ -959,7 +1109,7 @@ func (r *Reconciler) expandDataTo(secret *v1.Secret, dataToList []esapi.PushSecr
 			return nil, nil, err
 		}
 
-		recordBundleOverrides(bundleOverrides, entries, filteredData)
+		maps.Copy(bundleOverrides, overrides)
 
 		allData = append(allData, entries...)
 		r.Log.Info("expanded dataTo entry", "index", i, "matchedKeys", len(entries), "created", len(keyMap))
@@ -980,17 +1130,6 @@ func registerRemoteKeys(seen, keyMap map[string]string, index int) error {
 	return nil
 }
 
-// recordBundleOverrides associates filtered data with bundle entries so only
-// matched keys appear in the pushed JSON blob.
-func recordBundleOverrides(overrides map[string]map[string][]byte, entries []esapi.PushSecretData, filteredData map[string][]byte) {
-	if filteredData == nil {
-		return
-	}
-	for _, entry := range entries {
-		overrides[statusRef(entry)] = filteredData
-	}
-}
-
 // expandSingleDataTo processes a single dataTo entry: converts keys, matches them
 // against the pattern, applies rewrites, validates remote keys, and builds the
 // resulting PushSecretData entries along with the source-to-remote key mapping.
@@ -1001,8 +1140,9 @@ func recordBundleOverrides(overrides map[string]map[string][]byte, entries []esa
 // only matched keys appear in the JSON blob pushed to the provider.
 //
 // Per-key mode: when dataTo.RemoteKey is empty, one PushSecretData entry is
-// produced per matched key. The third return value is nil.
-func (r *Reconciler) expandSingleDataTo(secret *v1.Secret, dataTo esapi.PushSecretDataTo) ([]esapi.PushSecretData, map[string]string, map[string][]byte, error) {
+// produced per remote key of the rewrites. The third return value carries the
+// data of remote keys produced by merge and split rewrites.
+func (r *Reconciler) expandSingleDataTo(secret *v1.Secret, dataTo esapi.PushSecretDataTo) ([]esapi.PushSecretData, map[string]string, map[string]map[string][]byte, error) {
 	if dataTo.RemoteKey != "" && len(dataTo.Rewrite) > 0 {
 		return nil, nil, nil, fmt.Errorf("remoteKey and rewrite are mutually exclusive: rewrite is only supported in per-key mode (without remoteKey)")
 	}
@@ -1056,36 +1196,49 @@ func (r *Reconciler) expandSingleDataTo(secret *v1.Secret, dataTo esapi.PushSecr
 			Metadata:           dataTo.Metadata,
 			ConversionStrategy: esapi.PushSecretConversionNone,
 		}
-		return []esapi.PushSecretData{entry}, keyMap, matchedData, nil
+		return []esapi.PushSecretData{entry}, keyMap, map[string]map[string][]byte{statusRef(entry): matchedData}, nil
 	}
 
-	keyMap, err := rewriteWithKeyMapping(dataTo.Rewrite, matchedData)
+	keys, err := rewriteKeys(dataTo.Rewrite, matchedData)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("rewrite failed: %w", err)
 	}
 
-	for sourceKey, remoteKey := range keyMap {
-		if remoteKey == "" {
-			return nil, nil, nil, fmt.Errorf("empty remote key produced for source key %q", sourceKey)
+	keyMap := make(map[string]string, len(keys))
+	overrides := make(map[string]map[string][]byte)
+	entries := make([]esapi.PushSecretData, 0, len(keys))
+	for _, key := range keys {
+		if key.remoteKey == "" {
+			return nil, nil, nil, fmt.Errorf("empty remote key produced for source key %q", key.source)
 		}
-	}
+		keyMap[key.source] = key.remoteKey
 
-	sortedKeys := slices.Sorted(maps.Keys(keyMap))
-	entries := make([]esapi.PushSecretData, 0, len(keyMap))
-	for _, convertedKey := range sortedKeys {
-		entries = append(entries, esapi.PushSecretData{
+		secretKey := key.secretKey
+		if originalKey, ok := convertedToOriginal[secretKey]; ok {
+			secretKey = originalKey
+		}
+		entry := esapi.PushSecretData{
 			Match: esapi.PushSecretMatch{
-				SecretKey: convertedToOriginal[convertedKey],
+				SecretKey: secretKey,
 				RemoteRef: esapi.PushSecretRemoteRef{
-					RemoteKey: keyMap[convertedKey],
+					RemoteKey: key.remoteKey,
 				},
 			},
 			Metadata:           dataTo.Metadata,
 			ConversionStrategy: esapi.PushSecretConversionNone,
-		})
+		}
+		// merged and split keys push values which are not in the source Secret.
+		if key.data != nil {
+			data := key.data
+			if key.secretKey != "" {
+				data = map[string][]byte{secretKey: key.data[key.secretKey]}
+			}
+			overrides[statusRef(entry)] = data
+		}
+		entries = append(entries, entry)
 	}
 
-	return entries, keyMap, nil, nil
+	return entries, keyMap, overrides, nil
 }
 
 // validateDataToStoreRefs checks that each dataTo entry has a valid storeRef.
@@ -1165,32 +1318,6 @@ func anyStoreMatchesSelector(kind string, selector labels.Selector, stores map[e
 	return false
 }
 
-// rewriteWithKeyMapping applies rewrites and returns originalKey -> rewrittenKey mapping.
-func rewriteWithKeyMapping(rewrites []esapi.PushSecretRewrite, data map[string][]byte) (map[string]string, error) {
-	keyMap := make(map[string]string, len(data))
-	for k := range data {
-		keyMap[k] = k
-	}
-
-	for i, op := range rewrites {
-		applyFn, err := compileRewrite(op)
-		if err != nil {
-			return nil, fmt.Errorf("rewrite[%d]: %w", i, err)
-		}
-		newKeyMap := make(map[string]string, len(keyMap))
-		for origKey, currentKey := range keyMap {
-			newKey, err := applyFn(currentKey)
-			if err != nil {
-				return nil, fmt.Errorf("rewrite[%d] on key %q: %w", i, currentKey, err)
-			}
-			newKeyMap[origKey] = newKey
-		}
-		keyMap = newKeyMap
-	}
-
-	return keyMap, nil
-}
-
 // compileRewrite pre-compiles a rewrite operation (regexp or template) and
 // returns a function that applies it to a key. This avoids re-compiling the
 // same regexp or re-parsing the same template for every key.
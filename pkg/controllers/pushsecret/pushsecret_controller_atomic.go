@@ -0,0 +1,191 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/util/locks"
+)
+
+const (
+	msgRolledBack     = "%s, secrets pushed in this reconcile were rolled back"
+	msgRollbackFailed = "%s, rolling back the secrets pushed in this reconcile failed: %v"
+	errRollback       = "could not roll back remote ref %v in store %v: %w"
+	errNoSnapshot     = "previous value of remote ref %v in store %v could not be read: %w"
+
+	// restoreSecretKey is the key of the Secret used to push a previous value back to the provider.
+	restoreSecretKey = "value"
+)
+
+// pushTransaction records the secrets pushed during a reconcile with their previous values,
+// so they can be rolled back if a later push fails.
+type pushTransaction struct {
+	atomic       bool
+	updatePolicy esapi.PushSecretUpdatePolicy
+	writes       []*pushWrite
+}
+
+// pushWrite is a secret pushed to a store and its value before the push.
+type pushWrite struct {
+	secretClient esv1.SecretsClient
+	storeKey     string
+	data         esapi.PushSecretData
+	// existed is false if the secret did not exist in the provider before the push.
+	existed bool
+	// prior is the previous value as stored in the provider.
+	// For entries of the whole secret, e.g. of bundled or merged keys, it is the raw JSON document.
+	prior []byte
+	// snapshotErr is set if the previous value could not be read, the secret is not rolled back.
+	snapshotErr error
+}
+
+func newPushTransaction(ps *esapi.PushSecret) *pushTransaction {
+	return &pushTransaction{
+		atomic:       ps.Spec.Atomic,
+		updatePolicy: ps.Spec.UpdatePolicy,
+	}
+}
+
+func (t *pushTransaction) enabled() bool {
+	return t != nil && t.atomic
+}
+
+// snapshot reads the value of an entry before it is pushed.
+// The value is read past the read cache of the store, a cached value may be outdated.
+// It returns nil if the transaction is disabled.
+func (t *pushTransaction) snapshot(ctx context.Context, secretClient esv1.SecretsClient, storeKey string, data esapi.PushSecretData) *pushWrite {
+	if !t.enabled() {
+		return nil
+	}
+	w := &pushWrite{secretClient: secretClient, storeKey: storeKey, data: data}
+	reader := secretstore.WithoutReadCache(secretClient)
+	var err error
+	w.prior, err = reader.GetSecret(ctx, remoteDataRef(data))
+	switch {
+	case err == nil:
+		w.existed = true
+	case errors.Is(err, esv1.NoSecretErr):
+	default:
+		// providers which can not read the value may still tell whether the secret exists.
+		exists, existsErr := secretClient.SecretExists(ctx, data.Match.RemoteRef)
+		if existsErr != nil || exists {
+			w.snapshotErr = err
+		}
+	}
+	return w
+}
+
+// record adds a pushed entry to the transaction.
+func (t *pushTransaction) record(w *pushWrite) {
+	if w == nil {
+		return
+	}
+	// with UpdatePolicy=IfNotExists, secrets which exist, or may exist, are not pushed.
+	if t.updatePolicy == esapi.PushSecretUpdatePolicyIfNotExists && (w.existed || w.snapshotErr != nil) {
+		return
+	}
+	t.writes = append(t.writes, w)
+}
+
+// rollback restores the previous values of all recorded entries in reverse order,
+// and deletes the entries which did not exist before.
+func (t *pushTransaction) rollback(ctx context.Context) error {
+	if !t.enabled() {
+		return nil
+	}
+	var errs []error
+	for i := len(t.writes) - 1; i >= 0; i-- {
+		w := t.writes[i]
+		ref := statusRef(w.data)
+		var err error
+		switch {
+		case w.snapshotErr != nil:
+			err = fmt.Errorf(errNoSnapshot, ref, w.storeKey, w.snapshotErr)
+		case w.existed:
+			err = w.secretClient.PushSecret(ctx, w.restoreSecret(), w.restoreData())
+		default:
+			err = w.secretClient.DeleteSecret(ctx, w.data.Match.RemoteRef)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errRollback, ref, w.storeKey, err))
+		}
+	}
+	t.writes = nil
+	return errors.Join(errs...)
+}
+
+// restoreSecret returns a Secret holding the previous value of an entry.
+func (w *pushWrite) restoreSecret() *v1.Secret {
+	return &v1.Secret{Data: map[string][]byte{restoreSecretKey: w.prior}}
+}
+
+// restoreData returns the entry pushing the previous value as read from the provider, without conversion.
+// The previous value of a whole secret is pushed back as a single value, so the provider stores
+// the exact document it held before, instead of a JSON object of string values.
+func (w *pushWrite) restoreData() esapi.PushSecretData {
+	restore := *w.data.DeepCopy()
+	restore.Match.SecretKey = restoreSecretKey
+	restore.ConversionStrategy = esapi.PushSecretConversionNone
+	return restore
+}
+
+// abortPush rolls back the secrets pushed in this reconcile after pushErr occurred, and marks the PushSecret failed.
+// If all secrets were rolled back, the synced secrets of the previous reconcile are kept in the status.
+func (r *Reconciler) abortPush(
+	ctx context.Context,
+	ps *esapi.PushSecret,
+	tx *pushTransaction,
+	drift *driftState,
+	pushErr error,
+	synced esapi.SyncedPushSecretsMap,
+	refreshInt time.Duration,
+) (ctrl.Result, error) {
+	state := ps.Status.SyncedPushSecrets
+	msg := fmt.Sprintf(errFailedSetSecret, pushErr)
+	if err := tx.rollback(ctx); err != nil {
+		// secrets which could not be rolled back stay in the status, so they are deleted with DeletionPolicy=Delete.
+		state = mergeSecretState(synced, state)
+		ps.Status.SyncedPushSecretHashes = drift.syncedHashes(state)
+		msg = fmt.Sprintf(msgRollbackFailed, msg, err)
+	} else {
+		msg = fmt.Sprintf(msgRolledBack, msg)
+		r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonRolledBack, msg)
+	}
+
+	switch {
+	case errors.Is(pushErr, locks.ErrConflict):
+		r.Log.Info("retry to acquire lock to update the secret later", "pushsecret", client.ObjectKeyFromObject(ps), "error", pushErr)
+		r.setSecrets(ps, state)
+		return ctrl.Result{Requeue: true}, nil
+	case errors.Is(pushErr, errDrifted):
+		r.markAsDrifted(msg, ps, drift, state)
+		return ctrl.Result{RequeueAfter: refreshInt}, nil
+	}
+	r.markAsFailed(msg, ps, state)
+	return ctrl.Result{}, pushErr
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+var errUnreadable = errors.New("reading secrets is not supported")
+
+// memoryStore is a provider which keeps the pushed values in memory.
+// Whole secrets are stored as a JSON object, secrets in unreadable exist, but can not be read.
+type memoryStore struct {
+	*fake.Client
+	values     map[string][]byte
+	unreadable map[string]bool
+}
+
+func (s *memoryStore) GetSecret(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if s.unreadable[ref.Key] {
+		return nil, errUnreadable
+	}
+	value, ok := s.values[ref.Key]
+	if !ok {
+		return nil, esv1.NoSecretErr
+	}
+	return value, nil
+}
+
+func (s *memoryStore) SecretExists(_ context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+	_, ok := s.values[ref.GetRemoteKey()]
+	return ok || s.unreadable[ref.GetRemoteKey()], nil
+}
+
+func (s *memoryStore) PushSecret(_ context.Context, secret *v1.Secret, data esv1.PushSecretData) error {
+	if data.GetSecretKey() == "" {
+		obj := make(map[string]string, len(secret.Data))
+		for key, value := range secret.Data {
+			obj[key] = string(value)
+		}
+		value, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		s.values[data.GetRemoteKey()] = value
+		return nil
+	}
+	s.values[data.GetRemoteKey()] = secret.Data[data.GetSecretKey()]
+	return nil
+}
+
+func (s *memoryStore) DeleteSecret(_ context.Context, ref esv1.PushSecretRemoteRef) error {
+	delete(s.values, ref.GetRemoteKey())
+	return nil
+}
+
+func TestPushTransaction(t *testing.T) {
+	ctx := context.Background()
+	const storeKey = "SecretStore/store"
+	provider := &memoryStore{Client: fake.New(), values: map[string][]byte{"existing": []byte("old")}, unreadable: map[string]bool{}}
+	entry := func(remoteKey string) esapi.PushSecretData {
+		return esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "key", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: remoteKey}}}
+	}
+	secret := &v1.Secret{Data: map[string][]byte{"key": []byte("new")}}
+	push := func(tx *pushTransaction, remoteKey string) {
+		t.Helper()
+		w := tx.snapshot(ctx, provider, storeKey, entry(remoteKey))
+		require.NoError(t, provider.PushSecret(ctx, secret, entry(remoteKey)))
+		tx.record(w)
+	}
+	ps := &esapi.PushSecret{Spec: esapi.PushSecretSpec{Atomic: true}}
+
+	// existing secrets are restored and new secrets are deleted.
+	tx := newPushTransaction(ps)
+	push(tx, "existing")
+	push(tx, "created")
+	assert.Equal(t, []byte("new"), provider.values["existing"])
+	require.NoError(t, tx.rollback(ctx))
+	assert.Equal(t, map[string][]byte{"existing": []byte("old")}, provider.values)
+
+	// secrets whose previous value can not be read are not rolled back.
+	provider.unreadable["opaque"] = true
+	tx = newPushTransaction(ps)
+	push(tx, "opaque")
+	err := tx.rollback(ctx)
+	assert.ErrorContains(t, err, "could not roll back remote ref opaque in store SecretStore/store")
+	assert.ErrorIs(t, err, errUnreadable)
+
+	// with UpdatePolicy=IfNotExists, existing secrets are not pushed and not rolled back.
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyIfNotExists
+	tx = newPushTransaction(ps)
+	tx.record(tx.snapshot(ctx, provider, storeKey, entry("existing")))
+	tx.record(tx.snapshot(ctx, provider, storeKey, entry("opaque")))
+	assert.Empty(t, tx.writes)
+
+	// without Atomic, nothing is recorded.
+	tx = newPushTransaction(&esapi.PushSecret{})
+	assert.Nil(t, tx.snapshot(ctx, provider, storeKey, entry("existing")))
+	assert.NoError(t, tx.rollback(ctx))
+}
+
+func TestPushTransactionWholeSecret(t *testing.T) {
+	ctx := context.Background()
+	const storeKey = "SecretStore/store"
+	r := &Reconciler{Log: logr.Discard()}
+	provider := &memoryStore{Client: fake.New(), values: map[string][]byte{}, unreadable: map[string]bool{}}
+	ps := &esapi.PushSecret{Spec: esapi.PushSecretSpec{Atomic: true}}
+	push := func(tx *pushTransaction, data esapi.PushSecretData, values map[string][]byte) {
+		t.Helper()
+		w := tx.snapshot(ctx, provider, storeKey, data)
+		require.NoError(t, provider.PushSecret(ctx, &v1.Secret{Data: values}, data))
+		tx.record(w)
+	}
+
+	// secrets pushed as a whole are restored as the exact document, including nested objects and numbers.
+	whole := esapi.PushSecretData{Match: esapi.PushSecretMatch{RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "bundle"}}}
+	created := esapi.PushSecretData{Match: esapi.PushSecretMatch{RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "created"}}}
+	previous := []byte(`{"a":{"nested":true},"b":2}`)
+	provider.values["bundle"] = previous
+	tx := newPushTransaction(ps)
+	push(tx, whole, map[string][]byte{"a": []byte("new")})
+	push(tx, created, map[string][]byte{"a": []byte("new")})
+	assert.JSONEq(t, `{"a":"new"}`, string(provider.values["bundle"]))
+	require.NoError(t, tx.rollback(ctx))
+	assert.Equal(t, map[string][]byte{"bundle": previous}, provider.values)
+
+	// keys merged by a dataTo rewrite are restored as they were merged before.
+	secret := &v1.Secret{Data: map[string][]byte{"DB_HOST": []byte("db.new"), "DB_USER": []byte("app")}}
+	entries, _, overrides, err := r.expandSingleDataTo(secret, esapi.PushSecretDataTo{
+		Rewrite: []esapi.PushSecretRewrite{{Merge: &esapi.PushSecretRewriteMerge{Into: "db"}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	provider.values["db"] = []byte(`{"DB_HOST":"db.old"}`)
+	tx = newPushTransaction(ps)
+	push(tx, entries[0], overrides[statusRef(entries[0])])
+	assert.JSONEq(t, `{"DB_HOST":"db.new","DB_USER":"app"}`, string(provider.values["db"]))
+	require.NoError(t, tx.rollback(ctx))
+	assert.Equal(t, []byte(`{"DB_HOST":"db.old"}`), provider.values["db"])
+}
+
+func TestAbortPush(t *testing.T) {
+	ctx := context.Background()
+	const storeKey = "SecretStore/store"
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{Log: logr.Discard(), recorder: recorder}
+	provider := &memoryStore{Client: fake.New(), values: map[string][]byte{}, unreadable: map[string]bool{}}
+	data := esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "key", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "created"}}}
+	previous := esapi.SyncedPushSecretsMap{storeKey: {"kept": data}}
+	ps := &esapi.PushSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "default"},
+		Spec:       esapi.PushSecretSpec{Atomic: true},
+		Status:     esapi.PushSecretStatus{SyncedPushSecrets: previous},
+	}
+	tx := newPushTransaction(ps)
+	w := tx.snapshot(ctx, provider, storeKey, data)
+	require.NoError(t, provider.PushSecret(ctx, &v1.Secret{Data: map[string][]byte{"key": []byte("new")}}, data))
+	tx.record(w)
+	synced := esapi.SyncedPushSecretsMap{storeKey: {"created": data}}
+
+	// after a rollback, the synced secrets of the previous reconcile are kept.
+	pushErr := errors.New("push failed")
+	_, err := r.abortPush(ctx, ps, tx, newDriftState(ps), pushErr, synced, time.Hour)
+	assert.ErrorIs(t, err, pushErr)
+	assert.Empty(t, provider.values)
+	assert.Equal(t, previous, ps.Status.SyncedPushSecrets)
+	cond := GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionFalse, cond.Status)
+	assert.Contains(t, cond.Message, "rolled back")
+	assert.Contains(t, <-recorder.Events, esapi.ReasonRolledBack)
+
+	// secrets which could not be rolled back are kept in the status.
+	provider.unreadable["created"] = true
+	tx = newPushTransaction(ps)
+	tx.record(tx.snapshot(ctx, provider, storeKey, data))
+	_, err = r.abortPush(ctx, ps, tx, newDriftState(ps), pushErr, synced, time.Hour)
+	assert.ErrorIs(t, err, pushErr)
+	assert.Contains(t, ps.Status.SyncedPushSecrets[storeKey], "created")
+	assert.Contains(t, ps.Status.SyncedPushSecrets[storeKey], "kept")
+	cond = GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretReady)
+	require.NotNil(t, cond)
+	assert.Contains(t, cond.Message, "rolling back the secrets pushed in this reconcile failed")
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+// deleteExpiredPendingDeletions deletes the pending deletions of the PushSecret whose grace period expired.
+func (r *Reconciler) deleteExpiredPendingDeletions(ctx context.Context, ps *esapi.PushSecret, mgr *secretstore.Manager) error {
+	now := time.Now()
+	pending := make([]esapi.PushSecretPendingDeletion, 0, len(ps.Status.PendingDeletions))
+	var errs []error
+	for _, deletion := range ps.Status.PendingDeletions {
+		if deletion.DeleteAfter.After(now) {
+			pending = append(pending, deletion)
+			continue
+		}
+		secretsClient, err := mgr.Get(ctx, storeRefFromKey(deletion.StoreRef), ps.Namespace, nil)
+		if err != nil {
+			pending = append(pending, deletion)
+			errs = append(errs, fmt.Errorf("could not get secrets client for store %v: %w", deletion.StoreRef, err))
+			continue
+		}
+		if err := r.DeleteSecretFromStore(ctx, secretsClient, deletion.Data, ps.Spec.DeletionMode); err != nil {
+			pending = append(pending, deletion)
+			errs = append(errs, err)
+			continue
+		}
+		r.Log.Info("deleted secret after the deletion grace period", "pushsecret", client.ObjectKeyFromObject(ps), "store", deletion.StoreRef, "key", deletion.Key)
+	}
+	ps.Status.PendingDeletions = nil
+	if len(pending) > 0 {
+		ps.Status.PendingDeletions = pending
+	}
+	return errors.Join(errs...)
+}
+
+// cancelPendingDeletions drops the pending deletions of secrets which are present in the synced push secrets.
+func cancelPendingDeletions(pending []esapi.PushSecretPendingDeletion, synced esapi.SyncedPushSecretsMap) []esapi.PushSecretPendingDeletion {
+	var out []esapi.PushSecretPendingDeletion
+	for _, deletion := range pending {
+		if _, ok := synced[deletion.StoreRef][deletion.Key]; ok {
+			continue
+		}
+		out = append(out, deletion)
+	}
+	return out
+}
+
+// addPendingDeletion records a pending deletion, a secret which is already pending keeps its deletion time.
+func addPendingDeletion(pending []esapi.PushSecretPendingDeletion, deletion esapi.PushSecretPendingDeletion) []esapi.PushSecretPendingDeletion {
+	for _, p := range pending {
+		if p.StoreRef == deletion.StoreRef && p.Key == deletion.Key {
+			return pending
+		}
+	}
+	return append(pending, deletion)
+}
+
+// nextPendingDeletion returns the time until the next pending deletion of the PushSecret is due.
+func nextPendingDeletion(ps *esapi.PushSecret) (time.Duration, bool) {
+	if len(ps.Status.PendingDeletions) == 0 {
+		return 0, false
+	}
+	next := ps.Status.PendingDeletions[0].DeleteAfter.Time
+	for _, deletion := range ps.Status.PendingDeletions[1:] {
+		if deletion.DeleteAfter.Before(&metav1.Time{Time: next}) {
+			next = deletion.DeleteAfter.Time
+		}
+	}
+	return max(time.Until(next), 0), true
+}
+
+// pendingDeletionDue returns true if the grace period of a pending deletion of the PushSecret expired.
+func pendingDeletionDue(ps *esapi.PushSecret) bool {
+	next, ok := nextPendingDeletion(ps)
+	return ok && next == 0
+}
+
+// requeueForPendingDeletions shortens the requeue interval so the next pending deletion is executed when it is due.
+func requeueForPendingDeletions(ps *esapi.PushSecret, requeueAfter time.Duration) time.Duration {
+	next, ok := nextPendingDeletion(ps)
+	if !ok || (requeueAfter > 0 && requeueAfter <= next) {
+		return requeueAfter
+	}
+	// give the deletion time a margin, so the deletion is due when the PushSecret is reconciled.
+	return next + time.Second
+}
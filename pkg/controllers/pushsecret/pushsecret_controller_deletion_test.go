@@ -0,0 +1,136 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+// softDeletingStore is a memoryStore which keeps soft deleted secrets.
+type softDeletingStore struct {
+	*memoryStore
+	softDeleted map[string][]byte
+}
+
+func (s *softDeletingStore) SoftDeleteSecret(_ context.Context, ref esv1.PushSecretRemoteRef) error {
+	s.softDeleted[ref.GetRemoteKey()] = s.values[ref.GetRemoteKey()]
+	delete(s.values, ref.GetRemoteKey())
+	return nil
+}
+
+func TestDeleteSecretFromProviders(t *testing.T) {
+	ctx := context.Background()
+	const storeKey = "SecretStore/store"
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esv1.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(&esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"},
+		Spec:       esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{Fake: &esv1.FakeProvider{}}},
+	}).Build()
+	var provider esv1.SecretsClient
+	fakeProvider.WithNew(func(context.Context, esv1.GenericStore, client.Client, string) (esv1.SecretsClient, error) {
+		return provider, nil
+	})
+	t.Cleanup(fakeProvider.Reset)
+	r := &Reconciler{Client: kube, Log: logr.Discard()}
+	deleteSecrets := func(ps *esapi.PushSecret, newMap esapi.SyncedPushSecretsMap) (esapi.SyncedPushSecretsMap, error) {
+		t.Helper()
+		mgr := secretstore.NewManager(kube, "", false)
+		defer func() { _ = mgr.Close(ctx) }()
+		return r.DeleteSecretFromProviders(ctx, ps, newMap, mgr)
+	}
+	entry := func(remoteKey string) esapi.PushSecretData {
+		return esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: remoteKey, RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: remoteKey}}}
+	}
+	store := &memoryStore{Client: fake.New(), values: map[string][]byte{"a": []byte("a"), "b": []byte("b")}}
+	provider = store
+	ps := &esapi.PushSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "default"},
+		Spec: esapi.PushSecretSpec{
+			DeletionPolicy:      esapi.PushSecretDeletionPolicyDelete,
+			DeletionGracePeriod: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: esapi.PushSecretStatus{SyncedPushSecrets: esapi.SyncedPushSecretsMap{storeKey: {"a": entry("a"), "b": entry("b")}}},
+	}
+
+	// a removed secret is recorded as pending deletion.
+	out, err := deleteSecrets(ps, esapi.SyncedPushSecretsMap{storeKey: {"a": entry("a")}})
+	require.NoError(t, err)
+	assert.Equal(t, esapi.SyncedPushSecretsMap{storeKey: {"a": entry("a")}}, out)
+	require.Len(t, ps.Status.PendingDeletions, 1)
+	assert.Equal(t, "b", ps.Status.PendingDeletions[0].Key)
+	assert.Contains(t, store.values, "b")
+	requeue := requeueForPendingDeletions(ps, 2*time.Hour)
+	assert.InDelta(t, time.Hour, requeue, float64(time.Minute))
+	assert.Equal(t, time.Minute, requeueForPendingDeletions(ps, time.Minute))
+	assert.False(t, pendingDeletionDue(ps))
+
+	// the pending deletion is cancelled when the secret is pushed again.
+	ps.Status.SyncedPushSecrets = out
+	_, err = deleteSecrets(ps, esapi.SyncedPushSecretsMap{storeKey: {"a": entry("a"), "b": entry("b")}})
+	require.NoError(t, err)
+	assert.Empty(t, ps.Status.PendingDeletions)
+
+	// the secret is deleted once the grace period expired.
+	ps.Status.SyncedPushSecrets = esapi.SyncedPushSecretsMap{storeKey: {"a": entry("a"), "b": entry("b")}}
+	_, err = deleteSecrets(ps, esapi.SyncedPushSecretsMap{})
+	require.NoError(t, err)
+	require.Len(t, ps.Status.PendingDeletions, 2)
+	ps.Status.SyncedPushSecrets = nil
+	ps.Status.PendingDeletions[0].DeleteAfter = metav1.NewTime(time.Now().Add(-time.Second))
+	assert.True(t, pendingDeletionDue(ps))
+	_, err = deleteSecrets(ps, esapi.SyncedPushSecretsMap{})
+	require.NoError(t, err)
+	assert.Len(t, ps.Status.PendingDeletions, 1)
+	assert.Len(t, store.values, 1)
+
+	// soft deletes fail for providers which do not support them.
+	ps.Spec.DeletionGracePeriod = nil
+	ps.Spec.DeletionMode = esapi.PushSecretDeletionModeSoft
+	ps.Status.PendingDeletions = nil
+	ps.Status.SyncedPushSecrets = esapi.SyncedPushSecretsMap{storeKey: {"a": entry("a")}}
+	store.values = map[string][]byte{"a": []byte("a")}
+	_, err = deleteSecrets(ps, esapi.SyncedPushSecretsMap{})
+	assert.ErrorIs(t, err, esv1.SoftDeleteNotSupportedErr)
+	assert.Contains(t, store.values, "a")
+
+	// without a grace period, secrets are soft deleted right away.
+	softStore := &softDeletingStore{memoryStore: store, softDeleted: map[string][]byte{}}
+	provider = softStore
+	out, err = deleteSecrets(ps, esapi.SyncedPushSecretsMap{})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	assert.Empty(t, store.values)
+	assert.Equal(t, map[string][]byte{"a": []byte("a")}, softStore.softDeleted)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret/psmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	msgDrifted    = "secrets were changed in the provider since the last push: %s"
+	msgNotDrifted = "no secret was changed in the provider since the last push"
+)
+
+// errDrifted is returned when secrets were changed in the provider and DriftMode=Fail.
+var errDrifted = errors.New("secrets were changed in the provider since the last push")
+
+// driftState tracks the hashes of the remote values and the drifted secrets during a reconcile.
+type driftState struct {
+	mode esapi.PushSecretDriftMode
+	// recorded are the hashes of the last reconcile, hashes are the hashes of this reconcile.
+	recorded esapi.SyncedPushSecretHashesMap
+	hashes   esapi.SyncedPushSecretHashesMap
+	// drifted are the drifted secrets, formatted as "<store>/<remote ref>".
+	drifted []string
+}
+
+func newDriftState(ps *esapi.PushSecret) *driftState {
+	mode := ps.Spec.DriftMode
+	// without replacing, secrets that exist in the provider are never overwritten.
+	if ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
+		mode = esapi.PushSecretDriftModeNone
+	}
+	return &driftState{
+		mode:     mode,
+		recorded: ps.Status.SyncedPushSecretHashes,
+		hashes:   make(esapi.SyncedPushSecretHashesMap),
+	}
+}
+
+func (d *driftState) enabled() bool {
+	return d != nil && d.mode != "" && d.mode != esapi.PushSecretDriftModeNone
+}
+
+func (d *driftState) set(storeKey, ref, hash string) {
+	if d.hashes[storeKey] == nil {
+		d.hashes[storeKey] = make(map[string]string)
+	}
+	d.hashes[storeKey][ref] = hash
+}
+
+// isDrifted returns true if the secret was found to be drifted in this reconcile.
+func (d *driftState) isDrifted(storeKey, ref string) bool {
+	return d.enabled() && slices.Contains(d.drifted, storeKey+"/"+ref)
+}
+
+// detect compares the remote values of the entries with their recorded hashes and returns the drifted entries.
+// Entries without a recorded hash, or which do not exist in the provider, are not drifted.
+// The remote values are read past the read cache of the store, a cached value would hide the drift.
+func (d *driftState) detect(ctx context.Context, secretClient esv1.SecretsClient, storeKey string, entries []esapi.PushSecretData) ([]string, error) {
+	secretClient = secretstore.WithoutReadCache(secretClient)
+	var drifted []string
+	for _, data := range entries {
+		ref := statusRef(data)
+		// secrets pushed earlier in this reconcile are compared with their new hash.
+		recorded, ok := d.hashes[storeKey][ref]
+		if !ok {
+			recorded, ok = d.recorded[storeKey][ref]
+		}
+		if !ok {
+			continue
+		}
+		value, err := secretClient.GetSecret(ctx, remoteDataRef(data))
+		if errors.Is(err, esv1.NoSecretErr) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read remote ref %v from store %v: %w", ref, storeKey, err)
+		}
+		if esutils.ObjectHash(value) != recorded {
+			drifted = append(drifted, storeKey+"/"+ref)
+			if slices.Contains(d.drifted, storeKey+"/"+ref) {
+				continue
+			}
+			d.drifted = append(d.drifted, storeKey+"/"+ref)
+			// the recorded hash is kept, so the drift is reported until it is resolved.
+			d.set(storeKey, ref, recorded)
+		}
+	}
+	return drifted, nil
+}
+
+// recordRemoteHash reads back the remote value of a pushed entry and records its hash.
+// Providers which can not read back the value do not support drift detection.
+func (r *Reconciler) recordRemoteHash(ctx context.Context, d *driftState, secretClient esv1.SecretsClient, storeKey string, data esapi.PushSecretData) {
+	value, err := secretstore.WithoutReadCache(secretClient).GetSecret(ctx, remoteDataRef(data))
+	if err != nil {
+		r.Log.V(1).Info("could not read back pushed secret, drift can not be detected", "store", storeKey, "remoteRef", statusRef(data), "error", err.Error())
+		return
+	}
+	d.set(storeKey, statusRef(data), esutils.ObjectHash(value))
+}
+
+func remoteDataRef(data esapi.PushSecretData) esv1.ExternalSecretDataRemoteRef {
+	return esv1.ExternalSecretDataRemoteRef{
+		Key:      data.GetRemoteKey(),
+		Property: data.GetProperty(),
+	}
+}
+
+// syncedHashes returns the recorded hashes of the synced secrets.
+func (d *driftState) syncedHashes(synced esapi.SyncedPushSecretsMap) esapi.SyncedPushSecretHashesMap {
+	if !d.enabled() {
+		return nil
+	}
+	out := make(esapi.SyncedPushSecretHashesMap)
+	for storeKey, refs := range synced {
+		for ref := range refs {
+			hash, ok := d.hashes[storeKey][ref]
+			if !ok {
+				hash, ok = d.recorded[storeKey][ref]
+			}
+			if !ok {
+				continue
+			}
+			if out[storeKey] == nil {
+				out[storeKey] = make(map[string]string)
+			}
+			out[storeKey][ref] = hash
+		}
+	}
+	return out
+}
+
+// markDrift reports the drift of the reconcile in the status.
+// Secrets which were not drifted in the last reconcile are reported as an event and counted in the metric,
+// so a drift is only counted once until it is resolved.
+func (r *Reconciler) markDrift(ps *esapi.PushSecret, d *driftState) {
+	if !d.enabled() {
+		ps.Status.Conditions = FilterOutCondition(ps.Status.Conditions, esapi.PushSecretDrifted)
+		ps.Status.DriftedSecrets = nil
+		return
+	}
+	if len(d.drifted) == 0 {
+		cond := NewPushSecretCondition(esapi.PushSecretDrifted, v1.ConditionFalse, esapi.ReasonNotDrifted, msgNotDrifted)
+		SetPushSecretCondition(ps, *cond)
+		ps.Status.DriftedSecrets = nil
+		return
+	}
+	slices.Sort(d.drifted)
+	msg := fmt.Sprintf(msgDrifted, strings.Join(d.drifted, ", "))
+	cond := NewPushSecretCondition(esapi.PushSecretDrifted, v1.ConditionTrue, esapi.ReasonDrifted, msg)
+	SetPushSecretCondition(ps, *cond)
+
+	var newlyDrifted []string
+	for _, ref := range d.drifted {
+		if !slices.Contains(ps.Status.DriftedSecrets, ref) {
+			newlyDrifted = append(newlyDrifted, ref)
+		}
+	}
+	ps.Status.DriftedSecrets = slices.Clone(d.drifted)
+	if len(newlyDrifted) == 0 {
+		return
+	}
+	r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonDrifted, fmt.Sprintf(msgDrifted, strings.Join(newlyDrifted, ", ")))
+	psmetrics.ObserveDrift(ps, len(newlyDrifted))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+func TestDriftDetection(t *testing.T) {
+	ctx := context.Background()
+	const storeKey = "SecretStore/store"
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{Log: logr.Discard(), recorder: recorder}
+	provider := fake.New()
+	remote := map[string][]byte{}
+	provider.GetSecretFn = func(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+		value, ok := remote[ref.Key]
+		if !ok {
+			return nil, esv1.NoSecretErr
+		}
+		return value, nil
+	}
+	data := esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "key", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote"}}}
+	synced := esapi.SyncedPushSecretsMap{storeKey: {"remote": data}}
+	ps := &esapi.PushSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "default"},
+		Spec:       esapi.PushSecretSpec{DriftMode: esapi.PushSecretDriftModeDetect},
+	}
+
+	// without a recorded hash, nothing is drifted and the hash is recorded after the push.
+	drift := newDriftState(ps)
+	drifted, err := drift.detect(ctx, provider, storeKey, []esapi.PushSecretData{data})
+	require.NoError(t, err)
+	assert.Empty(t, drifted)
+	remote["remote"] = []byte("pushed")
+	r.recordRemoteHash(ctx, drift, provider, storeKey, data)
+	ps.Status.SyncedPushSecretHashes = drift.syncedHashes(synced)
+	require.Contains(t, ps.Status.SyncedPushSecretHashes[storeKey], "remote")
+	r.markDrift(ps, drift)
+	cond := GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretDrifted)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionFalse, cond.Status)
+
+	// a value changed in the provider is drifted, and its recorded hash is kept.
+	remote["remote"] = []byte("changed")
+	drift = newDriftState(ps)
+	drifted, err = drift.detect(ctx, provider, storeKey, []esapi.PushSecretData{data})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SecretStore/store/remote"}, drifted)
+	assert.True(t, drift.isDrifted(storeKey, "remote"))
+	assert.Equal(t, ps.Status.SyncedPushSecretHashes, drift.syncedHashes(synced))
+	r.markDrift(ps, drift)
+	cond = GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretDrifted)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionTrue, cond.Status)
+	assert.Equal(t, esapi.ReasonDrifted, cond.Reason)
+	assert.Contains(t, cond.Message, "SecretStore/store/remote")
+	assert.Equal(t, []string{"SecretStore/store/remote"}, ps.Status.DriftedSecrets)
+	require.Len(t, recorder.Events, 1)
+	<-recorder.Events
+
+	// a drift is only reported once until it is resolved.
+	drift = newDriftState(ps)
+	_, err = drift.detect(ctx, provider, storeKey, []esapi.PushSecretData{data})
+	require.NoError(t, err)
+	r.markDrift(ps, drift)
+	assert.Equal(t, v1.ConditionTrue, GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretDrifted).Status)
+	assert.Empty(t, recorder.Events)
+
+	// a value deleted in the provider is not drifted, it is pushed again.
+	delete(remote, "remote")
+	drift = newDriftState(ps)
+	drifted, err = drift.detect(ctx, provider, storeKey, []esapi.PushSecretData{data})
+	require.NoError(t, err)
+	assert.Empty(t, drifted)
+	r.markDrift(ps, drift)
+	assert.Empty(t, ps.Status.DriftedSecrets)
+
+	// with UpdatePolicy=IfNotExists, existing values are never overwritten.
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyIfNotExists
+	drift = newDriftState(ps)
+	assert.False(t, drift.enabled())
+	assert.Nil(t, drift.syncedHashes(synced))
+	r.markDrift(ps, drift)
+	assert.Nil(t, GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretDrifted))
+}
@@ -0,0 +1,199 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+const defaultSplitSeparator = "/"
+
+// rewrittenKey is a remote key produced by the rewrite operations of a dataTo entry.
+type rewrittenKey struct {
+	remoteKey string
+	// source describes the keys of the source Secret the remote key was produced from.
+	source string
+	// secretKey is the key whose value is pushed to the remote key.
+	// It is empty if all keys of data are pushed as JSON object.
+	secretKey string
+	// data holds the pushed values if they are not values of the source Secret,
+	// e.g. for merged and split keys.
+	data map[string][]byte
+}
+
+// value returns the value pushed to the remote key.
+func (k rewrittenKey) value(source map[string][]byte) ([]byte, error) {
+	if k.data == nil {
+		return source[k.secretKey], nil
+	}
+	if k.secretKey != "" {
+		return k.data[k.secretKey], nil
+	}
+	obj := make(map[string]string, len(k.data))
+	for key, value := range k.data {
+		obj[key] = string(value)
+	}
+	return json.Marshal(obj)
+}
+
+// rewriteKeys applies the rewrite operations to the keys of data and returns the resulting remote keys.
+// Regexp and transform rewrite the names of the remote keys, merge and split change
+// which values are pushed to them.
+func rewriteKeys(rewrites []esapi.PushSecretRewrite, data map[string][]byte) ([]rewrittenKey, error) {
+	keys := make([]rewrittenKey, 0, len(data))
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		keys = append(keys, rewrittenKey{remoteKey: key, source: key, secretKey: key})
+	}
+
+	for i, op := range rewrites {
+		var err error
+		switch {
+		case op.Merge != nil:
+			keys, err = mergeKeys(keys, op.Merge, data)
+		case op.Split != nil:
+			keys, err = splitKeys(keys, op.Split, data)
+		default:
+			keys, err = renameKeys(keys, op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rewrite[%d]: %w", i, err)
+		}
+	}
+	return keys, nil
+}
+
+// renameKeys applies a regexp or transform rewrite to the remote keys.
+func renameKeys(keys []rewrittenKey, op esapi.PushSecretRewrite) ([]rewrittenKey, error) {
+	applyFn, err := compileRewrite(op)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]rewrittenKey, 0, len(keys))
+	for _, key := range keys {
+		remoteKey, err := applyFn(key.remoteKey)
+		if err != nil {
+			return nil, fmt.Errorf("on key %q: %w", key.remoteKey, err)
+		}
+		key.remoteKey = remoteKey
+		out = append(out, key)
+	}
+	return out, nil
+}
+
+// mergeKeys merges the matching keys into a single remote key holding a JSON object of their values.
+func mergeKeys(keys []rewrittenKey, merge *esapi.PushSecretRewriteMerge, data map[string][]byte) ([]rewrittenKey, error) {
+	matched, err := matchRemoteKeys(keys, merge.Match)
+	if err != nil {
+		return nil, err
+	}
+	merged := rewrittenKey{remoteKey: merge.Into, data: make(map[string][]byte)}
+	sources := make([]string, 0, len(keys))
+	out := make([]rewrittenKey, 0, len(keys))
+	for _, key := range keys {
+		if !matched[key.remoteKey] {
+			out = append(out, key)
+			continue
+		}
+		if _, exists := merged.data[key.remoteKey]; exists {
+			return nil, fmt.Errorf("duplicate key %q merged into %q", key.remoteKey, merge.Into)
+		}
+		value, err := key.value(data)
+		if err != nil {
+			return nil, fmt.Errorf("on key %q: %w", key.remoteKey, err)
+		}
+		merged.data[key.remoteKey] = value
+		sources = append(sources, key.source)
+	}
+	if len(sources) == 0 {
+		return out, nil
+	}
+	merged.source = strings.Join(sources, ",")
+	return append(out, merged), nil
+}
+
+// splitKeys splits the matching keys holding a JSON object into a remote key for every property of the object.
+func splitKeys(keys []rewrittenKey, split *esapi.PushSecretRewriteSplit, data map[string][]byte) ([]rewrittenKey, error) {
+	matched, err := matchRemoteKeys(keys, split.Match)
+	if err != nil {
+		return nil, err
+	}
+	separator := split.Separator
+	if separator == "" {
+		separator = defaultSplitSeparator
+	}
+	out := make([]rewrittenKey, 0, len(keys))
+	for _, key := range keys {
+		if !matched[key.remoteKey] {
+			out = append(out, key)
+			continue
+		}
+		value, err := key.value(data)
+		if err != nil {
+			return nil, fmt.Errorf("on key %q: %w", key.remoteKey, err)
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(value, &obj); err != nil || obj == nil {
+			return nil, fmt.Errorf("on key %q: value is not a JSON object", key.remoteKey)
+		}
+		for _, property := range slices.Sorted(maps.Keys(obj)) {
+			// keys merged before have no single source key, the property is used instead.
+			secretKey := key.secretKey
+			if secretKey == "" {
+				secretKey = property
+			}
+			out = append(out, rewrittenKey{
+				remoteKey: key.remoteKey + separator + property,
+				source:    key.source + separator + property,
+				secretKey: secretKey,
+				data:      map[string][]byte{secretKey: jsonValue(obj[property])},
+			})
+		}
+	}
+	return out, nil
+}
+
+// matchRemoteKeys returns the remote keys matching the pattern, all keys match without a pattern.
+func matchRemoteKeys(keys []rewrittenKey, match *esapi.PushSecretDataToMatch) (map[string]bool, error) {
+	remoteKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		remoteKeys = append(remoteKeys, key.remoteKey)
+	}
+	matchedKeys, err := matchKeys(remoteKeys, match)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool, len(matchedKeys))
+	for _, key := range matchedKeys {
+		matched[key] = true
+	}
+	return matched, nil
+}
+
+// jsonValue returns strings without quotes and all other values as JSON.
+func jsonValue(raw json.RawMessage) []byte {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s)
+	}
+	return raw
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestExpandDataToRewrites(t *testing.T) {
+	r := &Reconciler{Log: logr.Discard()}
+	secret := &v1.Secret{Data: map[string][]byte{
+		"DB_HOST": []byte("db.local"),
+		"DB_USER": []byte("app"),
+		"config":  []byte(`{"api":{"url":"https://api"},"port":8080,"token":"s3cr3t"}`),
+	}}
+	remoteKeys := func(entries []esapi.PushSecretData) []string {
+		out := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			out = append(out, entry.GetRemoteKey())
+		}
+		return out
+	}
+
+	// matching keys are merged into a single remote key, the others are kept.
+	entries, keyMap, overrides, err := r.expandSingleDataTo(secret, esapi.PushSecretDataTo{
+		Rewrite: []esapi.PushSecretRewrite{
+			{Merge: &esapi.PushSecretRewriteMerge{Into: "app/db", Match: &esapi.PushSecretDataToMatch{RegExp: "^DB_"}}},
+			{Regexp: &esv1.ExternalSecretRewriteRegexp{Source: "^app/", Target: "prod/"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config", "prod/db"}, remoteKeys(entries))
+	assert.Equal(t, map[string]string{"config": "config", "DB_HOST,DB_USER": "prod/db"}, keyMap)
+	assert.Empty(t, entries[1].GetSecretKey())
+	assert.Equal(t, map[string]map[string][]byte{
+		"prod/db": {"DB_HOST": []byte("db.local"), "DB_USER": []byte("app")},
+	}, overrides)
+
+	// JSON objects are split into a remote key for every property.
+	entries, _, overrides, err = r.expandSingleDataTo(secret, esapi.PushSecretDataTo{
+		Match: &esapi.PushSecretDataToMatch{RegExp: "config"},
+		Rewrite: []esapi.PushSecretRewrite{
+			{Split: &esapi.PushSecretRewriteSplit{Separator: "-"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config-api", "config-port", "config-token"}, remoteKeys(entries))
+	assert.Equal(t, "config", entries[0].GetSecretKey())
+	assert.Equal(t, map[string][]byte{"config": []byte(`{"url":"https://api"}`)}, overrides["config-api"])
+	assert.Equal(t, map[string][]byte{"config": []byte("8080")}, overrides["config-port"])
+	assert.Equal(t, map[string][]byte{"config": []byte("s3cr3t")}, overrides["config-token"])
+
+	// merged keys can be split again.
+	entries, _, overrides, err = r.expandSingleDataTo(secret, esapi.PushSecretDataTo{
+		Match: &esapi.PushSecretDataToMatch{RegExp: "^DB_"},
+		Rewrite: []esapi.PushSecretRewrite{
+			{Merge: &esapi.PushSecretRewriteMerge{Into: "db"}},
+			{Split: &esapi.PushSecretRewriteSplit{}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/DB_HOST", "db/DB_USER"}, remoteKeys(entries))
+	assert.Equal(t, map[string][]byte{"DB_USER": []byte("app")}, overrides["db/DB_USER"])
+
+	// values which are not JSON objects can not be split.
+	_, _, _, err = r.expandSingleDataTo(secret, esapi.PushSecretDataTo{
+		Rewrite: []esapi.PushSecretRewrite{{Split: &esapi.PushSecretRewriteSplit{}}},
+	})
+	assert.ErrorContains(t, err, `rewrite failed: rewrite[0]: on key "DB_HOST": value is not a JSON object`)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	estemplate "github.com/external-secrets/external-secrets/runtime/template/v2"
+)
+
+const (
+	// indexPSSourceResourceField indexes PushSecrets by their source ConfigMap or resource (GVK + name).
+	indexPSSourceResourceField = ".spec.selector.resource"
+
+	errGenericSourcesDisabled = "generic sources are disabled. Enable with --unsafe-allow-generic-sources flag"
+	errGetSourceResource      = "could not get source %s %q: %w"
+	errExtractValue           = "could not extract value %q from source %s %q: %w"
+	errNoJSONPathMatch        = "jsonPath %q did not match any value"
+)
+
+var configMapGVK = v1.SchemeGroupVersion.WithKind("ConfigMap")
+
+// errSourcesDisabled is returned for generic sources unless they are enabled, retrying does not help.
+var errSourcesDisabled = errors.New(errGenericSourcesDisabled)
+
+// sourceResource returns the GVK and name of the ConfigMap or resource the PushSecret pushes from.
+// It returns false if the PushSecret pushes from a Secret or a generator.
+func sourceResource(ps *esapi.PushSecret) (schema.GroupVersionKind, string, bool) {
+	switch {
+	case ps.Spec.Selector.ConfigMap != nil:
+		return configMapGVK, ps.Spec.Selector.ConfigMap.Name, true
+	case ps.Spec.Selector.Resource != nil:
+		gvk := schema.FromAPIVersionAndKind(ps.Spec.Selector.Resource.APIVersion, ps.Spec.Selector.Resource.Kind)
+		return gvk, ps.Spec.Selector.Resource.Name, true
+	}
+	return schema.GroupVersionKind{}, "", false
+}
+
+// sourceResourceKey returns the index value of a source resource.
+func sourceResourceKey(gvk schema.GroupVersionKind, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Version, gvk.Kind, name)
+}
+
+// indexSourceResource returns the index values of the source resource of a PushSecret.
+func indexSourceResource(obj client.Object) []string {
+	ps := obj.(*esapi.PushSecret)
+	gvk, name, ok := sourceResource(ps)
+	if !ok {
+		return nil
+	}
+	return []string{sourceResourceKey(gvk, name)}
+}
+
+// pushSecretsForSource returns the reconcile requests for the PushSecrets pushing from the changed resource.
+func (r *Reconciler) pushSecretsForSource(ctx context.Context, gvk schema.GroupVersionKind, obj metav1.Object) []ctrl.Request {
+	var psList esapi.PushSecretList
+	if err := r.List(ctx, &psList,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector(indexPSSourceResourceField, sourceResourceKey(gvk, obj.GetName()))},
+	); err != nil {
+		r.Log.Error(err, "failed to list PushSecrets for source", "gvk", gvk.String(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(psList.Items))
+	for i := range psList.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&psList.Items[i])})
+	}
+	return requests
+}
+
+// trackSource keeps the informer for the source resource of the PushSecret running,
+// so changes of the source trigger a push. Informers of sources which are no longer used are released.
+func (r *Reconciler) trackSource(ctx context.Context, ps *esapi.PushSecret) error {
+	if r.informerManager == nil {
+		return nil
+	}
+	name := client.ObjectKeyFromObject(ps)
+	gvk, _, ok := sourceResource(ps)
+	if ps.Spec.Selector.Resource != nil && !r.AllowGenericSources {
+		ok = false
+	}
+	if !ok || !ps.DeletionTimestamp.IsZero() {
+		return r.releaseSource(ctx, name)
+	}
+
+	r.sourcesMu.Lock()
+	previous, tracked := r.sources[name]
+	r.sourcesMu.Unlock()
+	if tracked && previous != gvk {
+		if err := r.releaseSource(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.informerManager.EnsureInformer(ctx, gvk, name); err != nil {
+		return err
+	}
+	r.sourcesMu.Lock()
+	defer r.sourcesMu.Unlock()
+	if r.sources == nil {
+		r.sources = make(map[types.NamespacedName]schema.GroupVersionKind)
+	}
+	r.sources[name] = gvk
+	return nil
+}
+
+// releaseSource releases the informer of the source resource of a PushSecret, if any.
+func (r *Reconciler) releaseSource(ctx context.Context, name types.NamespacedName) error {
+	if r.informerManager == nil {
+		return nil
+	}
+	r.sourcesMu.Lock()
+	gvk, tracked := r.sources[name]
+	delete(r.sources, name)
+	r.sourcesMu.Unlock()
+	if !tracked {
+		return nil
+	}
+	return r.informerManager.ReleaseInformer(ctx, gvk, name)
+}
+
+// getSourceResource fetches the ConfigMap or resource the PushSecret pushes from.
+func (r *Reconciler) getSourceResource(ctx context.Context, ps *esapi.PushSecret) (*unstructured.Unstructured, error) {
+	gvk, name, _ := sourceResource(ps)
+	if ps.Spec.Selector.Resource != nil && !r.AllowGenericSources {
+		return nil, errSourcesDisabled
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: ps.Namespace}, obj); err != nil {
+		return nil, fmt.Errorf(errGetSourceResource, gvk.Kind, name, err)
+	}
+	return obj, nil
+}
+
+// fetchSourceResource fetches the source ConfigMap or resource of the PushSecret.
+// It returns nil if the PushSecret pushes from a Secret or a generator.
+func (r *Reconciler) fetchSourceResource(ctx context.Context, ps *esapi.PushSecret) (*unstructured.Unstructured, error) {
+	if _, _, ok := sourceResource(ps); !ok {
+		return nil, nil
+	}
+	return r.getSourceResource(ctx, ps)
+}
+
+// sourceChanged returns true if the fetched source ConfigMap or resource changed since it was last pushed.
+// A deleted source is changed, unless it was never pushed.
+func sourceChanged(ps *esapi.PushSecret, obj *unstructured.Unstructured) bool {
+	if _, _, ok := sourceResource(ps); !ok {
+		return false
+	}
+	var version string
+	if obj != nil {
+		version = obj.GetResourceVersion()
+	}
+	return version != ps.Status.SyncedSourceResourceVersion
+}
+
+// resolveSourceResource converts the fetched source ConfigMap or resource of the PushSecret to a Secret,
+// so it can be pushed like a source Secret.
+func (r *Reconciler) resolveSourceResource(ps *esapi.PushSecret, obj *unstructured.Unstructured) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            obj.GetName(),
+			Namespace:       obj.GetNamespace(),
+			Labels:          obj.GetLabels(),
+			Annotations:     obj.GetAnnotations(),
+			ResourceVersion: obj.GetResourceVersion(),
+		},
+		Data: make(map[string][]byte),
+	}
+
+	if ps.Spec.Selector.ConfigMap != nil {
+		var cm v1.ConfigMap
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &cm); err != nil {
+			return nil, fmt.Errorf(errGetSourceResource, configMapGVK.Kind, obj.GetName(), err)
+		}
+		for k, v := range cm.Data {
+			secret.Data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			secret.Data[k] = v
+		}
+		return secret, nil
+	}
+
+	for _, value := range ps.Spec.Selector.Resource.Values {
+		var (
+			out []byte
+			err error
+		)
+		if value.Template != "" {
+			out, err = executeSourceTemplate(value.Template, obj)
+		} else {
+			out, err = extractJSONPath(value.JSONPath, obj)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(errExtractValue, value.Key, obj.GetKind(), obj.GetName(), err)
+		}
+		secret.Data[value.Key] = out
+	}
+	return secret, nil
+}
+
+// extractJSONPath returns the value selected by a kubectl style JSONPath expression.
+// Strings are returned as is, other values are encoded as JSON.
+func extractJSONPath(expr string, obj *unstructured.Unstructured) ([]byte, error) {
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New("value")
+	if err := jp.Parse(expr); err != nil {
+		return nil, err
+	}
+	results, err := jp.FindResults(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []any
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, v.Interface())
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf(errNoJSONPathMatch, expr)
+	case 1:
+		if s, ok := values[0].(string); ok {
+			return []byte(s), nil
+		}
+		return json.Marshal(values[0])
+	}
+	return json.Marshal(values)
+}
+
+// executeSourceTemplate renders a template with the resource available as ".object".
+func executeSourceTemplate(text string, obj *unstructured.Unstructured) ([]byte, error) {
+	tmpl, err := template.New("value").Funcs(estemplate.FuncMap()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]any{"object": obj.Object}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
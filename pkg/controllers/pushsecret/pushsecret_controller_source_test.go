@@ -0,0 +1,129 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestResolveSourceResource(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esapi.AddToScheme(scheme))
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"host": "db.example.com"},
+			BinaryData: map[string][]byte{"ca": {0x01, 0x02}},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: v1.ServiceSpec{
+				ClusterIP: "10.0.0.1",
+				Ports:     []v1.ServicePort{{Name: "sql", Port: 5432}},
+			},
+		},
+	).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard(), Scheme: scheme}
+	ps := func(selector esapi.PushSecretSelector) *esapi.PushSecret {
+		return &esapi.PushSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "default"},
+			Spec:       esapi.PushSecretSpec{Selector: selector},
+		}
+	}
+	service := func(values ...esapi.PushSecretResourceValue) *esapi.PushSecret {
+		return ps(esapi.PushSecretSelector{Resource: &esapi.PushSecretResource{APIVersion: "v1", Kind: "Service", Name: "db", Values: values}})
+	}
+
+	resolve := func(ps *esapi.PushSecret) (*v1.Secret, error) {
+		obj, err := r.fetchSourceResource(ctx, ps)
+		if err != nil {
+			return nil, err
+		}
+		return r.resolveSourceResource(ps, obj)
+	}
+
+	// all keys of a ConfigMap are pushed.
+	secret, err := resolve(ps(esapi.PushSecretSelector{ConfigMap: &esapi.PushSecretConfigMap{Name: "config"}}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"host": []byte("db.example.com"), "ca": {0x01, 0x02}}, secret.Data)
+	assert.NotEmpty(t, secret.ResourceVersion)
+
+	// generic sources must be enabled.
+	_, err = resolve(service(esapi.PushSecretResourceValue{Key: "ip", JSONPath: "{.spec.clusterIP}"}))
+	assert.ErrorIs(t, err, errSourcesDisabled)
+
+	// values are extracted with JSONPath expressions and templates, other values than strings are encoded as JSON.
+	r.AllowGenericSources = true
+	secret, err = resolve(service(
+		esapi.PushSecretResourceValue{Key: "ip", JSONPath: "{.spec.clusterIP}"},
+		esapi.PushSecretResourceValue{Key: "port", JSONPath: ".spec.ports[0].port"},
+		esapi.PushSecretResourceValue{Key: "ports", JSONPath: "{.spec.ports[*].name}"},
+		esapi.PushSecretResourceValue{Key: "dsn", Template: "postgres://{{ .object.spec.clusterIP }}:{{ (index .object.spec.ports 0).port }}"},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"ip":    []byte("10.0.0.1"),
+		"port":  []byte("5432"),
+		"ports": []byte("sql"),
+		"dsn":   []byte("postgres://10.0.0.1:5432"),
+	}, secret.Data)
+
+	_, err = resolve(service(esapi.PushSecretResourceValue{Key: "missing", JSONPath: "{.spec.externalName}"}))
+	assert.ErrorContains(t, err, `could not extract value "missing" from source Service "db"`)
+
+	// a missing source is reported as not found, so the pushed secrets are deleted with DeletionPolicy=Delete.
+	_, err = resolve(ps(esapi.PushSecretSelector{ConfigMap: &esapi.PushSecretConfigMap{Name: "missing"}}))
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestSourceChanged(t *testing.T) {
+	configMap := &esapi.PushSecret{Spec: esapi.PushSecretSpec{Selector: esapi.PushSecretSelector{ConfigMap: &esapi.PushSecretConfigMap{Name: "config"}}}}
+	obj := &unstructured.Unstructured{}
+	obj.SetResourceVersion("2")
+
+	assert.True(t, sourceChanged(configMap, obj))
+	configMap.Status.SyncedSourceResourceVersion = "2"
+	assert.False(t, sourceChanged(configMap, obj))
+
+	// a deleted source is changed, unless it was never pushed.
+	assert.True(t, sourceChanged(configMap, nil))
+	configMap.Status.SyncedSourceResourceVersion = ""
+	assert.False(t, sourceChanged(configMap, nil))
+
+	// secrets and generators are not fetched as sources.
+	secret := &esapi.PushSecret{Spec: esapi.PushSecretSpec{Selector: esapi.PushSecretSelector{Secret: &esapi.PushSecretSecret{Name: "secret"}}}}
+	assert.False(t, sourceChanged(secret, nil))
+	source, err := (&Reconciler{}).fetchSourceResource(context.Background(), secret)
+	require.NoError(t, err)
+	assert.Nil(t, source)
+}
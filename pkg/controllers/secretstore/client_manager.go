@@ -42,10 +42,13 @@ const (
 	errClusterStoreMismatch  = "using cluster store %q is not allowed from namespace %q: denied by spec.condition"
 )
 
+// ErrUnmanagedStore is returned when the store is not handled by the controller class of the Manager.
+var ErrUnmanagedStore = errors.New("can not reference unmanaged store")
+
 // Manager stores instances of provider clients
 // At any given time we must have no more than one instance
-// of a client (due to limitations in GCP / see mutexlock there)
-// If the controller requests another instance of a given client
+// of a client per provider type (due to limitations in GCP / see mutexlock there)
+// If the controller requests a client of another store of the same provider type
 // we will close the old client first and then construct a new one.
 type Manager struct {
 	log             logr.Logger
@@ -53,17 +56,22 @@ type Manager struct {
 	controllerClass string
 	enableFloodgate bool
 
-	// store clients by provider type
+	// store clients by store
 	clientMap map[clientKey]*clientVal
+	// stores which failed during the lifetime of the manager
+	failedStores map[clientKey]error
 }
 
 type clientKey struct {
-	providerType string
+	kind      string
+	namespace string
+	name      string
 }
 
 type clientVal struct {
-	client esv1.SecretsClient
-	store  esv1.GenericStore
+	client       esv1.SecretsClient
+	store        esv1.GenericStore
+	providerType string
 }
 
 // NewManager constructs a new manager with defaults.
@@ -79,6 +87,8 @@ func NewManager(ctrlClient client.Client, controllerClass string, enableFloodgat
 }
 
 // GetFromStore returns a provider client from the given store.
+// If the store has a rate limit, it is enforced around every call of the client,
+// if it has a cache, reads of the client are coalesced and cached.
 // Do not close the client returned from this func, instead close
 // the manager once you're done with reconciling the external secret.
 func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, namespace string) (esv1.SecretsClient, error) {
@@ -86,9 +96,10 @@ func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, nam
 	if err != nil {
 		return nil, err
 	}
-	secretClient := m.getStoredClient(ctx, storeProvider, store)
+	idx := storeKey(store)
+	secretClient := m.getStoredClient(ctx, storeProvider, idx, store)
 	if secretClient != nil {
-		return secretClient, nil
+		return wrapClient(secretClient, store, namespace), nil
 	}
 	m.log.V(1).Info("creating new client",
 		"provider", fmt.Sprintf("%T", storeProvider),
@@ -99,12 +110,16 @@ func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, nam
 	if err != nil {
 		return nil, err
 	}
-	idx := storeKey(storeProvider)
+	// reads of stores with a cache are shared with other Manager instances, see readcache.go.
+	if store.GetSpec().Cache != nil {
+		secretClient = &sharedClient{SecretsClient: secretClient}
+	}
 	m.clientMap[idx] = &clientVal{
-		client: secretClient,
-		store:  store,
+		client:       secretClient,
+		store:        store,
+		providerType: providerType(storeProvider),
 	}
-	return secretClient, nil
+	return wrapClient(secretClient, store, namespace), nil
 }
 
 // Get returns a provider client from the given storeRef or sourceRef.secretStoreRef
@@ -121,7 +136,7 @@ func (m *Manager) Get(ctx context.Context, storeRef esv1.SecretStoreRef, namespa
 	}
 	// check if store should be handled by this controller instance
 	if !ShouldProcessStore(store, m.controllerClass) {
-		return nil, errors.New("can not reference unmanaged store")
+		return nil, ErrUnmanagedStore
 	}
 	// when using ClusterSecretStore, validate the ClusterSecretStore namespace conditions
 	shouldProcess, err := m.shouldProcessSecret(store, namespace)
@@ -144,45 +159,74 @@ func (m *Manager) Get(ctx context.Context, storeRef esv1.SecretStoreRef, namespa
 // returns a previously stored client from the cache if store and store-version match
 // if a client exists for the same provider which points to a different store or store version
 // it will be cleaned up.
-func (m *Manager) getStoredClient(ctx context.Context, storeProvider esv1.Provider, store esv1.GenericStore) esv1.SecretsClient {
-	idx := storeKey(storeProvider)
-	val, ok := m.clientMap[idx]
-	if !ok {
-		return nil
-	}
-	valGVK, err := m.client.GroupVersionKindFor(val.store)
-	if err != nil {
-		return nil
-	}
-	storeGVK, err := m.client.GroupVersionKindFor(store)
-	if err != nil {
-		return nil
-	}
+func (m *Manager) getStoredClient(ctx context.Context, storeProvider esv1.Provider, idx clientKey, store esv1.GenericStore) esv1.SecretsClient {
 	storeName := fmt.Sprintf("%s/%s", store.GetNamespace(), store.GetName())
 	// return client if it points to the very same store
-	if val.store.GetObjectMeta().Generation == store.GetGeneration() &&
-		valGVK == storeGVK &&
-		val.store.GetName() == store.GetName() &&
-		val.store.GetNamespace() == store.GetNamespace() {
+	if val, ok := m.clientMap[idx]; ok && val.store.GetObjectMeta().Generation == store.GetGeneration() {
 		m.log.V(1).Info("reusing stored client",
 			"provider", fmt.Sprintf("%T", storeProvider),
 			"store", storeName)
 		return val.client
 	}
-	m.log.V(1).Info("cleaning up client",
-		"provider", fmt.Sprintf("%T", storeProvider),
-		"store", storeName)
-	// if we have a client, but it points to a different store
-	// we must clean it up
-	_ = val.client.Close(ctx)
-	delete(m.clientMap, idx)
+	for key, val := range m.clientMap {
+		if val.providerType != providerType(storeProvider) {
+			continue
+		}
+		m.log.V(1).Info("cleaning up client",
+			"provider", val.providerType,
+			"store", fmt.Sprintf("%s/%s", val.store.GetNamespace(), val.store.GetName()))
+		// if we have a client of the same provider, but it points to a different store
+		// or store version we must clean it up
+		_ = val.client.Close(ctx)
+		delete(m.clientMap, key)
+	}
 	return nil
 }
 
-func storeKey(storeProvider esv1.Provider) clientKey {
+// MarkFailed remembers that the store failed with err, so that it can be skipped
+// for the rest of the lifetime of the manager.
+func (m *Manager) MarkFailed(storeRef esv1.SecretStoreRef, namespace string, err error) {
+	if m.failedStores == nil {
+		m.failedStores = make(map[clientKey]error)
+	}
+	m.failedStores[refKey(storeRef, namespace)] = err
+}
+
+// Failed returns the error the store was marked as failed with, or nil if it did not fail.
+func (m *Manager) Failed(storeRef esv1.SecretStoreRef, namespace string) error {
+	return m.failedStores[refKey(storeRef, namespace)]
+}
+
+// wrapClient wraps the client with the read cache and rate limit of the store.
+// The read cache wraps the rate limit, so cached reads do not count towards the limit.
+func wrapClient(secretClient esv1.SecretsClient, store esv1.GenericStore, namespace string) esv1.SecretsClient {
+	lease, _ := secretClient.(*sharedClient)
+	return withReadCache(withRateLimit(secretClient, store), lease, store, namespace)
+}
+
+// forgetStore drops the rate limit and read cache state of a deleted store.
+func forgetStore(id storeID) {
+	limiters.forget(id)
+	reads.forget(id)
+}
+
+func storeKey(store esv1.GenericStore) clientKey {
 	return clientKey{
-		providerType: fmt.Sprintf("%T", storeProvider),
+		kind:      store.GetKind(),
+		namespace: store.GetNamespace(),
+		name:      store.GetName(),
+	}
+}
+
+func refKey(storeRef esv1.SecretStoreRef, namespace string) clientKey {
+	if storeRef.Kind == esv1.ClusterSecretStoreKind {
+		return clientKey{kind: esv1.ClusterSecretStoreKind, name: storeRef.Name}
 	}
+	return clientKey{kind: esv1.SecretStoreKind, namespace: namespace, name: storeRef.Name}
+}
+
+func providerType(storeProvider esv1.Provider) string {
+	return fmt.Sprintf("%T", storeProvider)
 }
 
 // getStore fetches the (Cluster)SecretStore from the kube-apiserver
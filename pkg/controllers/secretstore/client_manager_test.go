@@ -18,6 +18,7 @@ package secretstore
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -97,9 +98,9 @@ func TestManagerGet(t *testing.T) {
 
 	var mgr *Manager
 
-	provKey := clientKey{
-		providerType: "*secretstore.WrapProvider",
-	}
+	const provType = "*secretstore.WrapProvider"
+	defaultKey := clientKey{kind: esv1.SecretStoreKind, namespace: testNamespace, name: defaultStore.Name}
+	otherKey := clientKey{kind: esv1.SecretStoreKind, namespace: testNamespace, name: otherStore.Name}
 
 	type fields struct {
 		client    client.Client
@@ -149,13 +150,13 @@ func TestManagerGet(t *testing.T) {
 				// we now must have this provider in the clientMap
 				// and it mustbe the client defined in clientConstructor
 				assert.NotNil(t, sc)
-				c, ok := mgr.clientMap[provKey]
+				c, ok := mgr.clientMap[defaultKey]
 				require.True(t, ok)
 				assert.Same(t, c.client, clientA)
 			},
 
 			afterClose: func() {
-				v, ok := mgr.clientMap[provKey]
+				v, ok := mgr.clientMap[defaultKey]
 				assert.False(t, ok)
 				assert.Nil(t, v)
 			},
@@ -191,13 +192,13 @@ func TestManagerGet(t *testing.T) {
 				// we now must have this provider in the clientMap
 				// and it mustbe the client defined in clientConstructor
 				assert.NotNil(t, sc)
-				c, ok := mgr.clientMap[provKey]
+				c, ok := mgr.clientMap[otherKey]
 				assert.True(t, ok)
 				assert.Same(t, c.client, clientB)
 			},
 
 			afterClose: func() {
-				v, ok := mgr.clientMap[provKey]
+				v, ok := mgr.clientMap[otherKey]
 				assert.False(t, ok)
 				assert.True(t, clientB.closeCalled)
 				assert.Nil(t, v)
@@ -212,9 +213,10 @@ func TestManagerGet(t *testing.T) {
 					WithObjects(defaultStore).
 					Build(),
 				clientMap: map[clientKey]*clientVal{
-					provKey: {
-						client: clientA,
-						store:  defaultStore,
+					defaultKey: {
+						client:       clientA,
+						store:        defaultStore,
+						providerType: provType,
 					},
 				},
 			},
@@ -235,14 +237,14 @@ func TestManagerGet(t *testing.T) {
 			verify: func(sc esv1.SecretsClient) {
 				// verify that the secretsClient is the one from cache
 				assert.NotNil(t, sc)
-				c, ok := mgr.clientMap[provKey]
+				c, ok := mgr.clientMap[defaultKey]
 				assert.True(t, ok)
 				assert.Same(t, c.client, clientA)
 				assert.Same(t, sc, clientA)
 			},
 
 			afterClose: func() {
-				v, ok := mgr.clientMap[provKey]
+				v, ok := mgr.clientMap[defaultKey]
 				assert.False(t, ok)
 				assert.True(t, clientA.closeCalled)
 				assert.Nil(t, v)
@@ -257,10 +259,11 @@ func TestManagerGet(t *testing.T) {
 					WithObjects(otherStore).
 					Build(),
 				clientMap: map[clientKey]*clientVal{
-					provKey: {
+					defaultKey: {
 						// we have clientA in cache pointing at defaultStore
-						client: clientA,
-						store:  defaultStore,
+						client:       clientA,
+						store:        defaultStore,
+						providerType: provType,
 					},
 				},
 			},
@@ -280,14 +283,16 @@ func TestManagerGet(t *testing.T) {
 			verify: func(sc esv1.SecretsClient) {
 				// verify that SecretsClient is NOT the one from cache
 				assert.NotNil(t, sc)
-				c, ok := mgr.clientMap[provKey]
+				c, ok := mgr.clientMap[otherKey]
 				assert.True(t, ok)
 				assert.Same(t, c.client, clientB)
 				assert.Same(t, sc, clientB)
 				assert.True(t, clientA.closeCalled)
+				_, ok = mgr.clientMap[defaultKey]
+				assert.False(t, ok)
 			},
 			afterClose: func() {
-				v, ok := mgr.clientMap[provKey]
+				v, ok := mgr.clientMap[otherKey]
 				assert.False(t, ok)
 				assert.True(t, clientB.closeCalled)
 				assert.Nil(t, v)
@@ -317,6 +322,23 @@ func TestManagerGet(t *testing.T) {
 	}
 }
 
+func TestManagerFailedStores(t *testing.T) {
+	mgr := NewManager(nil, "", false)
+	storeRef := esv1.SecretStoreRef{Name: "foo"}
+	clusterStoreRef := esv1.SecretStoreRef{Name: "foo", Kind: esv1.ClusterSecretStoreKind}
+	err := errors.New("provider unavailable")
+
+	assert.NoError(t, mgr.Failed(storeRef, "ns-a"))
+	mgr.MarkFailed(storeRef, "ns-a", err)
+	assert.ErrorIs(t, mgr.Failed(storeRef, "ns-a"), err)
+	// a SecretStore with the same name in another namespace is a different store.
+	assert.NoError(t, mgr.Failed(storeRef, "ns-b"))
+	assert.NoError(t, mgr.Failed(clusterStoreRef, "ns-a"))
+
+	mgr.MarkFailed(clusterStoreRef, "ns-a", err)
+	assert.ErrorIs(t, mgr.Failed(clusterStoreRef, "ns-b"), err)
+}
+
 func TestShouldProcessSecret(t *testing.T) {
 	scheme := runtime.NewScheme()
 
@@ -67,6 +67,7 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	err := r.Get(ctx, req.NamespacedName, &css)
 	if apierrors.IsNotFound(err) {
 		cssmetrics.RemoveMetrics(req.Namespace, req.Name)
+		forgetStore(storeID{kind: esapi.ClusterSecretStoreKind, namespace: req.Namespace, name: req.Name})
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get ClusterSecretStore")
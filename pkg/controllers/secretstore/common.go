@@ -156,6 +156,7 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 	capStatus := esapi.SecretStoreStatus{
 		Capabilities: storeProvider.Capabilities(),
 		Conditions:   ss.GetStatus().Conditions,
+		RateLimit:    limiters.status(ss),
 	}
 	ss.SetStatus(capStatus)
 
@@ -0,0 +1,267 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/metrics"
+)
+
+const (
+	limitQPS      = "qps"
+	limitInFlight = "in_flight"
+)
+
+// limiters holds the rate limiters of all stores.
+// It is shared by all Manager instances, so the limits of a store apply
+// to all ExternalSecrets and PushSecrets using it, across namespaces for ClusterSecretStores.
+var limiters = newLimiterRegistry()
+
+type storeID struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func storeIDFor(store esv1.GenericStore) storeID {
+	return storeID{kind: store.GetKind(), namespace: store.GetNamespace(), name: store.GetName()}
+}
+
+type limiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[storeID]*storeLimiter
+}
+
+func newLimiterRegistry() *limiterRegistry {
+	return &limiterRegistry{limiters: make(map[storeID]*storeLimiter)}
+}
+
+// get returns the limiter of a store, or nil if the store has no rate limit.
+// The limiter is replaced if the rate limit of the store changed, the throttling stats are kept.
+func (r *limiterRegistry) get(store esv1.GenericStore) *storeLimiter {
+	id := storeIDFor(store)
+	spec := store.GetSpec().RateLimit
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.limiters[id]
+	if spec == nil {
+		delete(r.limiters, id)
+		return nil
+	}
+	if current != nil && current.spec == *spec {
+		return current
+	}
+	limiter := newStoreLimiter(id, *spec)
+	if current != nil {
+		limiter.stats = current.stats
+	}
+	r.limiters[id] = limiter
+	return limiter
+}
+
+// status returns the throttling status of a store, or nil if the store has no rate limit.
+func (r *limiterRegistry) status(store esv1.GenericStore) *esv1.SecretStoreRateLimitStatus {
+	if store.GetSpec().RateLimit == nil {
+		return nil
+	}
+	r.mu.Lock()
+	limiter := r.limiters[storeIDFor(store)]
+	r.mu.Unlock()
+	status := &esv1.SecretStoreRateLimitStatus{}
+	if limiter == nil {
+		return status
+	}
+	status.ThrottledRequests = limiter.stats.throttled.Load()
+	if last := limiter.stats.lastThrottled.Load(); last != 0 {
+		status.LastThrottledTime = new(metav1.NewTime(time.Unix(0, last)))
+	}
+	return status
+}
+
+// forget removes the limiter of a deleted store.
+func (r *limiterRegistry) forget(id storeID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.limiters, id)
+}
+
+type throttleStats struct {
+	throttled     atomic.Int64
+	lastThrottled atomic.Int64
+}
+
+// storeLimiter enforces the rate limit of a single store.
+type storeLimiter struct {
+	id       storeID
+	spec     esv1.SecretStoreRateLimit
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+	stats    *throttleStats
+}
+
+func newStoreLimiter(id storeID, spec esv1.SecretStoreRateLimit) *storeLimiter {
+	l := &storeLimiter{id: id, spec: spec, stats: &throttleStats{}}
+	if spec.QPS > 0 {
+		burst := spec.Burst
+		if burst <= 0 {
+			burst = spec.QPS
+		}
+		l.limiter = rate.NewLimiter(rate.Limit(spec.QPS), int(burst))
+	}
+	if spec.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, spec.MaxInFlight)
+	}
+	return l
+}
+
+// acquire blocks until a request may be sent to the provider, or the context is done.
+// The returned func must be called once the request completed.
+func (l *storeLimiter) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+	var throttledBy string
+	if l.limiter != nil && !l.limiter.Allow() {
+		throttledBy = limitQPS
+		if err := l.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		default:
+			if throttledBy == "" {
+				throttledBy = limitInFlight
+			}
+			select {
+			case l.inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		// capture the channel, the limiter may be replaced while the request is in flight.
+		inFlight := l.inFlight
+		release = func() { <-inFlight }
+	}
+	if throttledBy != "" {
+		l.stats.throttled.Add(1)
+		l.stats.lastThrottled.Store(time.Now().UnixNano())
+		metrics.ObserveThrottledRequest(l.id.kind, l.id.namespace, l.id.name, throttledBy, time.Since(start))
+	}
+	return release, nil
+}
+
+// rateLimitedClient wraps a SecretsClient and enforces the rate limit of its store around every call.
+type rateLimitedClient struct {
+	esv1.SecretsClient
+	limiter *storeLimiter
+}
+
+var (
+	_ esv1.SecretsClient      = &rateLimitedClient{}
+	_ esv1.SecretsSoftDeleter = &rateLimitedClient{}
+)
+
+func (c *rateLimitedClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.SecretsClient.GetSecret(ctx, ref)
+}
+
+func (c *rateLimitedClient) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.SecretsClient.PushSecret(ctx, secret, data)
+}
+
+func (c *rateLimitedClient) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+}
+
+func (c *rateLimitedClient) SoftDeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return esv1.SoftDeleteSecret(ctx, c.SecretsClient, remoteRef)
+}
+
+func (c *rateLimitedClient) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (bool, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	return c.SecretsClient.SecretExists(ctx, remoteRef)
+}
+
+func (c *rateLimitedClient) Validate() (esv1.ValidationResult, error) {
+	release, err := c.limiter.acquire(context.Background())
+	if err != nil {
+		return esv1.ValidationResultUnknown, err
+	}
+	defer release()
+	return c.SecretsClient.Validate()
+}
+
+func (c *rateLimitedClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.SecretsClient.GetSecretMap(ctx, ref)
+}
+
+func (c *rateLimitedClient) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.SecretsClient.GetAllSecrets(ctx, ref)
+}
+
+// withRateLimit wraps the client with the rate limit of the store, if any.
+func withRateLimit(secretClient esv1.SecretsClient, store esv1.GenericStore) esv1.SecretsClient {
+	limiter := limiters.get(store)
+	if limiter == nil {
+		return secretClient
+	}
+	return &rateLimitedClient{SecretsClient: secretClient, limiter: limiter}
+}
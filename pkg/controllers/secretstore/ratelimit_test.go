@@ -0,0 +1,89 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func newRateLimitedStore(name string, rateLimit *esv1.SecretStoreRateLimit) *esv1.ClusterSecretStore {
+	return &esv1.ClusterSecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       esv1.SecretStoreSpec{RateLimit: rateLimit},
+	}
+}
+
+func TestRateLimitQPS(t *testing.T) {
+	store := newRateLimitedStore("qps", &esv1.SecretStoreRateLimit{QPS: 20, Burst: 1})
+	t.Cleanup(func() { limiters.forget(storeIDFor(store)) })
+
+	// clients of the same store share the limit, e.g. ExternalSecrets of different namespaces.
+	clientA := withRateLimit(&MockFakeClient{id: "a"}, store)
+	clientB := withRateLimit(&MockFakeClient{id: "b"}, store)
+
+	start := time.Now()
+	_, err := clientA.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "key"})
+	require.NoError(t, err)
+	_, err = clientB.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "key"})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	status := limiters.status(store)
+	require.NotNil(t, status)
+	assert.Equal(t, int64(1), status.ThrottledRequests)
+	assert.NotNil(t, status.LastThrottledTime)
+
+	// changing the limit keeps the stats.
+	store.Spec.RateLimit = &esv1.SecretStoreRateLimit{QPS: 100}
+	withRateLimit(&MockFakeClient{}, store)
+	assert.Equal(t, int64(1), limiters.status(store).ThrottledRequests)
+
+	// removing the limit returns the plain client.
+	store.Spec.RateLimit = nil
+	plain := &MockFakeClient{}
+	assert.Same(t, plain, withRateLimit(plain, store))
+	assert.Nil(t, limiters.status(store))
+}
+
+func TestRateLimitMaxInFlight(t *testing.T) {
+	store := newRateLimitedStore("in-flight", &esv1.SecretStoreRateLimit{MaxInFlight: 1})
+	t.Cleanup(func() { limiters.forget(storeIDFor(store)) })
+
+	limiter := limiters.get(store)
+	release, err := limiter.acquire(context.Background())
+	require.NoError(t, err)
+
+	// the second request waits until the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = withRateLimit(&MockFakeClient{}, store).GetSecretMap(ctx, esv1.ExternalSecretDataRemoteRef{Key: "key"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// once the first request completed, the next one is sent without waiting.
+	release()
+	_, err = withRateLimit(&MockFakeClient{}, store).GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "key"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), limiters.status(store).ThrottledRequests)
+}
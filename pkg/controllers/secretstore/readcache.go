@@ -0,0 +1,298 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/cache"
+)
+
+const (
+	readCacheSize = 2048
+
+	// readTimeout bounds a coalesced read, as it is detached from the contexts of its callers.
+	readTimeout = 2 * time.Minute
+
+	callGetSecret     = "GetSecret"
+	callGetSecretMap  = "GetSecretMap"
+	callGetAllSecrets = "GetAllSecrets"
+)
+
+// reads coalesces and caches the reads of all stores.
+// It is shared by all Manager instances, so concurrent reconciles of ExternalSecrets
+// reading the same remote ref share a single call to the provider.
+var reads = newReadCache(readCacheSize)
+
+type readCacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+type readCache struct {
+	group singleflight.Group
+	cache *cache.Cache[*readCacheEntry]
+	// waiting is the number of callers waiting for a coalesced read.
+	waiting atomic.Int32
+
+	// epochs are bumped on writes through a store, which invalidates its cached reads.
+	mu     sync.Mutex
+	epochs map[storeID]uint64
+}
+
+func newReadCache(size int) *readCache {
+	return &readCache{
+		cache:  cache.Must[*readCacheEntry](size, nil),
+		epochs: make(map[storeID]uint64),
+	}
+}
+
+// version returns the version of the cached reads of a store.
+// It changes whenever the store is recreated, its spec changes or a secret is written through it.
+func (r *readCache) version(store esv1.GenericStore) string {
+	r.mu.Lock()
+	epoch := r.epochs[storeIDFor(store)]
+	r.mu.Unlock()
+	return fmt.Sprintf("%s/%d/%d", store.GetObjectMeta().UID, store.GetGeneration(), epoch)
+}
+
+// invalidate drops the cached reads of a store.
+func (r *readCache) invalidate(store esv1.GenericStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.epochs[storeIDFor(store)]++
+}
+
+// forget removes the state of a deleted store, its cached reads are evicted by the lru.
+func (r *readCache) forget(id storeID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.epochs, id)
+}
+
+// sharedClient is a provider client whose reads may be shared with the reconciles of other Manager instances.
+// Closing it is deferred until the coalesced reads running on it have finished.
+type sharedClient struct {
+	esv1.SecretsClient
+
+	mu     sync.Mutex
+	refs   int
+	closed bool
+}
+
+// acquire keeps the client open until release is called.
+func (c *sharedClient) acquire() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refs++
+}
+
+// release closes the client if it was closed while it was acquired.
+func (c *sharedClient) release() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refs--
+	if c.refs == 0 && c.closed {
+		_ = c.SecretsClient.Close(context.Background())
+	}
+}
+
+// Close closes the client, or defers it until the last coalesced read running on it has finished.
+func (c *sharedClient) Close(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.refs > 0 {
+		return nil
+	}
+	return c.SecretsClient.Close(ctx)
+}
+
+// SoftDeleteSecret soft deletes the secret if the wrapped client supports it.
+func (c *sharedClient) SoftDeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	return esv1.SoftDeleteSecret(ctx, c.SecretsClient, remoteRef)
+}
+
+// cachingClient wraps a SecretsClient, coalesces concurrent identical reads
+// and caches their results for the TTL of the store cache.
+type cachingClient struct {
+	esv1.SecretsClient
+	// lease keeps the client open while a read of the client is shared with other callers.
+	lease     *sharedClient
+	reads     *readCache
+	store     esv1.GenericStore
+	namespace string
+	ttl       time.Duration
+}
+
+var (
+	_ esv1.SecretsClient      = &cachingClient{}
+	_ esv1.SecretsSoftDeleter = &cachingClient{}
+	_ esv1.SecretsSoftDeleter = &sharedClient{}
+)
+
+// key returns the cache key of a read.
+// Clients of ClusterSecretStores are created for a namespace, which may change their credentials,
+// so the namespace is part of the key.
+func (c *cachingClient) key(call string, ref any) (cache.Key, error) {
+	refKey, err := json.Marshal(ref)
+	if err != nil {
+		return cache.Key{}, err
+	}
+	return cache.Key{
+		Kind:      c.store.GetKind(),
+		Namespace: c.store.GetNamespace(),
+		Name:      c.store.GetName() + "|" + c.namespace + "|" + call + "|" + string(refKey),
+	}, nil
+}
+
+func (c *cachingClient) read(ctx context.Context, call string, ref any, fetch func(context.Context) (any, error)) (any, error) {
+	key, err := c.key(call, ref)
+	if err != nil {
+		return fetch(ctx)
+	}
+	version := c.reads.version(c.store)
+	if c.ttl > 0 {
+		if entry, ok := c.reads.cache.Get(version, key); ok && time.Now().Before(entry.expires) {
+			return entry.value, nil
+		}
+	}
+	// the read is shared by all callers, so it must not be canceled with the context of the first one.
+	// Every caller stops waiting when its own context is done, but keeps its client open until the read
+	// has finished, as the read may run on it.
+	c.lease.acquire()
+	ch := c.reads.group.DoChan(version+"|"+key.Kind+"|"+key.Namespace+"|"+key.Name, func() (any, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), readTimeout)
+		defer cancel()
+		value, err := fetch(fetchCtx)
+		if err == nil && c.ttl > 0 {
+			c.reads.cache.Add(version, key, &readCacheEntry{value: value, expires: time.Now().Add(c.ttl)})
+		}
+		return value, err
+	})
+	c.reads.waiting.Add(1)
+	defer c.reads.waiting.Add(-1)
+	select {
+	case res := <-ch:
+		c.lease.release()
+		return res.Val, res.Err
+	case <-ctx.Done():
+		go func() {
+			<-ch
+			c.lease.release()
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *cachingClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	value, err := c.read(ctx, callGetSecret, ref, func(ctx context.Context) (any, error) {
+		return c.SecretsClient.GetSecret(ctx, ref)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the value is shared with other callers, hand out a copy.
+	return bytes.Clone(value.([]byte)), nil
+}
+
+func (c *cachingClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	value, err := c.read(ctx, callGetSecretMap, ref, func(ctx context.Context) (any, error) {
+		return c.SecretsClient.GetSecretMap(ctx, ref)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSecretMap(value.(map[string][]byte)), nil
+}
+
+func (c *cachingClient) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	value, err := c.read(ctx, callGetAllSecrets, ref, func(ctx context.Context) (any, error) {
+		return c.SecretsClient.GetAllSecrets(ctx, ref)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSecretMap(value.(map[string][]byte)), nil
+}
+
+func (c *cachingClient) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	defer c.reads.invalidate(c.store)
+	return c.SecretsClient.PushSecret(ctx, secret, data)
+}
+
+func (c *cachingClient) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	defer c.reads.invalidate(c.store)
+	return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+}
+
+func (c *cachingClient) SoftDeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	defer c.reads.invalidate(c.store)
+	return esv1.SoftDeleteSecret(ctx, c.SecretsClient, remoteRef)
+}
+
+func cloneSecretMap(in map[string][]byte) map[string][]byte {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string][]byte, len(in))
+	for k, v := range in {
+		out[k] = bytes.Clone(v)
+	}
+	return out
+}
+
+// WithoutReadCache returns the client of a store without its read cache,
+// for reads which must return the current value in the provider.
+// Writes must go through the cached client, so its cached reads are invalidated.
+func WithoutReadCache(secretClient esv1.SecretsClient) esv1.SecretsClient {
+	if c, ok := secretClient.(*cachingClient); ok {
+		return c.SecretsClient
+	}
+	return secretClient
+}
+
+// withReadCache wraps the client with the read cache of the store, if any.
+// Concurrent identical reads are coalesced, results are only cached if the cache has a TTL.
+// The lease is the client the reads run on, it is kept open while they are shared.
+func withReadCache(secretClient esv1.SecretsClient, lease *sharedClient, store esv1.GenericStore, namespace string) esv1.SecretsClient {
+	cacheSpec := store.GetSpec().Cache
+	if cacheSpec == nil {
+		return secretClient
+	}
+	var ttl time.Duration
+	if cacheSpec.TTL != nil {
+		ttl = cacheSpec.TTL.Duration
+	}
+	return &cachingClient{SecretsClient: secretClient, lease: lease, reads: reads, store: store, namespace: namespace, ttl: ttl}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+type countingClient struct {
+	MockFakeClient
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (c *countingClient) GetSecret(_ context.Context, _ esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.calls.Add(1)
+	if c.release != nil {
+		<-c.release
+	}
+	return []byte("value"), nil
+}
+
+func newCachedStore(name string, cacheSpec *esv1.SecretStoreCache) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "uid", Generation: 1},
+		Spec:       esv1.SecretStoreSpec{Cache: cacheSpec},
+	}
+}
+
+func TestReadCacheCoalescing(t *testing.T) {
+	store := newCachedStore("coalescing", &esv1.SecretStoreCache{})
+	fake := &countingClient{release: make(chan struct{})}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+	rc := newReadCache(readCacheSize)
+	newClient := func() esv1.SecretsClient {
+		return &cachingClient{SecretsClient: fake, reads: rc, store: store, namespace: "default"}
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			value, err := newClient().GetSecret(context.Background(), ref)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value"), value)
+		})
+	}
+	// a caller whose context is canceled stops waiting, without canceling the read of the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := newClient().GetSecret(ctx, ref)
+		canceled <- err
+	}()
+	// the fetch is blocked until all callers joined it.
+	require.Eventually(t, func() bool { return rc.waiting.Load() == 6 }, 5*time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	close(fake.release)
+	wg.Wait()
+	assert.Equal(t, int32(1), fake.calls.Load())
+
+	// without a TTL, reads are not cached.
+	_, err := newClient().GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fake.calls.Load())
+
+	// stores without a cache are not wrapped.
+	plain := &MockFakeClient{}
+	assert.Same(t, plain, withReadCache(plain, nil, newCachedStore("plain", nil), "default"))
+}
+
+func TestReadCacheSharedClientClose(t *testing.T) {
+	store := newCachedStore("shared", &esv1.SecretStoreCache{})
+	fake := &countingClient{release: make(chan struct{})}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+	rc := newReadCache(readCacheSize)
+	first := &sharedClient{SecretsClient: fake}
+	newClient := func(lease *sharedClient) esv1.SecretsClient {
+		return &cachingClient{SecretsClient: lease, lease: lease, reads: rc, store: store, namespace: "default"}
+	}
+
+	// the first caller starts the read on its client and gives up waiting.
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := newClient(first).GetSecret(ctx, ref)
+		canceled <- err
+	}()
+	require.Eventually(t, func() bool { return fake.calls.Load() == 1 }, 5*time.Second, time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		value, err := newClient(&sharedClient{SecretsClient: &MockFakeClient{}}).GetSecret(context.Background(), ref)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+	}()
+	require.Eventually(t, func() bool { return rc.waiting.Load() == 2 }, 5*time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+
+	// closing the client of the first caller is deferred until the shared read has finished.
+	require.NoError(t, first.Close(context.Background()))
+	assert.False(t, fake.closeCalled)
+	close(fake.release)
+	<-done
+	require.Eventually(t, func() bool {
+		first.mu.Lock()
+		defer first.mu.Unlock()
+		return first.refs == 0
+	}, 5*time.Second, time.Millisecond)
+	assert.True(t, fake.closeCalled)
+}
+
+func TestReadCacheTTL(t *testing.T) {
+	store := newCachedStore("ttl", &esv1.SecretStoreCache{TTL: &metav1.Duration{Duration: time.Minute}})
+	// the reads of previous runs must not be served from the shared cache.
+	shared := reads
+	reads = newReadCache(readCacheSize)
+	t.Cleanup(func() { reads = shared })
+	fake := &countingClient{}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+	get := func(namespace string) []byte {
+		value, err := withReadCache(fake, nil, store, namespace).GetSecret(context.Background(), ref)
+		require.NoError(t, err)
+		return value
+	}
+
+	// the cached value is handed out as a copy.
+	get("default")[0] = 'x'
+	assert.Equal(t, []byte("value"), get("default"))
+	assert.Equal(t, int32(1), fake.calls.Load())
+
+	// clients of other namespaces do not share the cache.
+	get("other")
+	assert.Equal(t, int32(2), fake.calls.Load())
+
+	// writes through the store invalidate the cache.
+	require.NoError(t, withReadCache(fake, nil, store, "default").PushSecret(context.Background(), &corev1.Secret{}, nil))
+	get("default")
+	assert.Equal(t, int32(3), fake.calls.Load())
+
+	// so do changes of the store spec.
+	store.Generation++
+	get("default")
+	get("default")
+	assert.Equal(t, int32(4), fake.calls.Load())
+
+	// reads without the read cache always reach the provider.
+	uncached := WithoutReadCache(withReadCache(fake, nil, store, "default"))
+	_, err := uncached.GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), fake.calls.Load())
+	assert.Same(t, fake, WithoutReadCache(fake))
+}
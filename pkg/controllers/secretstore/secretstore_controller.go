@@ -67,6 +67,7 @@ func (r *StoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	err := r.Get(ctx, req.NamespacedName, &ss)
 	if apierrors.IsNotFound(err) {
 		ssmetrics.RemoveMetrics(req.Namespace, req.Name)
+		forgetStore(storeID{kind: esapi.SecretStoreKind, namespace: req.Namespace, name: req.Name})
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get SecretStore")
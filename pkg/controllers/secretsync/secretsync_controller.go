@@ -0,0 +1,441 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretsync implements the controller for managing SecretSync resources.
+package secretsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	indexTargetSecretField = ".spec.target.name"
+
+	errPatchStatus     = "error merging"
+	errGetSS           = "could not get SecretSync"
+	errGetClient       = "could not get secrets client for store %v: %w"
+	errGetSecret       = "could not get target secret"
+	errGetRemote       = "could not read remote ref %v of key %v: %w"
+	errPushRemote      = "could not write key %v to remote ref %v: %w"
+	errWriteSecret     = "could not write target secret: %w"
+
+	msgSynced            = "SecretSync synced successfully"
+	msgConflict          = "values were changed both in the Secret and in the provider since the last sync: %s"
+	msgConflictResolved  = "values of %s were changed on both sides, resolved with ConflictPolicy=%s"
+	msgPushed            = "pushed %s to the provider"
+	msgPulled            = "updated %s from the provider"
+	msgUnmanagedStoreRef = "skipping SecretSync, the store is not managed by this controller"
+)
+
+// Reconciler is the controller for SecretSync resources.
+// It keeps the keys of a Secret and their secrets in the provider in sync,
+// propagating the changes of whichever side changed since the last sync.
+type Reconciler struct {
+	client.Client
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	recorder        record.EventRecorder
+	RequeueInterval time.Duration
+	ControllerClass string
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts controller.Options) error {
+	r.recorder = mgr.GetEventRecorderFor("secretsync")
+
+	// Index SecretSyncs by their Secret, so changes of the Secret are synced right away
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &esapi.SecretSync{}, indexTargetSecretField, func(obj client.Object) []string {
+		ss := obj.(*esapi.SecretSync)
+		return []string{ss.Spec.Target.Name}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&esapi.SecretSync{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+		))).
+		// we use WatchesMetadata() to reduce memory usage, as otherwise we have to process full secret objects.
+		WatchesMetadata(
+			&v1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// findObjectsForSecret returns the SecretSyncs which sync the Secret.
+func (r *Reconciler) findObjectsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	var secretSyncs esapi.SecretSyncList
+	if err := r.List(ctx, &secretSyncs, client.InNamespace(secret.GetNamespace()), client.MatchingFields{
+		indexTargetSecretField: secret.GetName(),
+	}); err != nil {
+		r.Log.Error(err, errGetSS)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(secretSyncs.Items))
+	for i := range secretSyncs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      secretSyncs.Items[i].Name,
+			Namespace: secretSyncs.Items[i].Namespace,
+		}})
+	}
+	return requests
+}
+
+// Reconcile syncs the Secret and the provider of a SecretSync.
+// Changes of the Secret are synced right away, changes in the provider are picked up on refresh.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("secretsync", req.NamespacedName)
+
+	var ss esapi.SecretSync
+	if err := r.Get(ctx, req.NamespacedName, &ss); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to get SecretSync")
+		return ctrl.Result{}, fmt.Errorf("get resource: %w", err)
+	}
+
+	refreshInt := r.RequeueInterval
+	if ss.Spec.RefreshInterval != nil {
+		refreshInt = ss.Spec.RefreshInterval.Duration
+	}
+
+	secretChanged, err := r.secretChanged(ctx, &ss)
+	if err != nil {
+		log.Error(err, errGetSecret)
+		return ctrl.Result{}, err
+	}
+	if !secretChanged && !shouldRefresh(ss, refreshInt) {
+		next := refreshInt - time.Since(ss.Status.RefreshTime.Time) + 5*time.Second
+		log.V(1).Info("skipping refresh", "rv", ctrlutil.GetResourceVersion(ss.ObjectMeta), "nr", next.Seconds())
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	p := client.MergeFrom(ss.DeepCopy())
+	defer func() {
+		if err := r.Status().Patch(ctx, &ss, p); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, errPatchStatus)
+		}
+	}()
+
+	mgr := secretstore.NewManager(r.Client, r.ControllerClass, false)
+	defer func() {
+		_ = mgr.Close(ctx)
+	}()
+	storeRef := esv1.SecretStoreRef{Name: ss.Spec.SecretStoreRef.Name, Kind: ss.Spec.SecretStoreRef.Kind}
+	secretClient, err := mgr.Get(ctx, storeRef, ss.Namespace, nil)
+	if errors.Is(err, secretstore.ErrUnmanagedStore) {
+		log.V(1).Info(msgUnmanagedStoreRef, "store", storeRef.Name)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		err = fmt.Errorf(errGetClient, storeRef.Name, err)
+		r.markAsFailed(&ss, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	secret, err := r.getTargetSecret(ctx, &ss)
+	if err != nil {
+		r.markAsFailed(&ss, errGetSecret)
+		return ctrl.Result{}, err
+	}
+
+	res, err := r.syncData(ctx, &ss, secret, secretClient)
+	r.setStatus(&ss, res)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("target secret was changed during the sync, retrying", "error", err.Error())
+			return ctrl.Result{Requeue: true}, nil
+		}
+		r.markAsFailed(&ss, err.Error())
+		return ctrl.Result{}, err
+	}
+	r.markAsDone(&ss, secret, res)
+	return ctrl.Result{RequeueAfter: refreshInt}, nil
+}
+
+func shouldRefresh(ss esapi.SecretSync, refreshInt time.Duration) bool {
+	if ss.Status.SyncedResourceVersion != ctrlutil.GetResourceVersion(ss.ObjectMeta) {
+		return true
+	}
+	if refreshInt == 0 && ss.Status.SyncedResourceVersion != "" {
+		return false
+	}
+	if ss.Status.RefreshTime.IsZero() {
+		return true
+	}
+	return ss.Status.RefreshTime.Add(refreshInt).Before(time.Now())
+}
+
+// secretChanged returns true if the Secret was changed since the last sync.
+// Only its metadata is read, from the cache of the metadata watch.
+func (r *Reconciler) secretChanged(ctx context.Context, ss *esapi.SecretSync) (bool, error) {
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Secret"))
+	err := r.Get(ctx, client.ObjectKey{Name: ss.Spec.Target.Name, Namespace: ss.Namespace}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	return secret.ResourceVersion != ss.Status.SyncedSecretResourceVersion, nil
+}
+
+// getTargetSecret returns the target Secret, or a Secret without UID if it does not exist yet.
+func (r *Reconciler) getTargetSecret(ctx context.Context, ss *esapi.SecretSync) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Name: ss.Spec.Target.Name, Namespace: ss.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: ss.Spec.Target.Name, Namespace: ss.Namespace},
+			Type:       v1.SecretTypeOpaque,
+		}, nil
+	}
+	return secret, err
+}
+
+// syncResult is the outcome of syncing the data of a SecretSync.
+type syncResult struct {
+	// hashes are the hashes of all keys after the sync, including those which were not synced.
+	hashes map[string]esapi.SecretSyncHashes
+	// conflicts are the keys which were changed on both sides and not synced because of ConflictPolicy=Halt.
+	conflicts []string
+	// resolved are the keys which were changed on both sides and synced according to the conflict policy.
+	resolved []string
+	pushed   []string
+	pulled   []string
+}
+
+// syncData compares the values of every key on both sides with their hashes after the last sync
+// and propagates the value of the side which changed.
+// A value that only exists on one side is copied to the other side, deletions are not propagated.
+// The result is valid even if an error is returned, it contains the keys synced so far.
+func (r *Reconciler) syncData(ctx context.Context, ss *esapi.SecretSync, secret *v1.Secret, secretClient esv1.SecretsClient) (*syncResult, error) {
+	res := &syncResult{hashes: maps.Clone(ss.Status.SyncedHashes)}
+	if res.hashes == nil {
+		res.hashes = make(map[string]esapi.SecretSyncHashes)
+	}
+	// pulled values are written to the Secret at once, their hashes are recorded afterwards.
+	pulled := make(map[string][]byte)
+
+	for _, data := range ss.Spec.Data {
+		key := data.SecretKey
+		local, localOK := secret.Data[key]
+		remote, err := secretClient.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: data.GetRemoteKey(), Property: data.GetProperty()})
+		remoteOK := err == nil
+		if err != nil && !errors.Is(err, esv1.NoSecretErr) {
+			return res, fmt.Errorf(errGetRemote, remoteRef(data), key, err)
+		}
+
+		var push bool
+		switch {
+		case !localOK && !remoteOK:
+			delete(res.hashes, key)
+			continue
+		case !remoteOK:
+			push = true
+		case !localOK:
+			push = false
+		case bytes.Equal(local, remote):
+			res.hashes[key] = esapi.SecretSyncHashes{Local: esutils.ObjectHash(local), Remote: esutils.ObjectHash(remote)}
+			continue
+		default:
+			recorded, synced := res.hashes[key]
+			localChanged := !synced || esutils.ObjectHash(local) != recorded.Local
+			remoteChanged := !synced || esutils.ObjectHash(remote) != recorded.Remote
+			switch {
+			case !localChanged && !remoteChanged:
+				continue
+			case localChanged && remoteChanged:
+				switch ss.Spec.ConflictPolicy {
+				case esapi.SecretSyncConflictPolicyPreferLocal:
+					push = true
+				case esapi.SecretSyncConflictPolicyPreferRemote:
+					push = false
+				default:
+					res.conflicts = append(res.conflicts, key)
+					continue
+				}
+				res.resolved = append(res.resolved, key)
+			default:
+				push = localChanged
+			}
+		}
+
+		if !push {
+			pulled[key] = remote
+			continue
+		}
+		if err := secretClient.PushSecret(ctx, secret, data); err != nil {
+			return res, fmt.Errorf(errPushRemote, key, remoteRef(data), err)
+		}
+		res.pushed = append(res.pushed, key)
+		// the value is read back, providers may store it differently than it was pushed.
+		remote, err = secretClient.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: data.GetRemoteKey(), Property: data.GetProperty()})
+		if err != nil {
+			return res, fmt.Errorf(errGetRemote, remoteRef(data), key, err)
+		}
+		res.hashes[key] = esapi.SecretSyncHashes{Local: esutils.ObjectHash(local), Remote: esutils.ObjectHash(remote)}
+	}
+
+	if len(pulled) > 0 {
+		if err := r.writeSecret(ctx, ss, secret, pulled); err != nil {
+			return res, err
+		}
+		for key, value := range pulled {
+			res.hashes[key] = esapi.SecretSyncHashes{Local: esutils.ObjectHash(value), Remote: esutils.ObjectHash(value)}
+			res.pulled = append(res.pulled, key)
+		}
+	}
+
+	// keys which were removed from the spec are forgotten.
+	for key := range res.hashes {
+		if !slices.ContainsFunc(ss.Spec.Data, func(data esapi.SecretSyncData) bool { return data.SecretKey == key }) {
+			delete(res.hashes, key)
+		}
+	}
+	return res, nil
+}
+
+// writeSecret writes the pulled values to the Secret, creating it if it does not exist.
+func (r *Reconciler) writeSecret(ctx context.Context, ss *esapi.SecretSync, secret *v1.Secret, pulled map[string][]byte) error {
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(pulled))
+	}
+	maps.Copy(secret.Data, pulled)
+	var err error
+	if secret.UID == "" {
+		err = r.Create(ctx, secret)
+	} else {
+		err = r.Update(ctx, secret)
+	}
+	if err != nil {
+		return fmt.Errorf(errWriteSecret, err)
+	}
+	return nil
+}
+
+func remoteRef(data esapi.SecretSyncData) string {
+	if data.GetProperty() != "" {
+		return data.GetRemoteKey() + "/" + data.GetProperty()
+	}
+	return data.GetRemoteKey()
+}
+
+func (r *Reconciler) setStatus(ss *esapi.SecretSync, res *syncResult) {
+	ss.Status.SyncedHashes = res.hashes
+	slices.Sort(res.conflicts)
+	ss.Status.Conflicts = res.conflicts
+	if len(res.pushed) > 0 {
+		slices.Sort(res.pushed)
+		r.recorder.Event(ss, v1.EventTypeNormal, esapi.ReasonSynced, fmt.Sprintf(msgPushed, strings.Join(res.pushed, ", ")))
+	}
+	if len(res.pulled) > 0 {
+		slices.Sort(res.pulled)
+		r.recorder.Event(ss, v1.EventTypeNormal, esapi.ReasonSynced, fmt.Sprintf(msgPulled, strings.Join(res.pulled, ", ")))
+	}
+	if len(res.resolved) > 0 {
+		slices.Sort(res.resolved)
+		r.recorder.Event(ss, v1.EventTypeNormal, esapi.ReasonConflict, fmt.Sprintf(msgConflictResolved, strings.Join(res.resolved, ", "), ss.Spec.ConflictPolicy))
+	}
+}
+
+func (r *Reconciler) markAsFailed(ss *esapi.SecretSync, msg string) {
+	SetSecretSyncCondition(ss, *NewSecretSyncCondition(esapi.SecretSyncReady, v1.ConditionFalse, esapi.ReasonErrored, msg))
+	r.recorder.Event(ss, v1.EventTypeWarning, esapi.ReasonErrored, msg)
+}
+
+func (r *Reconciler) markAsDone(ss *esapi.SecretSync, secret *v1.Secret, res *syncResult) {
+	ss.Status.RefreshTime = metav1.NewTime(time.Now())
+	ss.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(ss.ObjectMeta)
+	ss.Status.SyncedSecretResourceVersion = secret.ResourceVersion
+	if len(res.conflicts) > 0 {
+		msg := fmt.Sprintf(msgConflict, strings.Join(res.conflicts, ", "))
+		SetSecretSyncCondition(ss, *NewSecretSyncCondition(esapi.SecretSyncReady, v1.ConditionFalse, esapi.ReasonConflict, msg))
+		r.recorder.Event(ss, v1.EventTypeWarning, esapi.ReasonConflict, msg)
+		return
+	}
+	SetSecretSyncCondition(ss, *NewSecretSyncCondition(esapi.SecretSyncReady, v1.ConditionTrue, esapi.ReasonSynced, msgSynced))
+}
+
+// NewSecretSyncCondition creates a new SecretSync condition.
+func NewSecretSyncCondition(condType esapi.SecretSyncConditionType, status v1.ConditionStatus, reason, message string) *esapi.SecretSyncStatusCondition {
+	return &esapi.SecretSyncStatusCondition{
+		Type:               condType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// SetSecretSyncCondition updates the SecretSync to include the provided condition.
+func SetSecretSyncCondition(ss *esapi.SecretSync, condition esapi.SecretSyncStatusCondition) {
+	currentCond := GetSecretSyncCondition(ss.Status.Conditions, condition.Type)
+	// Do not update lastTransitionTime if the status of the condition doesn't change.
+	if currentCond != nil && currentCond.Status == condition.Status {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+	conditions := make([]esapi.SecretSyncStatusCondition, 0, len(ss.Status.Conditions))
+	for _, c := range ss.Status.Conditions {
+		if c.Type != condition.Type {
+			conditions = append(conditions, c)
+		}
+	}
+	ss.Status.Conditions = append(conditions, condition)
+}
+
+// GetSecretSyncCondition returns the condition with the provided type.
+func GetSecretSyncCondition(conditions []esapi.SecretSyncStatusCondition, condType esapi.SecretSyncConditionType) *esapi.SecretSyncStatusCondition {
+	for i := range conditions {
+		c := conditions[i]
+		if c.Type == condType {
+			return &c
+		}
+	}
+	return nil
+}
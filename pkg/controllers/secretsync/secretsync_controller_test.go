@@ -0,0 +1,211 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsync
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+// remoteStore is a provider which keeps the pushed values in memory.
+type remoteStore struct {
+	*fake.Client
+	values map[string][]byte
+}
+
+func (s *remoteStore) GetSecret(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	value, ok := s.values[ref.Key]
+	if !ok {
+		return nil, esv1.NoSecretErr
+	}
+	return value, nil
+}
+
+func (s *remoteStore) PushSecret(_ context.Context, secret *v1.Secret, data esv1.PushSecretData) error {
+	s.values[data.GetRemoteKey()] = secret.Data[data.GetSecretKey()]
+	return nil
+}
+
+func TestSyncData(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esapi.AddToScheme(scheme))
+	// the fake client does not set the UID of created objects.
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			obj.SetUID(types.UID(obj.GetName()))
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard(), Scheme: scheme, recorder: record.NewFakeRecorder(100)}
+	provider := &remoteStore{Client: fake.New(), values: map[string][]byte{"remote-b": []byte("r1")}}
+	ss := &esapi.SecretSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "default"},
+		Spec: esapi.SecretSyncSpec{
+			Target: esapi.SecretSyncTarget{Name: "target"},
+			Data: []esapi.SecretSyncData{
+				{SecretKey: "a", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote-a"}},
+				{SecretKey: "b", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote-b"}},
+				{SecretKey: "c", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote-c"}},
+			},
+		},
+	}
+	sync := func() *syncResult {
+		t.Helper()
+		secret, err := r.getTargetSecret(ctx, ss)
+		require.NoError(t, err)
+		res, err := r.syncData(ctx, ss, secret, provider)
+		require.NoError(t, err)
+		r.setStatus(ss, res)
+		r.markAsDone(ss, secret, res)
+		changed, err := r.secretChanged(ctx, ss)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		return res
+	}
+	secret := func() *v1.Secret {
+		t.Helper()
+		var s v1.Secret
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "target", Namespace: "default"}, &s))
+		return &s
+	}
+	setLocal := func(key, value string) {
+		t.Helper()
+		s := secret()
+		if s.Data == nil {
+			s.Data = map[string][]byte{}
+		}
+		s.Data[key] = []byte(value)
+		require.NoError(t, fakeClient.Update(ctx, s))
+	}
+	ready := func() *esapi.SecretSyncStatusCondition {
+		t.Helper()
+		cond := GetSecretSyncCondition(ss.Status.Conditions, esapi.SecretSyncReady)
+		require.NotNil(t, cond)
+		return cond
+	}
+
+	// values which only exist in the provider create the Secret, missing values on both sides are skipped.
+	res := sync()
+	assert.Equal(t, []string{"b"}, res.pulled)
+	assert.Equal(t, map[string][]byte{"b": []byte("r1")}, secret().Data)
+	assert.Equal(t, []string{"b"}, slices.Sorted(maps.Keys(ss.Status.SyncedHashes)))
+	assert.Equal(t, v1.ConditionTrue, ready().Status)
+
+	// values which only exist in the Secret are pushed, the change of the Secret triggers a sync.
+	setLocal("a", "l1")
+	changed, err := r.secretChanged(ctx, ss)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	res = sync()
+	assert.Equal(t, []string{"a"}, res.pushed)
+	assert.Equal(t, []byte("l1"), provider.values["remote-a"])
+
+	// unchanged values are not synced.
+	res = sync()
+	assert.Empty(t, res.pushed)
+	assert.Empty(t, res.pulled)
+
+	// the side which changed is propagated.
+	setLocal("a", "l2")
+	res = sync()
+	assert.Equal(t, []string{"a"}, res.pushed)
+	assert.Equal(t, []byte("l2"), provider.values["remote-a"])
+	provider.values["remote-a"] = []byte("r3")
+	res = sync()
+	assert.Equal(t, []string{"a"}, res.pulled)
+	assert.Equal(t, []byte("r3"), secret().Data["a"])
+
+	// with ConflictPolicy=Halt, values changed on both sides are kept.
+	setLocal("a", "l4")
+	provider.values["remote-a"] = []byte("r4")
+	res = sync()
+	assert.Equal(t, []string{"a"}, res.conflicts)
+	assert.Equal(t, []string{"a"}, ss.Status.Conflicts)
+	assert.Equal(t, []byte("l4"), secret().Data["a"])
+	assert.Equal(t, []byte("r4"), provider.values["remote-a"])
+	assert.Equal(t, v1.ConditionFalse, ready().Status)
+	assert.Equal(t, esapi.ReasonConflict, ready().Reason)
+	assert.Contains(t, ready().Message, "a")
+
+	// the conflict is resolved according to the conflict policy.
+	ss.Spec.ConflictPolicy = esapi.SecretSyncConflictPolicyPreferRemote
+	res = sync()
+	assert.Equal(t, []string{"a"}, res.resolved)
+	assert.Equal(t, []byte("r4"), secret().Data["a"])
+	assert.Empty(t, ss.Status.Conflicts)
+	assert.Equal(t, v1.ConditionTrue, ready().Status)
+
+	setLocal("a", "l5")
+	provider.values["remote-a"] = []byte("r5")
+	ss.Spec.ConflictPolicy = esapi.SecretSyncConflictPolicyPreferLocal
+	res = sync()
+	assert.Equal(t, []string{"a"}, res.resolved)
+	assert.Equal(t, []byte("l5"), provider.values["remote-a"])
+
+	// keys removed from the spec are forgotten.
+	ss.Spec.Data = ss.Spec.Data[:1]
+	sync()
+	assert.Equal(t, []string{"a"}, slices.Sorted(maps.Keys(ss.Status.SyncedHashes)))
+}
+
+func TestFindObjectsForSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, esapi.AddToScheme(scheme))
+	newSecretSync := func(name, namespace, target string) *esapi.SecretSync {
+		return &esapi.SecretSync{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       esapi.SecretSyncSpec{Target: esapi.SecretSyncTarget{Name: target}},
+		}
+	}
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&esapi.SecretSync{}, indexTargetSecretField, func(obj client.Object) []string {
+			return []string{obj.(*esapi.SecretSync).Spec.Target.Name}
+		}).
+		WithObjects(
+			newSecretSync("sync", "default", "target"),
+			newSecretSync("other-secret", "default", "other"),
+			newSecretSync("other-namespace", "other", "target"),
+		).Build()
+	r := &Reconciler{Client: fakeClient, Log: logr.Discard()}
+
+	secret := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"}}
+	requests := r.findObjectsForSecret(context.Background(), secret)
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "sync", Namespace: "default"}}}, requests)
+}
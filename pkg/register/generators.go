@@ -19,7 +19,10 @@ package register
 import (
 	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
 	acr "github.com/external-secrets/external-secrets/generators/v1/acr"
+	certificate "github.com/external-secrets/external-secrets/generators/v1/certificate"
+	chain "github.com/external-secrets/external-secrets/generators/v1/chain"
 	cloudsmith "github.com/external-secrets/external-secrets/generators/v1/cloudsmith"
+	database "github.com/external-secrets/external-secrets/generators/v1/database"
 	ecr "github.com/external-secrets/external-secrets/generators/v1/ecr"
 	fakegen "github.com/external-secrets/external-secrets/generators/v1/fake"
 	gcr "github.com/external-secrets/external-secrets/generators/v1/gcr"
@@ -38,7 +41,10 @@ import (
 func init() {
 	// Register all generators
 	genv1alpha1.Register(acr.Kind(), acr.NewGenerator())
+	genv1alpha1.Register(certificate.Kind(), certificate.NewGenerator())
+	genv1alpha1.Register(chain.Kind(), chain.NewGenerator())
 	genv1alpha1.Register(cloudsmith.Kind(), cloudsmith.NewGenerator())
+	genv1alpha1.Register(database.Kind(), database.NewGenerator())
 	genv1alpha1.Register(ecr.Kind(), ecr.NewGenerator())
 	genv1alpha1.Register(fakegen.Kind(), fakegen.NewGenerator())
 	genv1alpha1.Register(gcr.Kind(), gcr.NewGenerator())
@@ -78,7 +78,10 @@ const (
 )
 
 // https://github.com/external-secrets/external-secrets/issues/644
-var _ esv1.SecretsClient = &SecretsManager{}
+var (
+	_ esv1.SecretsClient      = &SecretsManager{}
+	_ esv1.SecretsSoftDeleter = &SecretsManager{}
+)
 
 // SecretsManager is a provider for AWS SecretsManager.
 type SecretsManager struct {
@@ -166,6 +169,16 @@ func (sm *SecretsManager) fetch(ctx context.Context, ref esv1.ExternalSecretData
 
 // DeleteSecret deletes a secret from AWS Secrets Manager.
 func (sm *SecretsManager) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	return sm.deleteSecret(ctx, remoteRef, false)
+}
+
+// SoftDeleteSecret deletes a secret from AWS Secrets Manager with a recovery window,
+// even if the store is configured to force the deletion without recovery.
+func (sm *SecretsManager) SoftDeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	return sm.deleteSecret(ctx, remoteRef, true)
+}
+
+func (sm *SecretsManager) deleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef, soft bool) error {
 	secretName := sm.prefix + remoteRef.GetRemoteKey()
 	secretValue := awssm.GetSecretValueInput{
 		SecretId: &secretName,
@@ -196,7 +209,7 @@ func (sm *SecretsManager) DeleteSecret(ctx context.Context, remoteRef esv1.PushS
 	deleteInput := &awssm.DeleteSecretInput{
 		SecretId: awsSecret.ARN,
 	}
-	if sm.config != nil && sm.config.ForceDeleteWithoutRecovery {
+	if sm.config != nil && sm.config.ForceDeleteWithoutRecovery && !soft {
 		deleteInput.ForceDeleteWithoutRecovery = &sm.config.ForceDeleteWithoutRecovery
 	}
 	if sm.config != nil && sm.config.RecoveryWindowInDays > 0 {
@@ -1796,6 +1796,40 @@ func TestDeleteSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestSoftDeleteSecret(t *testing.T) {
+	managed := managedBy
+	manager := externalSecrets
+	var deleteInput *awssm.DeleteSecretInput
+	sm := SecretsManager{
+		client: &fakesm.Client{
+			GetSecretValueFn: fakesm.NewGetSecretValueFn(&awssm.GetSecretValueOutput{ARN: new("arn")}, nil),
+			DescribeSecretFn: fakesm.NewDescribeSecretFn(&awssm.DescribeSecretOutput{
+				Tags: []types.Tag{{Key: &managed, Value: &manager}},
+			}, nil),
+			DeleteSecretFn: func(_ context.Context, input *awssm.DeleteSecretInput, _ ...func(*awssm.Options)) (*awssm.DeleteSecretOutput, error) {
+				deleteInput = input
+				return &awssm.DeleteSecretOutput{}, nil
+			},
+		},
+		config: &esv1.SecretsManager{
+			ForceDeleteWithoutRecovery: true,
+			RecoveryWindowInDays:       7,
+		},
+	}
+
+	// the recovery window of the store is kept, the forced deletion is ignored.
+	err := sm.SoftDeleteSecret(context.TODO(), fake.PushSecretData{RemoteKey: fakeKey})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleteInput.ForceDeleteWithoutRecovery != nil {
+		t.Errorf("expected no forced deletion, got %v", *deleteInput.ForceDeleteWithoutRecovery)
+	}
+	if deleteInput.RecoveryWindowInDays == nil || *deleteInput.RecoveryWindowInDays != 7 {
+		t.Errorf("expected a recovery window of 7 days, got %v", deleteInput.RecoveryWindowInDays)
+	}
+}
 func makeValidSecretStore() *esv1.SecretStore {
 	return &esv1.SecretStore{
 		ObjectMeta: metav1.ObjectMeta{
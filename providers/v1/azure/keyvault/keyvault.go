@@ -120,6 +120,7 @@ const (
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1.SecretsClient = &Azure{}
+var _ esv1.SecretsSoftDeleter = &Azure{}
 var _ esv1.Provider = &Azure{}
 
 // SecretClient is an interface to keyvault.BaseClient.
@@ -446,6 +447,12 @@ func (a *Azure) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemot
 	}
 }
 
+// SoftDeleteSecret deletes a secret from Azure Key Vault without purging it,
+// so it can be recovered within the retention period of the vault.
+func (a *Azure) SoftDeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	return a.DeleteSecret(ctx, remoteRef)
+}
+
 // SecretExists checks if a secret exists in Azure Key Vault.
 func (a *Azure) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (bool, error) {
 	if a.useNewSDK() {
@@ -302,6 +302,39 @@ func clusterGeneratorToVirtual(gen *genv1alpha1.ClusterGenerator) (client.Object
 			},
 			Spec: *gen.Spec.Generator.MFASpec,
 		}, nil
+	case genv1alpha1.GeneratorKindCertificate:
+		if gen.Spec.Generator.CertificateSpec == nil {
+			return nil, fmt.Errorf("when kind is %s, CertificateSpec must be set", gen.Spec.Kind)
+		}
+		return &genv1alpha1.Certificate{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: genv1alpha1.SchemeGroupVersion.String(),
+				Kind:       genv1alpha1.CertificateKind,
+			},
+			Spec: *gen.Spec.Generator.CertificateSpec,
+		}, nil
+	case genv1alpha1.GeneratorKindGeneratorChain:
+		if gen.Spec.Generator.GeneratorChainSpec == nil {
+			return nil, fmt.Errorf("when kind is %s, GeneratorChainSpec must be set", gen.Spec.Kind)
+		}
+		return &genv1alpha1.GeneratorChain{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: genv1alpha1.SchemeGroupVersion.String(),
+				Kind:       genv1alpha1.GeneratorChainKind,
+			},
+			Spec: *gen.Spec.Generator.GeneratorChainSpec,
+		}, nil
+	case genv1alpha1.GeneratorKindDatabaseUser:
+		if gen.Spec.Generator.DatabaseUserSpec == nil {
+			return nil, fmt.Errorf("when kind is %s, DatabaseUserSpec must be set", gen.Spec.Kind)
+		}
+		return &genv1alpha1.DatabaseUser{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: genv1alpha1.SchemeGroupVersion.String(),
+				Kind:       genv1alpha1.DatabaseUserKind,
+			},
+			Spec: *gen.Spec.Generator.DatabaseUserSpec,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown kind %s", gen.Spec.Kind)
 	}
@@ -772,6 +772,31 @@ func NamespacePredicate() predicate.Predicate {
 	}
 }
 
+// RemovedNamespaces returns the provisioned namespaces which are not part of the current namespaces.
+func RemovedNamespaces(current []corev1.Namespace, provisioned []string) []string {
+	currentSet := make(map[string]struct{}, len(current))
+	for _, namespace := range current {
+		currentSet[namespace.Name] = struct{}{}
+	}
+
+	var removed []string
+	for _, namespace := range provisioned {
+		if _, ok := currentSet[namespace]; !ok {
+			removed = append(removed, namespace)
+		}
+	}
+	return removed
+}
+
+// NamespaceFailures converts the errors of the failed namespaces to the failures of a status, sorted by namespace.
+func NamespaceFailures[T any](failed map[string]error, newFailure func(namespace, reason string) T) []T {
+	failures := make([]T, 0, len(failed))
+	for _, namespace := range slices.Sorted(maps.Keys(failed)) {
+		failures = append(failures, newFailure(namespace, failed[namespace].Error()))
+	}
+	return failures
+}
+
 func base64decode(cert []byte) ([]byte, error) {
 	if c, err := parseCertificateBytes(cert); err == nil {
 		return c, nil
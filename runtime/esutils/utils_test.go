@@ -1547,3 +1547,29 @@ func TestExtractJWTExpiration(t *testing.T) {
 		t.Errorf("Expected expiration to be '1700000000', got %s", exp)
 	}
 }
+
+func TestRemovedNamespaces(t *testing.T) {
+	current := []v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+	}
+	assert.Equal(t, []string{"b", "d"}, RemovedNamespaces(current, []string{"a", "b", "c", "d"}))
+	assert.Empty(t, RemovedNamespaces(current, []string{"a"}))
+	assert.Empty(t, RemovedNamespaces(nil, nil))
+}
+
+func TestNamespaceFailures(t *testing.T) {
+	type failure struct {
+		Namespace string
+		Reason    string
+	}
+	newFailure := func(namespace, reason string) failure {
+		return failure{Namespace: namespace, Reason: reason}
+	}
+	failed := map[string]error{
+		"b": errors.New("denied"),
+		"a": errors.New("not found"),
+	}
+	assert.Equal(t, []failure{{"a", "not found"}, {"b", "denied"}}, NamespaceFailures(failed, newFailure))
+	assert.Empty(t, NamespaceFailures(nil, newFailure))
+}
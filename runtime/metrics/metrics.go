@@ -18,6 +18,8 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -28,7 +30,9 @@ const (
 	// ExternalSecretSubsystem is the subsystem name used for external secret metrics.
 	ExternalSecretSubsystem = "externalsecret"
 
-	providerAPICalls = "provider_api_calls_count"
+	providerAPICalls          = "provider_api_calls_count"
+	providerThrottledRequests = "provider_throttled_requests_count"
+	providerThrottleWait      = "provider_throttle_wait_seconds"
 )
 
 var (
@@ -37,6 +41,19 @@ var (
 		Name:      providerAPICalls,
 		Help:      "Number of API calls towards the secret provider",
 	}, []string{"provider", "call", "status"})
+
+	throttledRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      providerThrottledRequests,
+		Help:      "Number of API calls towards the secret provider delayed by the rate limit of the store",
+	}, []string{"store_kind", "store_namespace", "store_name", "limit"})
+
+	throttleWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      providerThrottleWait,
+		Help:      "Time API calls towards the secret provider waited for the rate limit of the store",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"store_kind", "store_namespace", "store_name"})
 )
 
 // ObserveAPICall records metrics for an API call to a provider.
@@ -44,6 +61,13 @@ func ObserveAPICall(provider, call string, err error) {
 	syncCallsTotal.WithLabelValues(provider, call, deriveStatus(err)).Inc()
 }
 
+// ObserveThrottledRequest records metrics for an API call to a provider delayed by the rate limit of a store.
+// The limit is the limit that caused the delay, e.g. qps or in_flight.
+func ObserveThrottledRequest(storeKind, storeNamespace, storeName, limit string, wait time.Duration) {
+	throttledRequestsTotal.WithLabelValues(storeKind, storeNamespace, storeName, limit).Inc()
+	throttleWaitSeconds.WithLabelValues(storeKind, storeNamespace, storeName).Observe(wait.Seconds())
+}
+
 func deriveStatus(err error) string {
 	if err != nil {
 		return constants.StatusError
@@ -52,5 +76,5 @@ func deriveStatus(err error) string {
 }
 
 func init() {
-	metrics.Registry.MustRegister(syncCallsTotal)
+	metrics.Registry.MustRegister(syncCallsTotal, throttledRequestsTotal, throttleWaitSeconds)
 }
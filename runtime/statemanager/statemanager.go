@@ -127,7 +127,10 @@ func (m *Manager) EnqueueMoveStateToGC(stateKey string) {
 
 // EnqueueSetLatest sets the latest state for the given key.
 // It will commit the state on success or move the state to GC on failure.
-func (m *Manager) EnqueueSetLatest(ctx context.Context, stateKey, namespace string, resource *apiextensions.JSON, gen genapi.Generator, state genapi.GeneratorProviderState) {
+// The expiry of the generated output is recorded along with the state, it is ignored if zero.
+// Stateless generators are skipped: their renewal is driven by the status of the resource,
+// so a GeneratorState per generation would only pile up.
+func (m *Manager) EnqueueSetLatest(ctx context.Context, stateKey, namespace string, resource *apiextensions.JSON, gen genapi.Generator, state genapi.GeneratorProviderState, expiresAt time.Time) {
 	if state == nil {
 		return
 	}
@@ -135,7 +138,7 @@ func (m *Manager) EnqueueSetLatest(ctx context.Context, stateKey, namespace stri
 	m.queue = append(m.queue, QueueItem{
 		// Stores the state in GeneratorState resource
 		Commit: func() error {
-			genState, err := m.createGeneratorState(resource, state, namespace, stateKey)
+			genState, err := m.createGeneratorState(resource, state, expiresAt, namespace, stateKey)
 			if err != nil {
 				return err
 			}
@@ -149,7 +152,7 @@ func (m *Manager) EnqueueSetLatest(ctx context.Context, stateKey, namespace stri
 			if err == nil {
 				return nil
 			}
-			genState, err := m.createGeneratorState(resource, state, namespace, stateKey)
+			genState, err := m.createGeneratorState(resource, state, expiresAt, namespace, stateKey)
 			if err != nil {
 				return err
 			}
@@ -161,7 +164,7 @@ func (m *Manager) EnqueueSetLatest(ctx context.Context, stateKey, namespace stri
 	})
 }
 
-func (m *Manager) createGeneratorState(resource *apiextensions.JSON, state genapi.GeneratorProviderState, namespace, stateKey string) (*genapi.GeneratorState, error) {
+func (m *Manager) createGeneratorState(resource *apiextensions.JSON, state genapi.GeneratorProviderState, expiresAt time.Time, namespace, stateKey string) (*genapi.GeneratorState, error) {
 	genState := &genapi.GeneratorState{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("gen-%s-%s-", strings.ToLower(m.resource.GetObjectKind().GroupVersionKind().Kind), m.resource.GetName()),
@@ -178,6 +181,9 @@ func (m *Manager) createGeneratorState(resource *apiextensions.JSON, state genap
 			State:    state,
 		},
 	}
+	if !expiresAt.IsZero() {
+		genState.Spec.ExpiresAt = &metav1.Time{Time: expiresAt}
+	}
 	if err := controllerutil.SetOwnerReference(m.resource, genState, m.scheme); err != nil {
 		return nil, err
 	}